@@ -0,0 +1,94 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/khivuksergey/portmonetka.wallet/config"
+	"github.com/khivuksergey/portmonetka.wallet/internal/adapter/storage/gorm"
+	"github.com/khivuksergey/portmonetka.wallet/internal/adapter/storage/gorm/migration"
+	"os"
+	"strconv"
+	"time"
+)
+
+const usage = `usage: migrate [-config path] <command>
+
+commands:
+  up        apply all pending migrations
+  down [n]  roll back the latest n applied migrations (default 1)
+  status    list migrations and whether they are applied`
+
+func main() {
+	configPath := flag.String("config", "config.json", "path to the service configuration")
+	flag.Usage = func() { fmt.Fprintln(os.Stderr, usage) }
+	flag.Parse()
+
+	if err := run(*configPath, flag.Args()); err != nil {
+		fmt.Fprintf(os.Stderr, "migrate: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(configPath string, args []string) error {
+	if len(args) == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	cfg := config.LoadConfiguration(configPath)
+	config.LoadDBEnv(cfg.DB)
+
+	db, err := gorm.Open(cfg.DB)
+	if err != nil {
+		return err
+	}
+	if sqlDB, err := db.DB(); err == nil {
+		defer sqlDB.Close()
+	}
+
+	migrator, err := migration.NewMigrator(db)
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up()
+		printMigrations("applied", applied)
+		return err
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			if steps, err = strconv.Atoi(args[1]); err != nil || steps < 1 {
+				return fmt.Errorf("invalid number of steps %q", args[1])
+			}
+		}
+		reverted, err := migrator.Down(steps)
+		printMigrations("rolled back", reverted)
+		return err
+	case "status":
+		statuses, err := migrator.Status()
+		if err != nil {
+			return err
+		}
+		for _, status := range statuses {
+			appliedAt := "pending"
+			if status.AppliedAt != nil {
+				appliedAt = status.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Printf("%04d_%s\t%s\n", status.Version, status.Name, appliedAt)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown command %q", args[0])
+	}
+}
+
+func printMigrations(action string, migrations []migration.Migration) {
+	if len(migrations) == 0 {
+		fmt.Printf("nothing %s\n", action)
+	}
+	for _, m := range migrations {
+		fmt.Printf("%s %04d_%s\n", action, m.Version, m.Name)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"github.com/khivuksergey/webserver"
 	"github.com/spf13/viper"
+	"time"
 )
 
 type Configuration struct {
@@ -12,11 +13,54 @@ type Configuration struct {
 	Swagger *webserver.SwaggerConfig
 	Logger  *LoggerConfig
 	DB      DBConfig
+	Trash   TrashConfig
+	Outbox  OutboxConfig
+
+	ExchangeRates ExchangeRatesConfig
+	Idempotency   IdempotencyConfig
 }
 
 type DBConfig struct {
+	// Driver is "postgres" (default) or "sqlite", for which ConnectionString is the database file path or ":memory:".
+	Driver           string
 	ConnectionString string
 	TablePrefix      string
+	// MigrationMode is "check" to refuse starting with pending migrations or "auto" to apply them on startup.
+	MigrationMode string
+	// QueryTimeout aborts database statements running longer, zero disables the limit.
+	QueryTimeout time.Duration
+}
+
+type TrashConfig struct {
+	RetentionPeriod time.Duration
+	PurgeInterval   time.Duration
+}
+
+// OutboxConfig configures the relay of domain events from the outbox to the publisher,
+// which is "log" (default) to write them to the service log or "file" to append them to File as NDJSON.
+// Published events are deleted after RetentionPeriod, zero keeps them.
+type OutboxConfig struct {
+	Publisher       string
+	File            string
+	RelayInterval   time.Duration
+	BatchSize       int
+	ClaimTimeout    time.Duration
+	RetentionPeriod time.Duration
+	CleanupInterval time.Duration
+}
+
+// IdempotencyConfig configures idempotency keys. A key whose request hasn't completed within LeaseTimeout
+// is taken over by a retry, so it must exceed the longest request. Completed keys expire after TTL,
+// zero keeps them forever, and the expired ones are deleted every PurgeInterval.
+type IdempotencyConfig struct {
+	LeaseTimeout  time.Duration
+	TTL           time.Duration
+	PurgeInterval time.Duration
+}
+
+// ExchangeRatesConfig points at the ECB-style XML or CSV file with exchange rates imported on startup.
+type ExchangeRatesConfig struct {
+	File string
 }
 
 type LoggerConfig struct {
@@ -6,17 +6,32 @@ import (
 	"github.com/spf13/viper"
 )
 
-func LoadEnv() {
-	var errMsg error.ErrorMessage
+var dbEnvVars = []string{
+	"DB_USER",
+	"DB_PASSWORD",
+	"DB_NAME",
+	"DB_HOST",
+}
 
-	requiredEnvVars := []string{
-		"JWT_SECRET",
-		"JWT_ISSUER",
-		"DB_USER",
-		"DB_PASSWORD",
-		"DB_NAME",
-		"DB_HOST",
+func LoadEnv(db DBConfig) {
+	loadEnv(append([]string{"JWT_SECRET", "JWT_ISSUER"}, requiredDBEnvVars(db)...))
+}
+
+// LoadDBEnv loads only the variables required to connect to the database.
+func LoadDBEnv(db DBConfig) {
+	loadEnv(requiredDBEnvVars(db))
+}
+
+// requiredDBEnvVars returns the credentials variables, which SQLite database doesn't need.
+func requiredDBEnvVars(db DBConfig) []string {
+	if db.Driver == "sqlite" {
+		return nil
 	}
+	return dbEnvVars
+}
+
+func loadEnv(requiredEnvVars []string) {
+	var errMsg error.ErrorMessage
 
 	viper.AutomaticEnv()
 
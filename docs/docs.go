@@ -19,9 +19,1185 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/admin/exchange-rates": {
+            "post": {
+                "description": "Imports rates from an ECB eurofxref XML file, an ECB CSV file or a CSV file with the \"date,base,quote,rate\" header. Requires the admin role",
+                "consumes": [
+                    "text/xml",
+                    "text/csv"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Exchange rate"
+                ],
+                "summary": "Import exchange rates",
+                "operationId": "import-exchange-rates",
+                "responses": {
+                    "200": {
+                        "description": "Exchange rates imported",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable entity",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/currencies": {
+            "get": {
+                "description": "Gets ISO 4217 currencies and cryptocurrencies wallets can be created in, with the number of minor units",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Currency"
+                ],
+                "summary": "Get supported currencies",
+                "operationId": "get-currencies",
+                "responses": {
+                    "200": {
+                        "description": "Currencies retrieved",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{userId}/invitations": {
+            "get": {
+                "description": "Gets invitations to other users' wallets which the user hasn't accepted yet",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Wallet member"
+                ],
+                "summary": "Get user's invitations",
+                "operationId": "get-invitations",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Authorized user ID",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Invitations retrieved",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{userId}/invitations/{invitationId}": {
+            "delete": {
+                "description": "Declines and deletes the pending invitation",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Wallet member"
+                ],
+                "summary": "Decline invitation",
+                "operationId": "decline-invitation",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Authorized user ID",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Invitation ID",
+                        "name": "invitationId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No content",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Invitation not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{userId}/invitations/{invitationId}/accept": {
+            "post": {
+                "description": "Accepts the pending invitation, the wallet becomes available to the user with the invited role",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Wallet member"
+                ],
+                "summary": "Accept invitation",
+                "operationId": "accept-invitation",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Authorized user ID",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Invitation ID",
+                        "name": "invitationId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Invitation accepted",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Invitation not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{userId}/preferences": {
+            "get": {
+                "description": "Gets user's preferences, the defaults if they were never updated",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Preferences"
+                ],
+                "summary": "Get user's preferences",
+                "operationId": "get-preferences",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Authorized user ID",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Preferences retrieved",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Replaces user's preferences: base currency and default wallet used by summary and reporting endpoints, locale and the first day of month for reporting. Omitted fields are reset to defaults",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Preferences"
+                ],
+                "summary": "Update user's preferences",
+                "operationId": "update-preferences",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Authorized user ID",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "User's preferences",
+                        "name": "preferences",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.PreferencesUpdateDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Preferences updated",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "The role in the default wallet doesn't permit the operation",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Default wallet not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{userId}/transfers": {
+            "post": {
+                "description": "Atomically debits the source wallet and credits the target wallet. The source wallet defaults to the default wallet from preferences. For wallets with different currencies either target amount or rate is required",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Transfer"
+                ],
+                "summary": "Create a new transfer",
+                "operationId": "create-transfer",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Authorized user ID",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Key making the request safe to retry",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Transfer object to be created",
+                        "name": "transfer",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.TransferCreateDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Transfer created",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Wallet belongs to another user or the role doesn't permit the operation",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Source or target wallet not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Request with the same idempotency key is in progress",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable entity, code insufficient_funds if the debit would bring the balance below the wallet floor",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    }
+                }
+            }
+        },
         "/users/{userId}/wallets": {
             "get": {
-                "description": "Gets user's wallets",
+                "description": "Gets user's wallets",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Wallet"
+                ],
+                "summary": "Get user's wallets",
+                "operationId": "get-wallets",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Authorized user ID",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Currency code",
+                        "name": "currency",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "cash",
+                            "debit_card",
+                            "credit_card",
+                            "savings",
+                            "loan",
+                            "investment",
+                            "crypto"
+                        ],
+                        "type": "string",
+                        "description": "Wallet type",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Case-insensitive substring of the wallet name",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Created at or after, RFC 3339",
+                        "name": "createdFrom",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Created before, RFC 3339",
+                        "name": "createdTo",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Updated at or after, RFC 3339",
+                        "name": "updatedFrom",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Updated before, RFC 3339",
+                        "name": "updatedTo",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "name",
+                            "-name",
+                            "createdAt",
+                            "-createdAt",
+                            "updatedAt",
+                            "-updatedAt",
+                            "balance",
+                            "-balance"
+                        ],
+                        "type": "string",
+                        "default": "-updatedAt",
+                        "description": "Sort field, prefix with '-' for descending order",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Page size",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor of the next page from the previous response",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Wallets retrieved",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable entity",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Creates a new wallet with the provided information",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Wallet"
+                ],
+                "summary": "Create a new wallet",
+                "operationId": "create-wallet",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Authorized user ID",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Key making the request safe to retry",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Wallet object to be created",
+                        "name": "wallet",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.WalletCreateDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Wallet created",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Wallet with this name already exists or request with the same idempotency key is in progress",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable entity",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{userId}/wallets/summary": {
+            "get": {
+                "description": "Converts balances of all user's wallets to the base currency with the exchange rates effective on the date and sums them up",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Wallet"
+                ],
+                "summary": "Get wallets summary",
+                "operationId": "get-wallets-summary",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Authorized user ID",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Base currency code, the one from user's preferences by default",
+                        "name": "base",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Date of the exchange rates, YYYY-MM-DD, today by default",
+                        "name": "date",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Wallets summary retrieved",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable entity",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{userId}/wallets/trash": {
+            "get": {
+                "description": "Gets user's soft-deleted wallets which weren't purged yet",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Trash"
+                ],
+                "summary": "Get user's deleted wallets",
+                "operationId": "get-deleted-wallets",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Authorized user ID",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Deleted wallets retrieved",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable entity",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{userId}/wallets/trash/{walletId}": {
+            "delete": {
+                "description": "Permanently deletes soft-deleted wallet and its transactions",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Trash"
+                ],
+                "summary": "Purge deleted wallet",
+                "operationId": "purge-wallet",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Authorized user ID",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Wallet ID",
+                        "name": "walletId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No content",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Wallet belongs to another user",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Deleted wallet not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable entity",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{userId}/wallets/trash/{walletId}/restore": {
+            "post": {
+                "description": "Restores soft-deleted wallet if there is no other wallet with the same name",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Trash"
+                ],
+                "summary": "Restore deleted wallet",
+                "operationId": "restore-wallet",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Authorized user ID",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Wallet ID",
+                        "name": "walletId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Wallet restored",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Wallet belongs to another user",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Deleted wallet not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Wallet with this name already exists",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable entity",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{userId}/wallets/{walletId}": {
+            "get": {
+                "description": "Gets user's wallet by the provided wallet ID. Supports conditional requests with If-None-Match",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Wallet"
+                ],
+                "summary": "Get user's wallet",
+                "operationId": "get-wallet",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Authorized user ID",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Wallet ID",
+                        "name": "walletId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the cached wallet",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Wallet retrieved",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "304": {
+                        "description": "Not modified",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Wallet belongs to another user",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Wallet not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable entity",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Deletes wallet by the provided wallet ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Wallet"
+                ],
+                "summary": "Delete wallet",
+                "operationId": "delete-wallet",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Authorized user ID",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Wallet ID",
+                        "name": "walletId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the wallet version being deleted",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Wallet delete request",
+                        "name": "wallet",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.WalletDeleteDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No content",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Wallet belongs to another user or the role doesn't permit the operation",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Wallet not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition failed",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable entity",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Updates wallet's properties",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Wallet"
+                ],
+                "summary": "Update wallet",
+                "operationId": "update-wallet",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Authorized user ID",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Wallet ID",
+                        "name": "walletId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the wallet version being updated",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Wallet update attributes",
+                        "name": "wallet",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.WalletUpdateDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Wallet updated",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Wallet belongs to another user or the role doesn't permit the operation",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Wallet not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Wallet with this name already exists",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition failed",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable entity",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{userId}/wallets/{walletId}/history": {
+            "get": {
+                "description": "Gets who created, updated, deleted and restored the wallet and when, with the values of the changed fields before and after each change, oldest first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Wallet"
+                ],
+                "summary": "Get wallet history",
+                "operationId": "get-wallet-history",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Authorized user ID",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Wallet ID",
+                        "name": "walletId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Wallet history retrieved",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Wallet belongs to another user",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Wallet not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable entity",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{userId}/wallets/{walletId}/members": {
+            "get": {
+                "description": "Gets members and pending invitations of the wallet, available to any member. The owner who created the wallet is not listed",
                 "consumes": [
                     "application/json"
                 ],
@@ -29,10 +1205,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Wallet"
+                    "Wallet member"
                 ],
-                "summary": "Get user's wallets",
-                "operationId": "get-wallets",
+                "summary": "Get wallet members",
+                "operationId": "get-wallet-members",
                 "parameters": [
                     {
                         "type": "integer",
@@ -40,25 +1216,341 @@ const docTemplate = `{
                         "name": "userId",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Wallet ID",
+                        "name": "walletId",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Wallets retrieved",
+                        "description": "Wallet members retrieved",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Wallet belongs to another user or the role doesn't permit the operation",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Wallet not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Invites the user to the wallet with the editor or viewer role. Requires the owner role. The invitee gets access after accepting the invitation",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Wallet member"
+                ],
+                "summary": "Invite wallet member",
+                "operationId": "invite-wallet-member",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Authorized user ID",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Wallet ID",
+                        "name": "walletId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Key making the request safe to retry",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Wallet member invite request",
+                        "name": "member",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.WalletMemberInviteDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Wallet member invited",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Wallet belongs to another user or the role doesn't permit the operation",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Wallet not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "User is already a member of the wallet or invited to it",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{userId}/wallets/{walletId}/members/{memberId}": {
+            "delete": {
+                "description": "Removes the member or cancels the invitation. Requires the owner role unless members remove themselves",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Wallet member"
+                ],
+                "summary": "Remove wallet member",
+                "operationId": "remove-wallet-member",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Authorized user ID",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Wallet ID",
+                        "name": "walletId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Wallet member ID",
+                        "name": "memberId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No content",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Wallet belongs to another user or the role doesn't permit the operation",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Wallet or member not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{userId}/wallets/{walletId}/transactions": {
+            "get": {
+                "description": "Gets transactions posted to the user's wallet",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Transaction"
+                ],
+                "summary": "Get wallet's transactions",
+                "operationId": "get-transactions",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Authorized user ID",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Wallet ID",
+                        "name": "walletId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Transactions retrieved",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Wallet belongs to another user or the role doesn't permit the operation",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Wallet not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Posts a new incoming or outgoing transaction to the user's wallet",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Transaction"
+                ],
+                "summary": "Create a new transaction",
+                "operationId": "create-transaction",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Authorized user ID",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Wallet ID",
+                        "name": "walletId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Key making the request safe to retry",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Transaction object to be created",
+                        "name": "transaction",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.TransactionCreateDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Transaction created",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Wallet belongs to another user or the role doesn't permit the operation",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Wallet not found",
                         "schema": {
-                            "$ref": "#/definitions/model.Response"
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Request with the same idempotency key is in progress",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
                         }
                     },
                     "422": {
-                        "description": "Unprocessable entity",
+                        "description": "Unprocessable entity, code insufficient_funds if the debit would bring the balance below the wallet floor",
                         "schema": {
-                            "$ref": "#/definitions/model.Response"
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
                         }
                     }
                 }
-            },
-            "post": {
-                "description": "Creates a new wallet with the provided information",
+            }
+        },
+        "/users/{userId}/wallets/{walletId}/transactions/{transactionId}": {
+            "get": {
+                "description": "Gets transaction by the provided transaction ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -66,10 +1558,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Wallet"
+                    "Transaction"
                 ],
-                "summary": "Create a new wallet",
-                "operationId": "create-wallet",
+                "summary": "Get transaction",
+                "operationId": "get-transaction",
                 "parameters": [
                     {
                         "type": "integer",
@@ -79,40 +1571,49 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Wallet object to be created",
-                        "name": "wallet",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/model.WalletCreateDTO"
-                        }
+                        "type": "integer",
+                        "description": "Wallet ID",
+                        "name": "walletId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Transaction ID",
+                        "name": "transactionId",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Wallet created",
+                    "200": {
+                        "description": "Transaction retrieved",
                         "schema": {
                             "$ref": "#/definitions/model.Response"
                         }
                     },
-                    "400": {
-                        "description": "Bad request",
+                    "403": {
+                        "description": "Wallet belongs to another user or the role doesn't permit the operation",
                         "schema": {
-                            "$ref": "#/definitions/model.Response"
+                            "$ref": "#/definitions/model.Problem"
                         }
                     },
-                    "422": {
-                        "description": "Unprocessable entity",
+                    "404": {
+                        "description": "Wallet or transaction not found",
                         "schema": {
-                            "$ref": "#/definitions/model.Response"
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
                         }
                     }
                 }
-            }
-        },
-        "/users/{userId}/wallets/{walletId}": {
+            },
             "delete": {
-                "description": "Deletes wallet by the provided wallet ID",
+                "description": "Deletes transaction by the provided transaction ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -120,10 +1621,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Wallet"
+                    "Transaction"
                 ],
-                "summary": "Delete wallet",
-                "operationId": "delete-wallet",
+                "summary": "Delete transaction",
+                "operationId": "delete-transaction",
                 "parameters": [
                     {
                         "type": "integer",
@@ -133,13 +1634,18 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Wallet delete request",
-                        "name": "wallet",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/model.WalletDeleteDTO"
-                        }
+                        "type": "integer",
+                        "description": "Wallet ID",
+                        "name": "walletId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Transaction ID",
+                        "name": "transactionId",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
@@ -149,22 +1655,40 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
-                    "400": {
-                        "description": "Bad request",
+                    "403": {
+                        "description": "Wallet belongs to another user or the role doesn't permit the operation",
                         "schema": {
-                            "$ref": "#/definitions/model.Response"
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Wallet or transaction not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Transaction is a part of a transfer",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
                         }
                     },
                     "422": {
-                        "description": "Unprocessable entity",
+                        "description": "Unprocessable entity, code insufficient_funds if the debit would bring the balance below the wallet floor",
                         "schema": {
-                            "$ref": "#/definitions/model.Response"
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
                         }
                     }
                 }
             },
             "patch": {
-                "description": "Updates wallet's properties",
+                "description": "Updates transaction's properties",
                 "consumes": [
                     "application/json"
                 ],
@@ -172,10 +1696,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Wallet"
+                    "Transaction"
                 ],
-                "summary": "Update wallet",
-                "operationId": "update-wallet",
+                "summary": "Update transaction",
+                "operationId": "update-transaction",
                 "parameters": [
                     {
                         "type": "integer",
@@ -185,18 +1709,32 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Wallet update attributes",
-                        "name": "wallet",
+                        "type": "integer",
+                        "description": "Wallet ID",
+                        "name": "walletId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Transaction ID",
+                        "name": "transactionId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Transaction update attributes",
+                        "name": "transaction",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.WalletUpdateDTO"
+                            "$ref": "#/definitions/model.TransactionUpdateDTO"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Wallet updated",
+                        "description": "Transaction updated",
                         "schema": {
                             "$ref": "#/definitions/model.Response"
                         }
@@ -204,13 +1742,37 @@ const docTemplate = `{
                     "400": {
                         "description": "Bad request",
                         "schema": {
-                            "$ref": "#/definitions/model.Response"
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Wallet belongs to another user or the role doesn't permit the operation",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Wallet or transaction not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Transaction is a part of a transfer",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
                         }
                     },
                     "422": {
-                        "description": "Unprocessable entity",
+                        "description": "Unprocessable entity, code insufficient_funds if the debit would bring the balance below the wallet floor",
                         "schema": {
-                            "$ref": "#/definitions/model.Response"
+                            "$ref": "#/definitions/model.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Problem"
                         }
                     }
                 }
@@ -218,18 +1780,197 @@ const docTemplate = `{
         }
     },
     "definitions": {
+        "model.FieldError": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.PreferencesUpdateDTO": {
+            "type": "object",
+            "properties": {
+                "baseCurrency": {
+                    "type": "string"
+                },
+                "defaultWalletId": {
+                    "type": "integer"
+                },
+                "locale": {
+                    "type": "string"
+                },
+                "monthStartDay": {
+                    "type": "integer",
+                    "maximum": 28,
+                    "minimum": 1
+                },
+                "userId": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.Problem": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "detail": {
+                    "type": "string"
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.FieldError"
+                    }
+                },
+                "field": {
+                    "type": "string"
+                },
+                "instance": {
+                    "type": "string"
+                },
+                "request_uuid": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
         "model.Response": {
             "type": "object",
             "properties": {
+                "code": {
+                    "type": "string"
+                },
                 "data": {},
                 "message": {
                     "type": "string"
                 },
+                "nextCursor": {
+                    "type": "string"
+                },
                 "request_uuid": {
                     "type": "string"
                 }
             }
         },
+        "model.TransactionCreateDTO": {
+            "type": "object",
+            "required": [
+                "amount",
+                "direction"
+            ],
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "category": {
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "direction": {
+                    "type": "string",
+                    "enum": [
+                        "in",
+                        "out"
+                    ]
+                },
+                "note": {
+                    "type": "string",
+                    "maxLength": 256
+                },
+                "timestamp": {
+                    "type": "string"
+                },
+                "userId": {
+                    "type": "integer"
+                },
+                "walletId": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.TransactionUpdateDTO": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "category": {
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "direction": {
+                    "type": "string",
+                    "enum": [
+                        "in",
+                        "out"
+                    ]
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "note": {
+                    "type": "string",
+                    "maxLength": 256
+                },
+                "timestamp": {
+                    "type": "string"
+                },
+                "userId": {
+                    "type": "integer"
+                },
+                "walletId": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.TransferCreateDTO": {
+            "type": "object",
+            "required": [
+                "amount",
+                "targetWalletId"
+            ],
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "note": {
+                    "type": "string",
+                    "maxLength": 256
+                },
+                "rate": {
+                    "type": "number"
+                },
+                "sourceWalletId": {
+                    "type": "integer"
+                },
+                "targetAmount": {
+                    "type": "number"
+                },
+                "targetWalletId": {
+                    "type": "integer"
+                },
+                "timestamp": {
+                    "type": "string"
+                },
+                "userId": {
+                    "type": "integer"
+                }
+            }
+        },
         "model.WalletCreateDTO": {
             "type": "object",
             "required": [
@@ -237,17 +1978,46 @@ const docTemplate = `{
                 "name"
             ],
             "properties": {
+                "allowNegative": {
+                    "type": "boolean"
+                },
+                "creditLimit": {
+                    "type": "number"
+                },
                 "currency": {
                     "type": "string"
                 },
                 "description": {
-                    "type": "string"
+                    "type": "string",
+                    "maxLength": 256
                 },
                 "initialAmount": {
                     "type": "number"
                 },
+                "interestRate": {
+                    "type": "number"
+                },
                 "name": {
-                    "type": "string"
+                    "type": "string",
+                    "maxLength": 128,
+                    "minLength": 3
+                },
+                "statementDay": {
+                    "type": "integer",
+                    "maximum": 31,
+                    "minimum": 1
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "cash",
+                        "debit_card",
+                        "credit_card",
+                        "savings",
+                        "loan",
+                        "investment",
+                        "crypto"
+                    ]
                 },
                 "userId": {
                     "type": "integer"
@@ -265,14 +2035,46 @@ const docTemplate = `{
                 }
             }
         },
+        "model.WalletMemberInviteDTO": {
+            "type": "object",
+            "required": [
+                "inviteeId",
+                "role"
+            ],
+            "properties": {
+                "inviteeId": {
+                    "type": "integer"
+                },
+                "role": {
+                    "type": "string",
+                    "enum": [
+                        "editor",
+                        "viewer"
+                    ]
+                },
+                "userId": {
+                    "type": "integer"
+                },
+                "walletId": {
+                    "type": "integer"
+                }
+            }
+        },
         "model.WalletUpdateDTO": {
             "type": "object",
             "properties": {
+                "allowNegative": {
+                    "type": "boolean"
+                },
+                "creditLimit": {
+                    "type": "number"
+                },
                 "currency": {
                     "type": "string"
                 },
                 "description": {
-                    "type": "string"
+                    "type": "string",
+                    "maxLength": 256
                 },
                 "id": {
                     "type": "integer"
@@ -280,8 +2082,30 @@ const docTemplate = `{
                 "initialAmount": {
                     "type": "number"
                 },
+                "interestRate": {
+                    "type": "number"
+                },
                 "name": {
-                    "type": "string"
+                    "type": "string",
+                    "maxLength": 128,
+                    "minLength": 3
+                },
+                "statementDay": {
+                    "type": "integer",
+                    "maximum": 31,
+                    "minimum": 1
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "cash",
+                        "debit_card",
+                        "credit_card",
+                        "savings",
+                        "loan",
+                        "investment",
+                        "crypto"
+                    ]
                 },
                 "userId": {
                     "type": "integer"
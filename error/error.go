@@ -3,16 +3,85 @@ package error
 import (
 	"errors"
 	"fmt"
+	"github.com/shopspring/decimal"
+	"net/http"
+)
+
+// ServiceError is an error with a stable machine-readable code, the HTTP status it is reported with
+// and, if it is caused by a single input field, the name of that field. Clients should match the code,
+// as messages may change.
+type ServiceError struct {
+	Code    string
+	Status  int
+	Field   string
+	Message string
+}
+
+func newError(code string, status int, message string) *ServiceError {
+	return &ServiceError{Code: code, Status: status, Message: message}
+}
+
+func newFieldError(code string, status int, field, message string) *ServiceError {
+	return &ServiceError{Code: code, Status: status, Field: field, Message: message}
+}
+
+func (e *ServiceError) Error() string {
+	return e.Message
+}
+
+var (
+	WalletAlreadyExists                = newFieldError("wallet_already_exists", http.StatusConflict, "name", "wallet with this name already exists")
+	WalletDoesntExist                  = newError("wallet_not_found", http.StatusNotFound, "wallet with this id doesn't exists")
+	WalletDoesntBelongToUser           = newError("wallet_forbidden", http.StatusForbidden, "wallet with this id doesn't belong to user")
+	WalletPermissionDenied             = newError("wallet_permission_denied", http.StatusForbidden, "user's role in the wallet doesn't permit the operation")
+	WalletMemberAlreadyExists          = newFieldError("wallet_member_already_exists", http.StatusConflict, "inviteeId", "user is already a member of the wallet or invited to it")
+	WalletMemberDoesntExist            = newError("wallet_member_not_found", http.StatusNotFound, "wallet member with this id doesn't exist")
+	WalletOwnerInvited                 = newFieldError("wallet_owner_invited", http.StatusUnprocessableEntity, "inviteeId", "wallet owner can't be invited to the own wallet")
+	InvitationDoesntExist              = newError("invitation_not_found", http.StatusNotFound, "pending invitation with this id doesn't exist")
+	DeletedWalletDoesntExist           = newError("deleted_wallet_not_found", http.StatusNotFound, "deleted wallet with this id doesn't exist")
+	WalletCurrencyError                = newFieldError("invalid_currency", http.StatusBadRequest, "currency", "wallet currency must be a supported currency code")
+	WalletAmountPrecisionError         = newFieldError("invalid_amount_precision", http.StatusBadRequest, "initialAmount", "initial amount has more decimal places than the wallet currency allows")
+	WalletVersionMismatch              = newError("wallet_version_mismatch", http.StatusPreconditionFailed, "wallet was modified by another request")
+	WalletTypeError                    = newFieldError("invalid_wallet_type", http.StatusBadRequest, "type", "wallet type must be one of cash, debit_card, credit_card, savings, loan, investment, crypto")
+	WalletTypeAttributeError           = newError("unsupported_wallet_attribute", http.StatusUnprocessableEntity, "attribute is not supported by the wallet type")
+	WalletCreditCardError              = newError("credit_card_attributes_required", http.StatusUnprocessableEntity, "credit card wallet requires credit limit and statement day")
+	WalletCreditLimitError             = newFieldError("invalid_credit_limit", http.StatusBadRequest, "creditLimit", "credit limit must not be negative and must fit the currency minor units")
+	WalletStatementDayError            = newFieldError("invalid_statement_day", http.StatusBadRequest, "statementDay", "statement day must be from 1 to 31")
+	WalletInterestRateError            = newFieldError("invalid_interest_rate", http.StatusBadRequest, "interestRate", "interest rate must be from 0 to 100 percent")
+	InsufficientFunds                  = newError(InsufficientFundsCode, http.StatusUnprocessableEntity, "insufficient funds")
+	InvalidWalletCursor                = newFieldError("invalid_cursor", http.StatusBadRequest, "cursor", "invalid wallet list cursor")
+	InvalidWalletSort                  = newFieldError("invalid_sort", http.StatusBadRequest, "sort", "invalid wallet list sort field")
+	InvalidEntityTag                   = newFieldError("invalid_entity_tag", http.StatusBadRequest, "If-Match", "invalid entity tag")
+	TransactionDoesntExist             = newError("transaction_not_found", http.StatusNotFound, "transaction with this id doesn't exist in wallet")
+	TransactionAmountError             = newFieldError("invalid_transaction_amount", http.StatusBadRequest, "amount", "transaction amount must be positive")
+	TransactionAmountPrecisionError    = newFieldError("invalid_amount_precision", http.StatusBadRequest, "amount", "transaction amount has more decimal places than the wallet currency allows")
+	AtLeastOneTransactionField         = newError("update_fields_required", http.StatusBadRequest, "at least one field for updating transaction is required")
+	TransactionIsTransferPart          = newError("transaction_is_transfer_part", http.StatusConflict, "transaction is part of a transfer and cannot be changed separately")
+	TransferSourceWalletRequired       = newFieldError("transfer_source_wallet_required", http.StatusBadRequest, "sourceWalletId", "source wallet is required if there is no default wallet in preferences")
+	TransferSameWallet                 = newFieldError("transfer_same_wallet", http.StatusBadRequest, "targetWalletId", "transfer source and target wallets must be different")
+	TransferAmountError                = newFieldError("invalid_transfer_amount", http.StatusBadRequest, "amount", "transfer amount must be positive")
+	TransferAmountPrecisionError       = newFieldError("invalid_amount_precision", http.StatusBadRequest, "amount", "transfer amount has more decimal places than the source wallet currency allows")
+	TransferTargetAmountPrecisionError = newFieldError("invalid_amount_precision", http.StatusBadRequest, "targetAmount", "target amount has more decimal places than the target wallet currency allows")
+	TransferRateError                  = newFieldError("invalid_transfer_rate", http.StatusBadRequest, "rate", "transfer rate must be positive")
+	TransferRateRequired               = newError("transfer_rate_required", http.StatusBadRequest, "target amount or rate is required for transfer between different currencies")
+	TransferRateAmbiguous              = newError("transfer_rate_ambiguous", http.StatusBadRequest, "either target amount or rate must be provided, not both")
+	TransferRateNotAllowed             = newError("transfer_rate_not_allowed", http.StatusBadRequest, "target amount and rate are allowed only for transfer between different currencies")
+	IdempotencyKeyLengthError          = newFieldError("invalid_idempotency_key", http.StatusBadRequest, "Idempotency-Key", "idempotency key must be from 1 to 255 symbols long")
+	IdempotencyKeyReused               = newFieldError("idempotency_key_reused", http.StatusUnprocessableEntity, "Idempotency-Key", "idempotency key was already used with a different request")
+	IdempotencyKeyInProgress           = newFieldError("idempotency_key_in_progress", http.StatusConflict, "Idempotency-Key", "request with this idempotency key is still in progress")
+	ExchangeRateFormatError            = newError("invalid_exchange_rate_format", http.StatusBadRequest, "exchange rates format must be xml or csv")
+	ExchangeRateParseError             = newError("invalid_exchange_rates", http.StatusUnprocessableEntity, "cannot parse exchange rates")
+	ExchangeRateError                  = newError("invalid_exchange_rate", http.StatusUnprocessableEntity, "exchange rate must be positive")
+	ExchangeRateNotFound               = newError("exchange_rate_not_found", http.StatusUnprocessableEntity, "no exchange rate for the currency on the date")
+	BaseCurrencyRequired               = newFieldError("base_currency_required", http.StatusBadRequest, "base", "base currency is required unless it is set in user preferences")
 )
 
 var (
-	WalletAlreadyExists          = errors.New("wallet with this name already exists")
-	WalletDoesntExist            = errors.New("wallet with this id doesn't exists")
-	WalletDoesntBelongToUser     = errors.New("wallet with this id doesn't belong to user")
-	AtLeastOneFieldIsRequired    = errors.New("at least one field for updating wallet is required")
-	WalletNameLengthError        = errors.New("wallet name must be from 3 to 128 symbols long")
-	WalletDescriptionLengthError = errors.New("wallet description must be less than 256 symbols long")
-	WalletCurrencyError          = errors.New("wallet currency must be 3 symbols long")
+	MigrationsPending       = errors.New("database has pending migrations")
+	UnknownMigrationApplied = errors.New("database has migrations unknown to this version")
+	InvalidMigrationMode    = errors.New("invalid migration mode")
+	UnknownDatabaseDriver   = errors.New("unknown database driver")
+	UnknownEventPublisher   = errors.New("unknown event publisher")
 )
 
 const (
@@ -21,8 +90,60 @@ const (
 	CannotGetWallets   = "cannot retrieve wallets"
 	CannotUpdateWallet = "cannot update wallet"
 	CannotDeleteWallet = "cannot delete wallet"
+
+	CannotGetWalletHistory = "cannot retrieve wallet history"
+
+	CannotGetDeletedWallets = "cannot retrieve deleted wallets"
+	CannotRestoreWallet     = "cannot restore wallet"
+	CannotPurgeWallet       = "cannot purge wallet"
+
+	CannotCreateTransaction = "cannot create transaction"
+	CannotGetTransactions   = "cannot retrieve transactions"
+	CannotUpdateTransaction = "cannot update transaction"
+	CannotDeleteTransaction = "cannot delete transaction"
+
+	CannotCreateTransfer = "cannot create transfer"
+
+	CannotProcessIdempotentRequest = "cannot process idempotent request"
+
+	CannotImportExchangeRates = "cannot import exchange rates"
+	CannotGetWalletsSummary   = "cannot retrieve wallets summary"
+
+	CannotGetWalletMembers   = "cannot retrieve wallet members"
+	CannotInviteWalletMember = "cannot invite wallet member"
+	CannotRemoveWalletMember = "cannot remove wallet member"
+	CannotGetInvitations     = "cannot retrieve invitations"
+	CannotAcceptInvitation   = "cannot accept invitation"
+	CannotDeclineInvitation  = "cannot decline invitation"
+
+	CannotGetPreferences    = "cannot retrieve preferences"
+	CannotUpdatePreferences = "cannot update preferences"
 )
 
+// InsufficientFundsCode is the machine-readable code of responses to debits rejected with InsufficientFunds.
+const InsufficientFundsCode = "insufficient_funds"
+
+// InsufficientFundsError is returned when a debit would bring the wallet balance below the floor
+// permitted by the wallet settings. It wraps InsufficientFunds, so it is reported with its code and status.
+type InsufficientFundsError struct {
+	WalletId uint64          `json:"walletId"`
+	Balance  decimal.Decimal `json:"balance"`
+	Floor    decimal.Decimal `json:"floor"`
+}
+
+func NewInsufficientFundsError(walletId uint64, balance, floor decimal.Decimal) *InsufficientFundsError {
+	return &InsufficientFundsError{WalletId: walletId, Balance: balance, Floor: floor}
+}
+
+func (e *InsufficientFundsError) Error() string {
+	return fmt.Sprintf("%v: balance of wallet %d would be %s, below the permitted %s",
+		InsufficientFunds, e.WalletId, e.Balance, e.Floor)
+}
+
+func (e *InsufficientFundsError) Unwrap() error {
+	return InsufficientFunds
+}
+
 type ErrorMessage string
 
 func (m *ErrorMessage) Append(errMessage string) {
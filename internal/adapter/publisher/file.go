@@ -0,0 +1,46 @@
+package publisher
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/khivuksergey/portmonetka.wallet/internal/adapter/storage/entity"
+	"github.com/khivuksergey/portmonetka.wallet/internal/core/port/event"
+	"os"
+	"sync"
+)
+
+// filePublisher appends events to a file as newline-delimited JSON, one event per line,
+// e.g. for local runs and tests of consumers.
+type filePublisher struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+// NewFilePublisher opens the file for appending, creating it if it doesn't exist.
+func NewFilePublisher(path string) (event.Publisher, error) {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	return &filePublisher{file: file}, nil
+}
+
+func (p *filePublisher) Publish(ctx context.Context, outboxEvent entity.OutboxEvent) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	line, err := json.Marshal(outboxEvent)
+	if err != nil {
+		return err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	_, err = p.file.Write(append(line, '\n'))
+	return err
+}
+
+func (p *filePublisher) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.file.Close()
+}
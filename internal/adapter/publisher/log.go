@@ -0,0 +1,30 @@
+package publisher
+
+import (
+	"context"
+	"github.com/khivuksergey/portmonetka.wallet/internal/adapter/storage/entity"
+	"github.com/khivuksergey/portmonetka.wallet/internal/core/port/event"
+	"github.com/khivuksergey/webserver/logger"
+)
+
+// logPublisher writes events to the service log at the info level, for deployments without a broker.
+type logPublisher struct {
+	logger logger.Logger
+}
+
+func NewLogPublisher(logger logger.Logger) event.Publisher {
+	return &logPublisher{logger: logger}
+}
+
+func (p *logPublisher) Publish(ctx context.Context, outboxEvent entity.OutboxEvent) error {
+	p.logger.Info(logger.LogMessage{
+		Action:  "PublishEvent",
+		Message: outboxEvent.Type + " event published",
+		Data:    outboxEvent,
+	})
+	return nil
+}
+
+func (p *logPublisher) Close() error {
+	return nil
+}
@@ -0,0 +1,26 @@
+package publisher
+
+import (
+	"fmt"
+	"github.com/khivuksergey/portmonetka.wallet/config"
+	serviceerror "github.com/khivuksergey/portmonetka.wallet/error"
+	"github.com/khivuksergey/portmonetka.wallet/internal/core/port/event"
+	"github.com/khivuksergey/webserver/logger"
+)
+
+const (
+	PublisherLog  = "log"
+	PublisherFile = "file"
+)
+
+// NewPublisher returns the event publisher chosen by the configuration, the log publisher by default.
+func NewPublisher(cfg config.OutboxConfig, logger logger.Logger) (event.Publisher, error) {
+	switch cfg.Publisher {
+	case PublisherLog, "":
+		return NewLogPublisher(logger), nil
+	case PublisherFile:
+		return NewFilePublisher(cfg.File)
+	default:
+		return nil, fmt.Errorf("%w: %s", serviceerror.UnknownEventPublisher, cfg.Publisher)
+	}
+}
@@ -0,0 +1,19 @@
+package entity
+
+import (
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+// ExchangeRate is the price of one unit of the base currency in the quote currency on the date.
+type ExchangeRate struct {
+	Id            uint64          `json:"-" gorm:"primarykey"`
+	Date          time.Time       `json:"date" gorm:"type:date;not null;uniqueIndex:idx_exchange_rate_date_pair"`
+	BaseCurrency  string          `json:"baseCurrency" gorm:"not null;uniqueIndex:idx_exchange_rate_date_pair"`
+	QuoteCurrency string          `json:"quoteCurrency" gorm:"not null;uniqueIndex:idx_exchange_rate_date_pair"`
+	Rate          decimal.Decimal `json:"rate" gorm:"type:numeric;not null"`
+	CreatedAt     time.Time       `json:"-" gorm:"<-:create"`
+	UpdatedAt     time.Time       `json:"-"`
+}
+
+func (ExchangeRate) TableName() string { return "portmonetka.exchange_rates" }
@@ -0,0 +1,20 @@
+package entity
+
+import "time"
+
+type IdempotencyKey struct {
+	Id          uint64    `json:"id" gorm:"primarykey"`
+	UserId      uint64    `json:"userId" gorm:"not null;uniqueIndex:idx_idempotency_userid_key"`
+	Key         string    `json:"key" gorm:"column:idempotency_key;not null;uniqueIndex:idx_idempotency_userid_key"`
+	RequestHash string    `json:"requestHash" gorm:"not null"`
+	StatusCode  int       `json:"statusCode" gorm:"not null"`
+	Response    []byte    `json:"response" gorm:"null"`
+	ReservedAt  time.Time `json:"reservedAt" gorm:"not null"`
+	CreatedAt   time.Time `json:"createdAt" gorm:"<-:create"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+func (IdempotencyKey) TableName() string { return "portmonetka.idempotency_keys" }
+
+// IsPending reports whether the request with this key is still being processed.
+func (k IdempotencyKey) IsPending() bool { return k.StatusCode == 0 }
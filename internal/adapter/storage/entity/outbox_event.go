@@ -0,0 +1,28 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	WalletCreatedEvent  = "WalletCreated"
+	WalletUpdatedEvent  = "WalletUpdated"
+	WalletDeletedEvent  = "WalletDeleted"
+	WalletRestoredEvent = "WalletRestored"
+)
+
+// OutboxEvent is a domain event written in the transaction of the change it describes
+// and relayed to the event publisher afterwards. PublishedAt is nil until it is relayed,
+// ClaimedUntil is set while a relay is publishing it.
+type OutboxEvent struct {
+	Id           uint64          `json:"id" gorm:"primarykey"`
+	Type         string          `json:"type" gorm:"not null"`
+	AggregateId  uint64          `json:"aggregateId" gorm:"not null"`
+	Payload      json.RawMessage `json:"payload" gorm:"serializer:json;not null"`
+	CreatedAt    time.Time       `json:"createdAt" gorm:"<-:create"`
+	PublishedAt  *time.Time      `json:"publishedAt,omitempty"`
+	ClaimedUntil *time.Time      `json:"-"`
+}
+
+func (OutboxEvent) TableName() string { return "portmonetka.outbox_events" }
@@ -0,0 +1,16 @@
+package entity
+
+import "time"
+
+// Preferences are user-level settings used as defaults by summary and reporting endpoints.
+type Preferences struct {
+	UserId          uint64    `json:"userId" gorm:"primarykey;autoIncrement:false"`
+	BaseCurrency    string    `json:"baseCurrency"`
+	DefaultWalletId *uint64   `json:"defaultWalletId"`
+	Locale          string    `json:"locale" gorm:"not null"`
+	MonthStartDay   int       `json:"monthStartDay" gorm:"not null"`
+	CreatedAt       time.Time `json:"createdAt" gorm:"<-:create"`
+	UpdatedAt       time.Time `json:"updatedAt"`
+}
+
+func (Preferences) TableName() string { return "portmonetka.user_preferences" }
@@ -0,0 +1,36 @@
+package entity
+
+import (
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+	"time"
+)
+
+const (
+	TransactionDirectionIn  = "in"
+	TransactionDirectionOut = "out"
+)
+
+type Transaction struct {
+	Id         uint64          `json:"id" gorm:"primarykey"`
+	WalletId   uint64          `json:"walletId" gorm:"not null;index"`
+	TransferId *uint64         `json:"transferId,omitempty" gorm:"index"`
+	Amount     decimal.Decimal `json:"amount" gorm:"type:numeric;not null"`
+	Direction  string          `json:"direction" gorm:"not null"`
+	Timestamp  time.Time       `json:"timestamp" gorm:"not null;index"`
+	Note       string          `json:"note" gorm:"null"`
+	Category   string          `json:"category" gorm:"null"`
+	CreatedAt  time.Time       `json:"createdAt" gorm:"<-:create"`
+	UpdatedAt  time.Time       `json:"updatedAt"`
+	DeletedAt  gorm.DeletedAt  `json:"-" gorm:"index"`
+}
+
+func (Transaction) TableName() string { return "portmonetka.transactions" }
+
+// SignedAmount returns the change of the wallet balance made by the transaction.
+func (t Transaction) SignedAmount() decimal.Decimal {
+	if t.Direction == TransactionDirectionOut {
+		return t.Amount.Neg()
+	}
+	return t.Amount
+}
@@ -0,0 +1,24 @@
+package entity
+
+import (
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+const TransferCategory = "transfer"
+
+type Transfer struct {
+	Id             uint64          `json:"id" gorm:"primarykey"`
+	UserId         uint64          `json:"userId" gorm:"not null;index"`
+	SourceWalletId uint64          `json:"sourceWalletId" gorm:"not null"`
+	TargetWalletId uint64          `json:"targetWalletId" gorm:"not null"`
+	SourceAmount   decimal.Decimal `json:"sourceAmount" gorm:"type:numeric;not null"`
+	TargetAmount   decimal.Decimal `json:"targetAmount" gorm:"type:numeric;not null"`
+	Rate           decimal.Decimal `json:"rate" gorm:"type:numeric;not null"`
+	Timestamp      time.Time       `json:"timestamp" gorm:"not null"`
+	Note           string          `json:"note" gorm:"null"`
+	CreatedAt      time.Time       `json:"createdAt" gorm:"<-:create"`
+	Transactions   []Transaction   `json:"transactions" gorm:"foreignKey:TransferId"`
+}
+
+func (Transfer) TableName() string { return "portmonetka.transfers" }
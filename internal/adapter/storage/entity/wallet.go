@@ -2,20 +2,62 @@ package entity
 
 import (
 	"github.com/shopspring/decimal"
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
 	"gorm.io/gorm"
 	"time"
 )
 
+const (
+	WalletTypeCash       = "cash"
+	WalletTypeDebitCard  = "debit_card"
+	WalletTypeCreditCard = "credit_card"
+	WalletTypeSavings    = "savings"
+	WalletTypeLoan       = "loan"
+	WalletTypeInvestment = "investment"
+	WalletTypeCrypto     = "crypto"
+)
+
+// Wallet holds money in a single currency. CreditLimit is set for credit cards and overdrafts of debit cards,
+// StatementDay for credit cards only, InterestRate is the annual rate in percent of savings and loans.
 type Wallet struct {
-	Id            uint64          `json:"id" gorm:"primarykey"`
-	UserId        uint64          `json:"userId" gorm:"not null;uniqueIndex:idx_userid_name_deletedat" validate:"required"`
-	Name          string          `json:"name" gorm:"not null;uniqueIndex:idx_userid_name_deletedat" validate:"required,min=3,max=128"`
-	Description   string          `json:"description" gorm:"null" validate:"max=256"`
-	Currency      string          `json:"currency" gorm:"not null" validate:"required,len=3"`
-	InitialAmount decimal.Decimal `json:"initialAmount" gorm:"not null" validate:"required"`
-	CreatedAt     time.Time       `json:"createdAt" gorm:"<-:create"`
-	UpdatedAt     time.Time       `json:"updatedAt"`
-	DeletedAt     gorm.DeletedAt  `json:"-" gorm:"index;uniqueIndex:idx_userid_name_deletedat"`
+	Id             uint64           `json:"id" gorm:"primarykey"`
+	UserId         uint64           `json:"userId" gorm:"not null;uniqueIndex:idx_wallets_userid_name_key,where:deleted_at IS NULL" validate:"required"`
+	Name           string           `json:"name" gorm:"not null" validate:"required,min=3,max=128"`
+	NameKey        string           `json:"-" gorm:"not null;uniqueIndex:idx_wallets_userid_name_key"`
+	Description    string           `json:"description" gorm:"null" validate:"max=256"`
+	Currency       string           `json:"currency" gorm:"not null" validate:"required,len=3"`
+	InitialAmount  decimal.Decimal  `json:"initialAmount" gorm:"type:numeric;not null" validate:"required"`
+	Type           string           `json:"type" gorm:"not null;default:cash"`
+	CreditLimit    *decimal.Decimal `json:"creditLimit,omitempty" gorm:"type:numeric"`
+	StatementDay   *int             `json:"statementDay,omitempty"`
+	InterestRate   *decimal.Decimal `json:"interestRate,omitempty" gorm:"type:numeric"`
+	AllowNegative  bool             `json:"allowNegative" gorm:"not null;default:false"`
+	CurrentBalance decimal.Decimal  `json:"currentBalance" gorm:"->;-:migration"`
+	Version        uint64           `json:"version" gorm:"not null;default:1"`
+	CreatedAt      time.Time        `json:"createdAt" gorm:"<-:create"`
+	UpdatedAt      time.Time        `json:"updatedAt"`
+	DeletedAt      gorm.DeletedAt   `json:"-" gorm:"index"`
 }
 
 func (Wallet) TableName() string { return "portmonetka.wallets" }
+
+// WalletNameKey returns the form in which wallet names are compared by the uniqueness rule and the name filter:
+// the name case-folded and normalized to NFC, so e.g. "Straße" and "STRASSE" or the composed and decomposed
+// forms of "Ё" have the same key. It is computed in Go, as LOWER() neither folds case nor normalizes.
+func WalletNameKey(name string) string {
+	// a Caser keeps state, so it can't be shared between goroutines
+	return norm.NFC.String(cases.Fold().String(norm.NFD.String(name)))
+}
+
+// BalanceFloor returns the lowest balance the wallet is permitted to have: minus the credit limit if it is set,
+// otherwise zero. It returns false if there is no floor, i.e. the wallet may go negative without a limit.
+func (w Wallet) BalanceFloor() (decimal.Decimal, bool) {
+	if w.CreditLimit != nil {
+		return w.CreditLimit.Neg(), true
+	}
+	if w.AllowNegative {
+		return decimal.Zero, false
+	}
+	return decimal.Zero, true
+}
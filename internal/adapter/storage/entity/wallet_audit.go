@@ -0,0 +1,31 @@
+package entity
+
+import "time"
+
+const (
+	WalletAuditActionCreate  = "create"
+	WalletAuditActionUpdate  = "update"
+	WalletAuditActionDelete  = "delete"
+	WalletAuditActionRestore = "restore"
+)
+
+// WalletAudit is an append-only record of a change of the wallet made by the actor in the request.
+// Changes holds the changed fields by their JSON names. Records outlive the wallet, even if it is purged.
+type WalletAudit struct {
+	Id          uint64                 `json:"id" gorm:"primarykey"`
+	WalletId    uint64                 `json:"walletId" gorm:"not null;index"`
+	ActorId     uint64                 `json:"actorId" gorm:"not null"`
+	RequestUuid string                 `json:"requestUuid"`
+	Action      string                 `json:"action" gorm:"not null"`
+	Changes     map[string]FieldChange `json:"changes" gorm:"serializer:json;not null"`
+	CreatedAt   time.Time              `json:"createdAt" gorm:"<-:create"`
+}
+
+func (WalletAudit) TableName() string { return "portmonetka.wallet_audit_log" }
+
+// FieldChange is the value of a field before and after the change. Before is absent for created
+// and restored wallets, After for deleted ones.
+type FieldChange struct {
+	Before any `json:"before,omitempty"`
+	After  any `json:"after,omitempty"`
+}
@@ -0,0 +1,40 @@
+package entity
+
+import "time"
+
+const (
+	WalletRoleOwner  = "owner"
+	WalletRoleEditor = "editor"
+	WalletRoleViewer = "viewer"
+)
+
+var walletRoleRanks = map[string]int{
+	WalletRoleViewer: 1,
+	WalletRoleEditor: 2,
+	WalletRoleOwner:  3,
+}
+
+// WalletMember grants the user a role in another user's wallet. The membership is an invitation
+// until the invited user accepts it. The user who created the wallet is its owner without membership.
+type WalletMember struct {
+	Id         uint64     `json:"id" gorm:"primarykey"`
+	WalletId   uint64     `json:"walletId" gorm:"not null;uniqueIndex:idx_wallet_member"`
+	UserId     uint64     `json:"userId" gorm:"not null;uniqueIndex:idx_wallet_member;index"`
+	Role       string     `json:"role" gorm:"not null"`
+	InvitedBy  uint64     `json:"invitedBy" gorm:"not null"`
+	AcceptedAt *time.Time `json:"acceptedAt"`
+	CreatedAt  time.Time  `json:"createdAt" gorm:"<-:create"`
+	UpdatedAt  time.Time  `json:"updatedAt"`
+}
+
+func (WalletMember) TableName() string { return "portmonetka.wallet_members" }
+
+// IsAccepted reports whether the invited user has accepted the invitation.
+func (m WalletMember) IsAccepted() bool { return m.AcceptedAt != nil }
+
+// WalletRoleAllows reports whether the role grants at least the required one: owner includes editor,
+// editor includes viewer. An empty role, meaning no access to the wallet, allows nothing.
+func WalletRoleAllows(role, required string) bool {
+	rank, ok := walletRoleRanks[role]
+	return ok && rank >= walletRoleRanks[required]
+}
@@ -0,0 +1,53 @@
+package gorm
+
+import (
+	"errors"
+	"fmt"
+	"github.com/khivuksergey/portmonetka.wallet/internal/core/port/repository"
+	"gorm.io/gorm"
+)
+
+const (
+	repositoryErrorsName      = "repository_errors"
+	repositoryErrorsTranslate = "repository_errors:translate"
+)
+
+// repositoryErrors wraps the gorm errors of every statement into the errors of the repository port,
+// so services can check them regardless of the adapter, while the repositories can still check
+// the original gorm errors.
+type repositoryErrors struct{}
+
+var repositoryErrorTranslations = []struct {
+	gormError, repositoryError error
+}{
+	{gorm.ErrRecordNotFound, repository.ErrNotFound},
+	{gorm.ErrDuplicatedKey, repository.ErrDuplicatedKey},
+	{gorm.ErrForeignKeyViolated, repository.ErrForeignKeyViolated},
+}
+
+func (r repositoryErrors) Name() string {
+	return repositoryErrorsName
+}
+
+func (r repositoryErrors) Initialize(db *gorm.DB) error {
+	callbacks := db.Callback()
+	return errors.Join(
+		callbacks.Create().After("*").Register(repositoryErrorsTranslate, r.translate),
+		callbacks.Query().After("*").Register(repositoryErrorsTranslate, r.translate),
+		callbacks.Update().After("*").Register(repositoryErrorsTranslate, r.translate),
+		callbacks.Delete().After("*").Register(repositoryErrorsTranslate, r.translate),
+		callbacks.Raw().After("*").Register(repositoryErrorsTranslate, r.translate),
+	)
+}
+
+func (r repositoryErrors) translate(db *gorm.DB) {
+	if db.Error == nil {
+		return
+	}
+	for _, translation := range repositoryErrorTranslations {
+		if errors.Is(db.Error, translation.gormError) && !errors.Is(db.Error, translation.repositoryError) {
+			db.Error = fmt.Errorf("%w: %w", translation.repositoryError, db.Error)
+			return
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package migration
+
+import (
+	"github.com/khivuksergey/portmonetka.wallet/internal/adapter/storage/entity"
+	"gorm.io/gorm"
+)
+
+// dataMigrationBatchSize limits the number of rows a data migration loads at once.
+const dataMigrationBatchSize = 500
+
+// dataMigrations are the changes of data which can't be expressed in SQL, by the version of the migration
+// they belong to. Each runs after the up statements of its migration, in the same transaction.
+var dataMigrations = map[uint64]func(tx *gorm.DB) error{
+	14: backfillWalletNameKeys,
+}
+
+// backfillWalletNameKeys fills name_key of every wallet, including deleted ones, with the key
+// the application computes, so the unique index created on it covers the existing names.
+func backfillWalletNameKeys(tx *gorm.DB) error {
+	table := entity.Wallet{}.TableName()
+	var lastId uint64
+	for {
+		var wallets []struct {
+			Id   uint64
+			Name string
+		}
+		err := tx.Table(table).
+			Select("id, name").
+			Where("id > ?", lastId).
+			Order("id").
+			Limit(dataMigrationBatchSize).
+			Find(&wallets).Error
+		if err != nil || len(wallets) == 0 {
+			return err
+		}
+		for _, wallet := range wallets {
+			err = tx.Table(table).Where("id = ?", wallet.Id).Update("name_key", entity.WalletNameKey(wallet.Name)).Error
+			if err != nil {
+				return err
+			}
+		}
+		lastId = wallets[len(wallets)-1].Id
+	}
+}
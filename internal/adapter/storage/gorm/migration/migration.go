@@ -0,0 +1,293 @@
+package migration
+
+import (
+	"embed"
+	"fmt"
+	serviceerror "github.com/khivuksergey/portmonetka.wallet/error"
+	"gorm.io/gorm"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+)
+
+const (
+	ModeCheck = "check"
+	ModeAuto  = "auto"
+
+	bootstrapFile  = "bootstrap.sql"
+	migrationTable = "portmonetka.schema_migrations"
+
+	// migrationLockId is the key of the Postgres advisory lock held while migrating.
+	migrationLockId int64 = 0x706f72746d6f6e // "portmon"
+)
+
+//go:embed sql
+var migrationFiles embed.FS
+
+var migrationFileName = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// Migration is a single schema change with the statements applying and reverting it.
+type Migration struct {
+	Version uint64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Status describes whether the migration has been applied to the database.
+type Status struct {
+	Version   uint64
+	Name      string
+	AppliedAt *time.Time
+}
+
+type appliedMigration struct {
+	Version   uint64
+	Name      string
+	AppliedAt time.Time
+}
+
+// Migrator applies the migration set embedded for the database dialect
+// and keeps track of applied versions in the schema_migrations table.
+type Migrator struct {
+	db         *gorm.DB
+	bootstrap  string
+	migrations []Migration
+}
+
+func NewMigrator(db *gorm.DB) (*Migrator, error) {
+	dir := path.Join("sql", db.Dialector.Name())
+	bootstrap, err := fs.ReadFile(migrationFiles, path.Join(dir, bootstrapFile))
+	if err != nil {
+		return nil, fmt.Errorf("no migrations for %s dialect: %w", db.Dialector.Name(), err)
+	}
+	migrations, err := LoadMigrations(migrationFiles, dir)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, bootstrap: string(bootstrap), migrations: migrations}, nil
+}
+
+// LoadMigrations reads migrations from the directory, ordered by version.
+// Every version must have both up and down files.
+func LoadMigrations(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+	byVersion := map[uint64]*Migration{}
+	for _, e := range entries {
+		if e.IsDir() || e.Name() == bootstrapFile {
+			continue
+		}
+		match := migrationFileName.FindStringSubmatch(e.Name())
+		if match == nil {
+			return nil, fmt.Errorf("invalid migration file name %s", e.Name())
+		}
+		version, err := strconv.ParseUint(match[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version in %s: %w", e.Name(), err)
+		}
+		content, err := fs.ReadFile(fsys, path.Join(dir, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = m
+		}
+		if m.Name != match[2] {
+			return nil, fmt.Errorf("migration %d has different names: %s and %s", version, m.Name, match[2])
+		}
+		if match[3] == "up" {
+			m.Up = string(content)
+		} else {
+			m.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" || m.Down == "" {
+			return nil, fmt.Errorf("migration %d_%s must have both up and down files", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// Up applies all pending migrations under the migration lock and returns the applied ones.
+// Each migration runs in its own savepoint together with its data migration if there is one,
+// so those applied before a failed one stay applied.
+func (m *Migrator) Up() ([]Migration, error) {
+	var done []Migration
+	var failure error
+	err := m.withLock(func(tx *gorm.DB) error {
+		applied, err := m.applied(tx)
+		if err != nil {
+			return err
+		}
+		for _, migration := range m.migrations {
+			if _, ok := applied[migration.Version]; ok {
+				continue
+			}
+			err = tx.Transaction(func(tx *gorm.DB) error {
+				if err := tx.Exec(migration.Up).Error; err != nil {
+					return err
+				}
+				if dataMigration, ok := dataMigrations[migration.Version]; ok {
+					if err := dataMigration(tx); err != nil {
+						return err
+					}
+				}
+				return tx.Table(migrationTable).Create(&appliedMigration{
+					Version:   migration.Version,
+					Name:      migration.Name,
+					AppliedAt: time.Now(),
+				}).Error
+			})
+			if err != nil {
+				failure = fmt.Errorf("migration %d_%s failed: %w", migration.Version, migration.Name, err)
+				return nil
+			}
+			done = append(done, migration)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return done, failure
+}
+
+// Down reverts the given number of the latest applied migrations under the migration lock
+// and returns the reverted ones.
+func (m *Migrator) Down(steps int) ([]Migration, error) {
+	var done []Migration
+	var failure error
+	err := m.withLock(func(tx *gorm.DB) error {
+		applied, err := m.applied(tx)
+		if err != nil {
+			return err
+		}
+		for i := len(m.migrations) - 1; i >= 0 && len(done) < steps; i-- {
+			migration := m.migrations[i]
+			if _, ok := applied[migration.Version]; !ok {
+				continue
+			}
+			err = tx.Transaction(func(tx *gorm.DB) error {
+				if err := tx.Exec(migration.Down).Error; err != nil {
+					return err
+				}
+				return tx.Table(migrationTable).Where("version = ?", migration.Version).Delete(&appliedMigration{}).Error
+			})
+			if err != nil {
+				failure = fmt.Errorf("rollback of %d_%s failed: %w", migration.Version, migration.Name, err)
+				return nil
+			}
+			done = append(done, migration)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return done, failure
+}
+
+// withLock runs fn in a transaction holding the migration lock, so replicas starting in auto mode
+// migrate one after another and the later ones find the migrations applied. Postgres takes
+// a transaction-level advisory lock, SQLite databases are local to a single instance.
+func (m *Migrator) withLock(fn func(tx *gorm.DB) error) error {
+	return m.db.Transaction(func(tx *gorm.DB) error {
+		if tx.Dialector.Name() == "postgres" {
+			if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", migrationLockId).Error; err != nil {
+				return err
+			}
+		}
+		return fn(tx)
+	})
+}
+
+// Status lists known migrations with the time they were applied at, if they were.
+func (m *Migrator) Status() ([]Status, error) {
+	applied, err := m.applied(m.db)
+	if err != nil {
+		return nil, err
+	}
+	statuses := make([]Status, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		status := Status{Version: migration.Version, Name: migration.Name}
+		if a, ok := applied[migration.Version]; ok {
+			status.AppliedAt = &a.AppliedAt
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// Check fails if there are pending migrations or the database was migrated
+// by a newer version of the service, which this one doesn't know about.
+func (m *Migrator) Check() error {
+	applied, err := m.applied(m.db)
+	if err != nil {
+		return err
+	}
+	known := make(map[uint64]struct{}, len(m.migrations))
+	var pending []uint64
+	for _, migration := range m.migrations {
+		known[migration.Version] = struct{}{}
+		if _, ok := applied[migration.Version]; !ok {
+			pending = append(pending, migration.Version)
+		}
+	}
+	for version := range applied {
+		if _, ok := known[version]; !ok {
+			return fmt.Errorf("%w: %d", serviceerror.UnknownMigrationApplied, version)
+		}
+	}
+	if len(pending) > 0 {
+		return fmt.Errorf("%w: %v", serviceerror.MigrationsPending, pending)
+	}
+	return nil
+}
+
+// Run prepares the database schema according to the startup mode:
+// either applies pending migrations or only checks there are none.
+func Run(db *gorm.DB, mode string) error {
+	migrator, err := NewMigrator(db)
+	if err != nil {
+		return err
+	}
+	switch mode {
+	case ModeAuto:
+		if _, err = migrator.Up(); err != nil {
+			return err
+		}
+		return migrator.Check()
+	case ModeCheck, "":
+		return migrator.Check()
+	default:
+		return fmt.Errorf("%w: %s", serviceerror.InvalidMigrationMode, mode)
+	}
+}
+
+func (m *Migrator) applied(db *gorm.DB) (map[uint64]appliedMigration, error) {
+	if err := db.Exec(m.bootstrap).Error; err != nil {
+		return nil, err
+	}
+	var rows []appliedMigration
+	if err := db.Table(migrationTable).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	applied := make(map[uint64]appliedMigration, len(rows))
+	for _, row := range rows {
+		applied[row.Version] = row
+	}
+	return applied, nil
+}
@@ -0,0 +1,83 @@
+-- Baseline of the schema previously created by AutoMigrate.
+-- Statements are idempotent, so the migration can be applied to such databases as well.
+
+CREATE TABLE IF NOT EXISTS portmonetka.wallets
+(
+    id             BIGSERIAL PRIMARY KEY,
+    user_id        BIGINT  NOT NULL,
+    name           TEXT    NOT NULL,
+    description    TEXT,
+    currency       TEXT    NOT NULL,
+    initial_amount NUMERIC NOT NULL,
+    version        BIGINT  NOT NULL DEFAULT 1,
+    created_at     TIMESTAMPTZ,
+    updated_at     TIMESTAMPTZ,
+    deleted_at     TIMESTAMPTZ
+);
+
+-- AutoMigrate created wallets before optimistic locking was introduced
+ALTER TABLE portmonetka.wallets
+    ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_userid_name_deletedat ON portmonetka.wallets (user_id, name, deleted_at);
+CREATE INDEX IF NOT EXISTS idx_portmonetka_wallets_deleted_at ON portmonetka.wallets (deleted_at);
+
+CREATE TABLE IF NOT EXISTS portmonetka.transfers
+(
+    id               BIGSERIAL PRIMARY KEY,
+    user_id          BIGINT      NOT NULL,
+    source_wallet_id BIGINT      NOT NULL,
+    target_wallet_id BIGINT      NOT NULL,
+    source_amount    NUMERIC     NOT NULL,
+    target_amount    NUMERIC     NOT NULL,
+    rate             NUMERIC     NOT NULL,
+    timestamp        TIMESTAMPTZ NOT NULL,
+    note             TEXT,
+    created_at       TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_portmonetka_transfers_user_id ON portmonetka.transfers (user_id);
+
+CREATE TABLE IF NOT EXISTS portmonetka.transactions
+(
+    id          BIGSERIAL PRIMARY KEY,
+    wallet_id   BIGINT      NOT NULL REFERENCES portmonetka.wallets (id),
+    transfer_id BIGINT REFERENCES portmonetka.transfers (id),
+    amount      NUMERIC     NOT NULL,
+    direction   TEXT        NOT NULL,
+    timestamp   TIMESTAMPTZ NOT NULL,
+    note        TEXT,
+    category    TEXT,
+    created_at  TIMESTAMPTZ,
+    updated_at  TIMESTAMPTZ,
+    deleted_at  TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_portmonetka_transactions_wallet_id ON portmonetka.transactions (wallet_id);
+CREATE INDEX IF NOT EXISTS idx_portmonetka_transactions_transfer_id ON portmonetka.transactions (transfer_id);
+CREATE INDEX IF NOT EXISTS idx_portmonetka_transactions_timestamp ON portmonetka.transactions (timestamp);
+CREATE INDEX IF NOT EXISTS idx_portmonetka_transactions_deleted_at ON portmonetka.transactions (deleted_at);
+
+CREATE TABLE IF NOT EXISTS portmonetka.idempotency_keys
+(
+    id              BIGSERIAL PRIMARY KEY,
+    user_id         BIGINT NOT NULL,
+    idempotency_key TEXT   NOT NULL,
+    request_hash    TEXT   NOT NULL,
+    status_code     BIGINT NOT NULL,
+    response        BYTEA,
+    created_at      TIMESTAMPTZ,
+    updated_at      TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_idempotency_userid_key ON portmonetka.idempotency_keys (user_id, idempotency_key);
+
+-- AutoMigrate stored decimals as text, which breaks balance arithmetic
+ALTER TABLE portmonetka.wallets
+    ALTER COLUMN initial_amount TYPE NUMERIC USING initial_amount::NUMERIC;
+ALTER TABLE portmonetka.transactions
+    ALTER COLUMN amount TYPE NUMERIC USING amount::NUMERIC;
+ALTER TABLE portmonetka.transfers
+    ALTER COLUMN source_amount TYPE NUMERIC USING source_amount::NUMERIC,
+    ALTER COLUMN target_amount TYPE NUMERIC USING target_amount::NUMERIC,
+    ALTER COLUMN rate TYPE NUMERIC USING rate::NUMERIC;
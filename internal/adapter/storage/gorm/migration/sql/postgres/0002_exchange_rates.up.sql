@@ -0,0 +1,13 @@
+CREATE TABLE portmonetka.exchange_rates
+(
+    id             BIGSERIAL PRIMARY KEY,
+    date           DATE    NOT NULL,
+    base_currency  TEXT    NOT NULL,
+    quote_currency TEXT    NOT NULL,
+    rate           NUMERIC NOT NULL,
+    created_at     TIMESTAMPTZ,
+    updated_at     TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX idx_exchange_rate_date_pair ON portmonetka.exchange_rates (date, base_currency, quote_currency);
+CREATE INDEX idx_exchange_rate_pair_date ON portmonetka.exchange_rates (base_currency, quote_currency, date);
@@ -0,0 +1,10 @@
+CREATE TABLE portmonetka.user_preferences
+(
+    user_id           BIGINT PRIMARY KEY,
+    base_currency     TEXT,
+    default_wallet_id BIGINT REFERENCES portmonetka.wallets (id) ON DELETE SET NULL,
+    locale            TEXT     NOT NULL,
+    month_start_day   SMALLINT NOT NULL,
+    created_at        TIMESTAMPTZ,
+    updated_at        TIMESTAMPTZ
+);
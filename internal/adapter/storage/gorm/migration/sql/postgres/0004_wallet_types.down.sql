@@ -0,0 +1,4 @@
+ALTER TABLE portmonetka.wallets DROP COLUMN interest_rate;
+ALTER TABLE portmonetka.wallets DROP COLUMN statement_day;
+ALTER TABLE portmonetka.wallets DROP COLUMN credit_limit;
+ALTER TABLE portmonetka.wallets DROP COLUMN type;
@@ -0,0 +1,4 @@
+ALTER TABLE portmonetka.wallets ADD COLUMN type TEXT NOT NULL DEFAULT 'cash';
+ALTER TABLE portmonetka.wallets ADD COLUMN credit_limit NUMERIC;
+ALTER TABLE portmonetka.wallets ADD COLUMN statement_day SMALLINT;
+ALTER TABLE portmonetka.wallets ADD COLUMN interest_rate NUMERIC;
@@ -0,0 +1 @@
+ALTER TABLE portmonetka.wallets DROP COLUMN allow_negative;
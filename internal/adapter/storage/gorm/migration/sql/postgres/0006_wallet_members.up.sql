@@ -0,0 +1,14 @@
+CREATE TABLE portmonetka.wallet_members
+(
+    id          BIGSERIAL PRIMARY KEY,
+    wallet_id   BIGINT NOT NULL REFERENCES portmonetka.wallets (id) ON DELETE CASCADE,
+    user_id     BIGINT NOT NULL,
+    role        TEXT   NOT NULL,
+    invited_by  BIGINT NOT NULL,
+    accepted_at TIMESTAMPTZ,
+    created_at  TIMESTAMPTZ,
+    updated_at  TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX idx_wallet_member ON portmonetka.wallet_members (wallet_id, user_id);
+CREATE INDEX idx_portmonetka_wallet_members_user_id ON portmonetka.wallet_members (user_id);
@@ -0,0 +1,4 @@
+-- NULL deleted_at values are distinct in the old index, so it didn't prevent duplicate names among active wallets.
+-- Fails if a user already has active wallets whose names differ only in case, they must be renamed first.
+DROP INDEX portmonetka.idx_userid_name_deletedat;
+CREATE UNIQUE INDEX idx_wallets_userid_lower_name ON portmonetka.wallets (user_id, LOWER(name)) WHERE deleted_at IS NULL;
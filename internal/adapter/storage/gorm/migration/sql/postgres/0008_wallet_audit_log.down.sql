@@ -0,0 +1 @@
+DROP TABLE IF EXISTS portmonetka.wallet_audit_log;
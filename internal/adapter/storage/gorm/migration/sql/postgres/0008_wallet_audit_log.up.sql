@@ -0,0 +1,12 @@
+CREATE TABLE portmonetka.wallet_audit_log
+(
+    id           BIGSERIAL PRIMARY KEY,
+    wallet_id    BIGINT NOT NULL,
+    actor_id     BIGINT NOT NULL,
+    request_uuid TEXT,
+    action       TEXT   NOT NULL,
+    changes      JSONB  NOT NULL,
+    created_at   TIMESTAMPTZ
+);
+
+CREATE INDEX idx_portmonetka_wallet_audit_log_wallet_id ON portmonetka.wallet_audit_log (wallet_id);
@@ -0,0 +1,11 @@
+CREATE TABLE portmonetka.outbox_events
+(
+    id           BIGSERIAL PRIMARY KEY,
+    type         TEXT   NOT NULL,
+    aggregate_id BIGINT NOT NULL,
+    payload      JSONB  NOT NULL,
+    created_at   TIMESTAMPTZ,
+    published_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_portmonetka_outbox_events_unpublished ON portmonetka.outbox_events (id) WHERE published_at IS NULL;
@@ -0,0 +1,2 @@
+-- the members demoted from owners can't be told from editors, so the roles stay as they are
+SELECT 1;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS portmonetka.idx_portmonetka_outbox_events_published;
+ALTER TABLE portmonetka.outbox_events DROP COLUMN claimed_until;
@@ -0,0 +1,3 @@
+ALTER TABLE portmonetka.outbox_events ADD COLUMN claimed_until TIMESTAMPTZ;
+
+CREATE INDEX idx_portmonetka_outbox_events_published ON portmonetka.outbox_events (published_at) WHERE published_at IS NOT NULL;
@@ -0,0 +1,5 @@
+ALTER TABLE portmonetka.idempotency_keys ADD COLUMN reserved_at TIMESTAMPTZ;
+UPDATE portmonetka.idempotency_keys SET reserved_at = created_at;
+ALTER TABLE portmonetka.idempotency_keys ALTER COLUMN reserved_at SET NOT NULL;
+
+CREATE INDEX idx_portmonetka_idempotency_keys_completed ON portmonetka.idempotency_keys (updated_at) WHERE status_code <> 0;
@@ -0,0 +1,3 @@
+DROP INDEX portmonetka.idx_wallets_userid_name_key;
+CREATE UNIQUE INDEX idx_wallets_userid_lower_name ON portmonetka.wallets (user_id, LOWER(name)) WHERE deleted_at IS NULL;
+ALTER TABLE portmonetka.wallets ALTER COLUMN name_key SET DEFAULT '';
@@ -0,0 +1,4 @@
+-- Fails if a user already has active wallets whose names have the same key, they must be renamed first.
+ALTER TABLE portmonetka.wallets ALTER COLUMN name_key DROP DEFAULT;
+DROP INDEX portmonetka.idx_wallets_userid_lower_name;
+CREATE UNIQUE INDEX idx_wallets_userid_name_key ON portmonetka.wallets (user_id, name_key) WHERE deleted_at IS NULL;
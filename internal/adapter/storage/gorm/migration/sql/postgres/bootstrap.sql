@@ -0,0 +1,8 @@
+CREATE SCHEMA IF NOT EXISTS portmonetka;
+
+CREATE TABLE IF NOT EXISTS portmonetka.schema_migrations
+(
+    version    BIGINT PRIMARY KEY,
+    name       TEXT        NOT NULL,
+    applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS portmonetka.idempotency_keys;
+DROP TABLE IF EXISTS portmonetka.transactions;
+DROP TABLE IF EXISTS portmonetka.transfers;
+DROP TABLE IF EXISTS portmonetka.wallets;
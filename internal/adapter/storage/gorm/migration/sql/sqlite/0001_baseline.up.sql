@@ -0,0 +1,66 @@
+CREATE TABLE portmonetka.wallets
+(
+    id             INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id        INTEGER NOT NULL,
+    name           TEXT    NOT NULL,
+    description    TEXT,
+    currency       TEXT    NOT NULL,
+    initial_amount NUMERIC NOT NULL,
+    version        INTEGER NOT NULL DEFAULT 1,
+    created_at     DATETIME,
+    updated_at     DATETIME,
+    deleted_at     DATETIME
+);
+
+CREATE UNIQUE INDEX portmonetka.idx_userid_name_deletedat ON wallets (user_id, name, deleted_at);
+CREATE INDEX portmonetka.idx_portmonetka_wallets_deleted_at ON wallets (deleted_at);
+
+CREATE TABLE portmonetka.transfers
+(
+    id               INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id          INTEGER  NOT NULL,
+    source_wallet_id INTEGER  NOT NULL,
+    target_wallet_id INTEGER  NOT NULL,
+    source_amount    NUMERIC  NOT NULL,
+    target_amount    NUMERIC  NOT NULL,
+    rate             NUMERIC  NOT NULL,
+    timestamp        DATETIME NOT NULL,
+    note             TEXT,
+    created_at       DATETIME
+);
+
+CREATE INDEX portmonetka.idx_portmonetka_transfers_user_id ON transfers (user_id);
+
+CREATE TABLE portmonetka.transactions
+(
+    id          INTEGER PRIMARY KEY AUTOINCREMENT,
+    wallet_id   INTEGER  NOT NULL REFERENCES wallets (id),
+    transfer_id INTEGER REFERENCES transfers (id),
+    amount      NUMERIC  NOT NULL,
+    direction   TEXT     NOT NULL,
+    timestamp   DATETIME NOT NULL,
+    note        TEXT,
+    category    TEXT,
+    created_at  DATETIME,
+    updated_at  DATETIME,
+    deleted_at  DATETIME
+);
+
+CREATE INDEX portmonetka.idx_portmonetka_transactions_wallet_id ON transactions (wallet_id);
+CREATE INDEX portmonetka.idx_portmonetka_transactions_transfer_id ON transactions (transfer_id);
+CREATE INDEX portmonetka.idx_portmonetka_transactions_timestamp ON transactions (timestamp);
+CREATE INDEX portmonetka.idx_portmonetka_transactions_deleted_at ON transactions (deleted_at);
+
+CREATE TABLE portmonetka.idempotency_keys
+(
+    id              INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id         INTEGER NOT NULL,
+    idempotency_key TEXT    NOT NULL,
+    request_hash    TEXT    NOT NULL,
+    status_code     INTEGER NOT NULL,
+    response        BLOB,
+    created_at      DATETIME,
+    updated_at      DATETIME
+);
+
+CREATE UNIQUE INDEX portmonetka.idx_idempotency_userid_key ON idempotency_keys (user_id, idempotency_key);
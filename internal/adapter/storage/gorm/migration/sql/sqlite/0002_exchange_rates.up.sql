@@ -0,0 +1,13 @@
+CREATE TABLE portmonetka.exchange_rates
+(
+    id             INTEGER PRIMARY KEY AUTOINCREMENT,
+    date           DATE    NOT NULL,
+    base_currency  TEXT    NOT NULL,
+    quote_currency TEXT    NOT NULL,
+    rate           NUMERIC NOT NULL,
+    created_at     DATETIME,
+    updated_at     DATETIME
+);
+
+CREATE UNIQUE INDEX portmonetka.idx_exchange_rate_date_pair ON exchange_rates (date, base_currency, quote_currency);
+CREATE INDEX portmonetka.idx_exchange_rate_pair_date ON exchange_rates (base_currency, quote_currency, date);
@@ -0,0 +1,10 @@
+CREATE TABLE portmonetka.user_preferences
+(
+    user_id           INTEGER PRIMARY KEY,
+    base_currency     TEXT,
+    default_wallet_id INTEGER REFERENCES wallets (id) ON DELETE SET NULL,
+    locale            TEXT    NOT NULL,
+    month_start_day   INTEGER NOT NULL,
+    created_at        DATETIME,
+    updated_at        DATETIME
+);
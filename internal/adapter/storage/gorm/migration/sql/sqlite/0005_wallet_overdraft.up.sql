@@ -0,0 +1 @@
+ALTER TABLE portmonetka.wallets ADD COLUMN allow_negative BOOLEAN NOT NULL DEFAULT FALSE;
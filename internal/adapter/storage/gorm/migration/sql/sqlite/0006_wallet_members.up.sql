@@ -0,0 +1,14 @@
+CREATE TABLE portmonetka.wallet_members
+(
+    id          INTEGER PRIMARY KEY AUTOINCREMENT,
+    wallet_id   INTEGER NOT NULL REFERENCES wallets (id) ON DELETE CASCADE,
+    user_id     INTEGER NOT NULL,
+    role        TEXT    NOT NULL,
+    invited_by  INTEGER NOT NULL,
+    accepted_at DATETIME,
+    created_at  DATETIME,
+    updated_at  DATETIME
+);
+
+CREATE UNIQUE INDEX portmonetka.idx_wallet_member ON wallet_members (wallet_id, user_id);
+CREATE INDEX portmonetka.idx_portmonetka_wallet_members_user_id ON wallet_members (user_id);
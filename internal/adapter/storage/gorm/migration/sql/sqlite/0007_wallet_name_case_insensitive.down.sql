@@ -0,0 +1,2 @@
+DROP INDEX portmonetka.idx_wallets_userid_lower_name;
+CREATE UNIQUE INDEX portmonetka.idx_userid_name_deletedat ON wallets (user_id, name, deleted_at);
@@ -0,0 +1,12 @@
+CREATE TABLE portmonetka.wallet_audit_log
+(
+    id           INTEGER PRIMARY KEY AUTOINCREMENT,
+    wallet_id    INTEGER NOT NULL,
+    actor_id     INTEGER NOT NULL,
+    request_uuid TEXT,
+    action       TEXT    NOT NULL,
+    changes      TEXT    NOT NULL,
+    created_at   DATETIME
+);
+
+CREATE INDEX portmonetka.idx_portmonetka_wallet_audit_log_wallet_id ON wallet_audit_log (wallet_id);
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS portmonetka.outbox_events;
@@ -0,0 +1,11 @@
+CREATE TABLE portmonetka.outbox_events
+(
+    id           INTEGER PRIMARY KEY AUTOINCREMENT,
+    type         TEXT    NOT NULL,
+    aggregate_id INTEGER NOT NULL,
+    payload      TEXT    NOT NULL,
+    created_at   DATETIME,
+    published_at DATETIME
+);
+
+CREATE INDEX portmonetka.idx_portmonetka_outbox_events_unpublished ON outbox_events (id) WHERE published_at IS NULL;
@@ -0,0 +1,4 @@
+-- the owner role is no longer granted by invitations, as only the creator can restore a deleted wallet
+UPDATE portmonetka.wallet_members
+SET role = 'editor'
+WHERE role = 'owner';
@@ -0,0 +1,3 @@
+ALTER TABLE portmonetka.outbox_events ADD COLUMN claimed_until DATETIME;
+
+CREATE INDEX portmonetka.idx_portmonetka_outbox_events_published ON outbox_events (published_at) WHERE published_at IS NOT NULL;
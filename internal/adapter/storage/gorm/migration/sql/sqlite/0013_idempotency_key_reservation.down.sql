@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS portmonetka.idx_portmonetka_idempotency_keys_completed;
+ALTER TABLE portmonetka.idempotency_keys DROP COLUMN reserved_at;
@@ -0,0 +1,4 @@
+ALTER TABLE portmonetka.idempotency_keys ADD COLUMN reserved_at DATETIME NOT NULL DEFAULT '1970-01-01 00:00:00';
+UPDATE portmonetka.idempotency_keys SET reserved_at = created_at WHERE created_at IS NOT NULL;
+
+CREATE INDEX portmonetka.idx_portmonetka_idempotency_keys_completed ON idempotency_keys (updated_at) WHERE status_code <> 0;
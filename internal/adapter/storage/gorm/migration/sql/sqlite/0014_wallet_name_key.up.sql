@@ -0,0 +1,4 @@
+-- name_key holds the wallet name as compared by the uniqueness rule. It is computed by the application
+-- with Unicode case folding, which LOWER() doesn't match, so existing rows are backfilled by the Go step
+-- of this migration and the unique index is switched to it by the next one.
+ALTER TABLE portmonetka.wallets ADD COLUMN name_key TEXT NOT NULL DEFAULT '';
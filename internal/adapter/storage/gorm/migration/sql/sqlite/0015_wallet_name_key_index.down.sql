@@ -0,0 +1,2 @@
+DROP INDEX portmonetka.idx_wallets_userid_name_key;
+CREATE UNIQUE INDEX portmonetka.idx_wallets_userid_lower_name ON wallets (user_id, LOWER(name)) WHERE deleted_at IS NULL;
@@ -0,0 +1,3 @@
+-- Fails if a user already has active wallets whose names have the same key, they must be renamed first.
+DROP INDEX portmonetka.idx_wallets_userid_lower_name;
+CREATE UNIQUE INDEX portmonetka.idx_wallets_userid_name_key ON wallets (user_id, name_key) WHERE deleted_at IS NULL;
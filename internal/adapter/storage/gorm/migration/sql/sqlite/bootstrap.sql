@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS portmonetka.schema_migrations
+(
+    version    INTEGER PRIMARY KEY,
+    name       TEXT     NOT NULL,
+    applied_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
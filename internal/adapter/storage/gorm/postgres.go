@@ -3,8 +3,8 @@ package gorm
 import (
 	"fmt"
 	"github.com/khivuksergey/portmonetka.wallet/config"
-	"github.com/khivuksergey/portmonetka.wallet/internal/adapter/storage/entity"
-	"github.com/khivuksergey/portmonetka.wallet/internal/adapter/storage/gorm/repo"
+	serviceerror "github.com/khivuksergey/portmonetka.wallet/error"
+	"github.com/khivuksergey/portmonetka.wallet/internal/adapter/storage/gorm/migration"
 	"github.com/khivuksergey/portmonetka.wallet/internal/core/port/repository"
 	"github.com/khivuksergey/portmonetka.wallet/internal/core/port/storage"
 	"github.com/spf13/viper"
@@ -13,6 +13,11 @@ import (
 	"gorm.io/gorm/logger"
 )
 
+const (
+	DriverPostgres = "postgres"
+	DriverSqlite   = "sqlite"
+)
+
 type dbManager struct {
 	db  *gorm.DB
 	cfg *config.DBConfig
@@ -28,6 +33,39 @@ func NewDbManager(config config.DBConfig) storage.IDB {
 }
 
 func (m *dbManager) InitDB(config config.DBConfig) (err error) {
+	m.db, err = Open(config)
+	if err != nil {
+		return err
+	}
+	return migration.Run(m.db, config.MigrationMode)
+}
+
+// Open connects to the database of the configured driver without touching its schema.
+func Open(config config.DBConfig) (db *gorm.DB, err error) {
+	switch config.Driver {
+	case DriverSqlite:
+		db, err = openSqlite(config)
+	case DriverPostgres, "":
+		db, err = openPostgres(config)
+	default:
+		return nil, fmt.Errorf("%w: %s", serviceerror.UnknownDatabaseDriver, config.Driver)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err = db.Use(repositoryErrors{}); err != nil {
+		return nil, err
+	}
+	if config.QueryTimeout <= 0 {
+		return db, nil
+	}
+	if err = db.Use(queryTimeout{timeout: config.QueryTimeout}); err != nil {
+		return nil, err
+	}
+	return db, nil
+}
+
+func openPostgres(config config.DBConfig) (*gorm.DB, error) {
 	dsn := fmt.Sprintf(config.ConnectionString,
 		viper.GetString("DB_USER"),
 		viper.GetString("DB_PASSWORD"),
@@ -35,29 +73,21 @@ func (m *dbManager) InitDB(config config.DBConfig) (err error) {
 		viper.GetString("DB_HOST"),
 	)
 
-	m.db, err = gorm.Open(
+	return gorm.Open(
 		postgres.New(postgres.Config{
 			DSN:                  dsn,
 			PreferSimpleProtocol: true,
 		}),
 		&gorm.Config{
 			Logger: logger.Default.LogMode(logger.Silent),
+			// unique violations are reported as gorm.ErrDuplicatedKey, wrapped into repository.ErrDuplicatedKey
+			TranslateError: true,
 		},
 	)
-
-	if err != nil {
-		return err
-	}
-
-	err = m.db.AutoMigrate(&entity.Wallet{})
-
-	return err
 }
 
 func (m *dbManager) InitRepositoryManager() *repository.Manager {
-	return &repository.Manager{
-		Wallet: repo.NewWalletRepository(m.db),
-	}
+	return newRepositoryManager(m.db)
 }
 
 func (m *dbManager) Close() (err error) {
@@ -0,0 +1,53 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"github.com/khivuksergey/portmonetka.wallet/internal/adapter/storage/entity"
+	"github.com/khivuksergey/portmonetka.wallet/internal/core/port/repository"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+const exchangeRateBatchSize = 500
+
+type exchangeRateRepository struct {
+	db        *gorm.DB
+	tableName string
+}
+
+func NewExchangeRateRepository(db *gorm.DB) repository.ExchangeRateRepository {
+	return &exchangeRateRepository{db: db, tableName: entity.ExchangeRate{}.TableName()}
+}
+
+// SaveExchangeRates inserts the rates, replacing already stored rates of the same pair on the same date.
+// The rates must not repeat a pair on the same date, as Postgres rejects an upsert affecting a row twice.
+func (e *exchangeRateRepository) SaveExchangeRates(ctx context.Context, exchangeRates []entity.ExchangeRate) error {
+	if len(exchangeRates) == 0 {
+		return nil
+	}
+	return e.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "date"}, {Name: "base_currency"}, {Name: "quote_currency"}},
+			DoUpdates: clause.AssignmentColumns([]string{"rate", "updated_at"}),
+		}).
+		CreateInBatches(&exchangeRates, exchangeRateBatchSize).Error
+}
+
+// GetEffectiveExchangeRates returns the latest rate of every currency pair published on or before the date.
+func (e *exchangeRateRepository) GetEffectiveExchangeRates(ctx context.Context, date time.Time) ([]entity.ExchangeRate, error) {
+	var exchangeRates []entity.ExchangeRate
+	// raw query, as the sqlite dialector drops the alias of a schema-qualified table
+	result := e.db.WithContext(ctx).
+		Raw(fmt.Sprintf(
+			"SELECT r.* FROM %[1]s r WHERE r.date = (SELECT MAX(l.date) FROM %[1]s l "+
+				"WHERE l.base_currency = r.base_currency AND l.quote_currency = r.quote_currency AND l.date <= ?)",
+			e.tableName,
+		), date).
+		Find(&exchangeRates)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return exchangeRates, nil
+}
@@ -0,0 +1,65 @@
+package repo
+
+import (
+	"context"
+	"github.com/khivuksergey/portmonetka.wallet/internal/adapter/storage/entity"
+	"github.com/khivuksergey/portmonetka.wallet/internal/core/port/repository"
+	"gorm.io/gorm"
+	"time"
+)
+
+type idempotencyRepository struct {
+	db        *gorm.DB
+	tableName string
+}
+
+func NewIdempotencyRepository(db *gorm.DB) repository.IdempotencyRepository {
+	return &idempotencyRepository{db: db, tableName: entity.IdempotencyKey{}.TableName()}
+}
+
+func (i *idempotencyRepository) GetIdempotencyKey(ctx context.Context, userId uint64, key string) (*entity.IdempotencyKey, error) {
+	idempotencyKey := &entity.IdempotencyKey{}
+	result := i.db.WithContext(ctx).Where("user_id = ? AND idempotency_key = ?", userId, key).First(idempotencyKey)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return idempotencyKey, nil
+}
+
+func (i *idempotencyRepository) CreateIdempotencyKey(ctx context.Context, idempotencyKey *entity.IdempotencyKey) error {
+	return i.db.WithContext(ctx).Create(idempotencyKey).Error
+}
+
+func (i *idempotencyRepository) UpdateIdempotencyKey(ctx context.Context, idempotencyKey *entity.IdempotencyKey) error {
+	return i.db.WithContext(ctx).Save(idempotencyKey).Error
+}
+
+func (i *idempotencyRepository) DeleteIdempotencyKey(ctx context.Context, id uint64) error {
+	return i.db.WithContext(ctx).Delete(&entity.IdempotencyKey{}, id).Error
+}
+
+// ReserveStaleIdempotencyKey checks the key is stale in the same statement that reserves it,
+// so of concurrent requests taking over the key only one succeeds.
+func (i *idempotencyRepository) ReserveStaleIdempotencyKey(ctx context.Context, id uint64, requestHash string, staleBefore, expiredBefore time.Time) (bool, error) {
+	result := i.db.WithContext(ctx).
+		Model(&entity.IdempotencyKey{}).
+		Where("id = ? AND ((status_code = 0 AND reserved_at < ?) OR (status_code <> 0 AND updated_at < ?))",
+			id, staleBefore.UTC(), expiredBefore.UTC()).
+		Updates(map[string]any{
+			"request_hash": requestHash,
+			"status_code":  0,
+			"response":     nil,
+			"reserved_at":  time.Now().UTC(),
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (i *idempotencyRepository) DeleteIdempotencyKeysCompletedBefore(ctx context.Context, completedBefore time.Time) (int64, error) {
+	result := i.db.WithContext(ctx).
+		Where("status_code <> 0 AND updated_at < ?", completedBefore.UTC()).
+		Delete(&entity.IdempotencyKey{})
+	return result.RowsAffected, result.Error
+}
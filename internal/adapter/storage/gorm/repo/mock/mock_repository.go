@@ -10,9 +10,13 @@
 package mock
 
 import (
+	context "context"
 	reflect "reflect"
+	time "time"
 
 	entity "github.com/khivuksergey/portmonetka.wallet/internal/adapter/storage/entity"
+	repository "github.com/khivuksergey/portmonetka.wallet/internal/core/port/repository"
+	model "github.com/khivuksergey/portmonetka.wallet/internal/model"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -40,103 +44,854 @@ func (m *MockWalletRepository) EXPECT() *MockWalletRepositoryMockRecorder {
 }
 
 // CreateWallet mocks base method.
-func (m *MockWalletRepository) CreateWallet(wallet *entity.Wallet) (*entity.Wallet, error) {
+func (m *MockWalletRepository) CreateWallet(ctx context.Context, wallet *entity.Wallet) (*entity.Wallet, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateWallet", wallet)
+	ret := m.ctrl.Call(m, "CreateWallet", ctx, wallet)
 	ret0, _ := ret[0].(*entity.Wallet)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreateWallet indicates an expected call of CreateWallet.
-func (mr *MockWalletRepositoryMockRecorder) CreateWallet(wallet any) *gomock.Call {
+func (mr *MockWalletRepositoryMockRecorder) CreateWallet(ctx, wallet any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWallet", reflect.TypeOf((*MockWalletRepository)(nil).CreateWallet), wallet)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWallet", reflect.TypeOf((*MockWalletRepository)(nil).CreateWallet), ctx, wallet)
 }
 
 // DeleteWallet mocks base method.
-func (m *MockWalletRepository) DeleteWallet(id uint64) error {
+func (m *MockWalletRepository) DeleteWallet(ctx context.Context, id uint64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteWallet", id)
+	ret := m.ctrl.Call(m, "DeleteWallet", ctx, id)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteWallet indicates an expected call of DeleteWallet.
-func (mr *MockWalletRepositoryMockRecorder) DeleteWallet(id any) *gomock.Call {
+func (mr *MockWalletRepositoryMockRecorder) DeleteWallet(ctx, id any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWallet", reflect.TypeOf((*MockWalletRepository)(nil).DeleteWallet), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWallet", reflect.TypeOf((*MockWalletRepository)(nil).DeleteWallet), ctx, id)
 }
 
-// ExistsWithName mocks base method.
-func (m *MockWalletRepository) ExistsWithName(userId uint64, name string) bool {
+// DeleteWalletWithVersion mocks base method.
+func (m *MockWalletRepository) DeleteWalletWithVersion(ctx context.Context, id, version uint64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ExistsWithName", userId, name)
-	ret0, _ := ret[0].(bool)
+	ret := m.ctrl.Call(m, "DeleteWalletWithVersion", ctx, id, version)
+	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// ExistsWithName indicates an expected call of ExistsWithName.
-func (mr *MockWalletRepositoryMockRecorder) ExistsWithName(userId, name any) *gomock.Call {
+// DeleteWalletWithVersion indicates an expected call of DeleteWalletWithVersion.
+func (mr *MockWalletRepositoryMockRecorder) DeleteWalletWithVersion(ctx, id, version any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWalletWithVersion", reflect.TypeOf((*MockWalletRepository)(nil).DeleteWalletWithVersion), ctx, id, version)
+}
+
+// GetAllWalletsByUserId mocks base method.
+func (m *MockWalletRepository) GetAllWalletsByUserId(ctx context.Context, userId uint64) ([]entity.Wallet, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllWalletsByUserId", ctx, userId)
+	ret0, _ := ret[0].([]entity.Wallet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllWalletsByUserId indicates an expected call of GetAllWalletsByUserId.
+func (mr *MockWalletRepositoryMockRecorder) GetAllWalletsByUserId(ctx, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllWalletsByUserId", reflect.TypeOf((*MockWalletRepository)(nil).GetAllWalletsByUserId), ctx, userId)
+}
+
+// GetDeletedWalletById mocks base method.
+func (m *MockWalletRepository) GetDeletedWalletById(ctx context.Context, id uint64) (*entity.Wallet, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeletedWalletById", ctx, id)
+	ret0, _ := ret[0].(*entity.Wallet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeletedWalletById indicates an expected call of GetDeletedWalletById.
+func (mr *MockWalletRepositoryMockRecorder) GetDeletedWalletById(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeletedWalletById", reflect.TypeOf((*MockWalletRepository)(nil).GetDeletedWalletById), ctx, id)
+}
+
+// GetDeletedWalletsByUserId mocks base method.
+func (m *MockWalletRepository) GetDeletedWalletsByUserId(ctx context.Context, userId uint64) ([]entity.Wallet, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeletedWalletsByUserId", ctx, userId)
+	ret0, _ := ret[0].([]entity.Wallet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeletedWalletsByUserId indicates an expected call of GetDeletedWalletsByUserId.
+func (mr *MockWalletRepositoryMockRecorder) GetDeletedWalletsByUserId(ctx, userId any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExistsWithName", reflect.TypeOf((*MockWalletRepository)(nil).ExistsWithName), userId, name)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeletedWalletsByUserId", reflect.TypeOf((*MockWalletRepository)(nil).GetDeletedWalletsByUserId), ctx, userId)
 }
 
 // GetWalletById mocks base method.
-func (m *MockWalletRepository) GetWalletById(id uint64) (*entity.Wallet, error) {
+func (m *MockWalletRepository) GetWalletById(ctx context.Context, id uint64) (*entity.Wallet, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetWalletById", id)
+	ret := m.ctrl.Call(m, "GetWalletById", ctx, id)
 	ret0, _ := ret[0].(*entity.Wallet)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetWalletById indicates an expected call of GetWalletById.
-func (mr *MockWalletRepositoryMockRecorder) GetWalletById(id any) *gomock.Call {
+func (mr *MockWalletRepositoryMockRecorder) GetWalletById(ctx, id any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWalletById", reflect.TypeOf((*MockWalletRepository)(nil).GetWalletById), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWalletById", reflect.TypeOf((*MockWalletRepository)(nil).GetWalletById), ctx, id)
+}
+
+// GetWalletForUpdate mocks base method.
+func (m *MockWalletRepository) GetWalletForUpdate(ctx context.Context, id uint64) (*entity.Wallet, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWalletForUpdate", ctx, id)
+	ret0, _ := ret[0].(*entity.Wallet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWalletForUpdate indicates an expected call of GetWalletForUpdate.
+func (mr *MockWalletRepositoryMockRecorder) GetWalletForUpdate(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWalletForUpdate", reflect.TypeOf((*MockWalletRepository)(nil).GetWalletForUpdate), ctx, id)
 }
 
 // GetWalletsByUserId mocks base method.
-func (m *MockWalletRepository) GetWalletsByUserId(userId uint64) ([]entity.Wallet, error) {
+func (m *MockWalletRepository) GetWalletsByUserId(ctx context.Context, userId uint64, walletListQuery model.WalletListQuery, after *model.WalletCursor) ([]entity.Wallet, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetWalletsByUserId", userId)
+	ret := m.ctrl.Call(m, "GetWalletsByUserId", ctx, userId, walletListQuery, after)
 	ret0, _ := ret[0].([]entity.Wallet)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetWalletsByUserId indicates an expected call of GetWalletsByUserId.
-func (mr *MockWalletRepositoryMockRecorder) GetWalletsByUserId(userId any) *gomock.Call {
+func (mr *MockWalletRepositoryMockRecorder) GetWalletsByUserId(ctx, userId, walletListQuery, after any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWalletsByUserId", reflect.TypeOf((*MockWalletRepository)(nil).GetWalletsByUserId), ctx, userId, walletListQuery, after)
+}
+
+// PurgeWallet mocks base method.
+func (m *MockWalletRepository) PurgeWallet(ctx context.Context, id uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeWallet", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PurgeWallet indicates an expected call of PurgeWallet.
+func (mr *MockWalletRepositoryMockRecorder) PurgeWallet(ctx, id any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWalletsByUserId", reflect.TypeOf((*MockWalletRepository)(nil).GetWalletsByUserId), userId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeWallet", reflect.TypeOf((*MockWalletRepository)(nil).PurgeWallet), ctx, id)
+}
+
+// PurgeWalletsDeletedBefore mocks base method.
+func (m *MockWalletRepository) PurgeWalletsDeletedBefore(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeWalletsDeletedBefore", ctx, deletedBefore)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeWalletsDeletedBefore indicates an expected call of PurgeWalletsDeletedBefore.
+func (mr *MockWalletRepositoryMockRecorder) PurgeWalletsDeletedBefore(ctx, deletedBefore any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeWalletsDeletedBefore", reflect.TypeOf((*MockWalletRepository)(nil).PurgeWalletsDeletedBefore), ctx, deletedBefore)
+}
+
+// RestoreWallet mocks base method.
+func (m *MockWalletRepository) RestoreWallet(ctx context.Context, id uint64) (*entity.Wallet, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreWallet", ctx, id)
+	ret0, _ := ret[0].(*entity.Wallet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreWallet indicates an expected call of RestoreWallet.
+func (mr *MockWalletRepositoryMockRecorder) RestoreWallet(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreWallet", reflect.TypeOf((*MockWalletRepository)(nil).RestoreWallet), ctx, id)
 }
 
 // UpdateWallet mocks base method.
-func (m *MockWalletRepository) UpdateWallet(wallet *entity.Wallet) (*entity.Wallet, error) {
+func (m *MockWalletRepository) UpdateWallet(ctx context.Context, wallet *entity.Wallet) (*entity.Wallet, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateWallet", wallet)
+	ret := m.ctrl.Call(m, "UpdateWallet", ctx, wallet)
 	ret0, _ := ret[0].(*entity.Wallet)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // UpdateWallet indicates an expected call of UpdateWallet.
-func (mr *MockWalletRepositoryMockRecorder) UpdateWallet(wallet any) *gomock.Call {
+func (mr *MockWalletRepositoryMockRecorder) UpdateWallet(ctx, wallet any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWallet", reflect.TypeOf((*MockWalletRepository)(nil).UpdateWallet), ctx, wallet)
+}
+
+// MockTransactionRepository is a mock of TransactionRepository interface.
+type MockTransactionRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockTransactionRepositoryMockRecorder
+}
+
+// MockTransactionRepositoryMockRecorder is the mock recorder for MockTransactionRepository.
+type MockTransactionRepositoryMockRecorder struct {
+	mock *MockTransactionRepository
+}
+
+// NewMockTransactionRepository creates a new mock instance.
+func NewMockTransactionRepository(ctrl *gomock.Controller) *MockTransactionRepository {
+	mock := &MockTransactionRepository{ctrl: ctrl}
+	mock.recorder = &MockTransactionRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTransactionRepository) EXPECT() *MockTransactionRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateTransaction mocks base method.
+func (m *MockTransactionRepository) CreateTransaction(ctx context.Context, transaction *entity.Transaction) (*entity.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTransaction", ctx, transaction)
+	ret0, _ := ret[0].(*entity.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTransaction indicates an expected call of CreateTransaction.
+func (mr *MockTransactionRepositoryMockRecorder) CreateTransaction(ctx, transaction any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTransaction", reflect.TypeOf((*MockTransactionRepository)(nil).CreateTransaction), ctx, transaction)
+}
+
+// DeleteTransaction mocks base method.
+func (m *MockTransactionRepository) DeleteTransaction(ctx context.Context, id uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTransaction", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTransaction indicates an expected call of DeleteTransaction.
+func (mr *MockTransactionRepositoryMockRecorder) DeleteTransaction(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTransaction", reflect.TypeOf((*MockTransactionRepository)(nil).DeleteTransaction), ctx, id)
+}
+
+// GetTransactionById mocks base method.
+func (m *MockTransactionRepository) GetTransactionById(ctx context.Context, id uint64) (*entity.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransactionById", ctx, id)
+	ret0, _ := ret[0].(*entity.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransactionById indicates an expected call of GetTransactionById.
+func (mr *MockTransactionRepositoryMockRecorder) GetTransactionById(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactionById", reflect.TypeOf((*MockTransactionRepository)(nil).GetTransactionById), ctx, id)
+}
+
+// GetTransactionsByWalletId mocks base method.
+func (m *MockTransactionRepository) GetTransactionsByWalletId(ctx context.Context, walletId uint64) ([]entity.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransactionsByWalletId", ctx, walletId)
+	ret0, _ := ret[0].([]entity.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransactionsByWalletId indicates an expected call of GetTransactionsByWalletId.
+func (mr *MockTransactionRepositoryMockRecorder) GetTransactionsByWalletId(ctx, walletId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactionsByWalletId", reflect.TypeOf((*MockTransactionRepository)(nil).GetTransactionsByWalletId), ctx, walletId)
+}
+
+// UpdateTransaction mocks base method.
+func (m *MockTransactionRepository) UpdateTransaction(ctx context.Context, transaction *entity.Transaction) (*entity.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTransaction", ctx, transaction)
+	ret0, _ := ret[0].(*entity.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateTransaction indicates an expected call of UpdateTransaction.
+func (mr *MockTransactionRepositoryMockRecorder) UpdateTransaction(ctx, transaction any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTransaction", reflect.TypeOf((*MockTransactionRepository)(nil).UpdateTransaction), ctx, transaction)
+}
+
+// MockTransferRepository is a mock of TransferRepository interface.
+type MockTransferRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockTransferRepositoryMockRecorder
+}
+
+// MockTransferRepositoryMockRecorder is the mock recorder for MockTransferRepository.
+type MockTransferRepositoryMockRecorder struct {
+	mock *MockTransferRepository
+}
+
+// NewMockTransferRepository creates a new mock instance.
+func NewMockTransferRepository(ctrl *gomock.Controller) *MockTransferRepository {
+	mock := &MockTransferRepository{ctrl: ctrl}
+	mock.recorder = &MockTransferRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTransferRepository) EXPECT() *MockTransferRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateTransfer mocks base method.
+func (m *MockTransferRepository) CreateTransfer(ctx context.Context, transfer *entity.Transfer) (*entity.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTransfer", ctx, transfer)
+	ret0, _ := ret[0].(*entity.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTransfer indicates an expected call of CreateTransfer.
+func (mr *MockTransferRepositoryMockRecorder) CreateTransfer(ctx, transfer any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTransfer", reflect.TypeOf((*MockTransferRepository)(nil).CreateTransfer), ctx, transfer)
+}
+
+// MockIdempotencyRepository is a mock of IdempotencyRepository interface.
+type MockIdempotencyRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockIdempotencyRepositoryMockRecorder
+}
+
+// MockIdempotencyRepositoryMockRecorder is the mock recorder for MockIdempotencyRepository.
+type MockIdempotencyRepositoryMockRecorder struct {
+	mock *MockIdempotencyRepository
+}
+
+// NewMockIdempotencyRepository creates a new mock instance.
+func NewMockIdempotencyRepository(ctrl *gomock.Controller) *MockIdempotencyRepository {
+	mock := &MockIdempotencyRepository{ctrl: ctrl}
+	mock.recorder = &MockIdempotencyRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIdempotencyRepository) EXPECT() *MockIdempotencyRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateIdempotencyKey mocks base method.
+func (m *MockIdempotencyRepository) CreateIdempotencyKey(ctx context.Context, idempotencyKey *entity.IdempotencyKey) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateIdempotencyKey", ctx, idempotencyKey)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateIdempotencyKey indicates an expected call of CreateIdempotencyKey.
+func (mr *MockIdempotencyRepositoryMockRecorder) CreateIdempotencyKey(ctx, idempotencyKey any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWallet", reflect.TypeOf((*MockWalletRepository)(nil).UpdateWallet), wallet)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateIdempotencyKey", reflect.TypeOf((*MockIdempotencyRepository)(nil).CreateIdempotencyKey), ctx, idempotencyKey)
 }
 
-// WalletBelongsToUser mocks base method.
-func (m *MockWalletRepository) WalletBelongsToUser(id, userId uint64) bool {
+// DeleteIdempotencyKey mocks base method.
+func (m *MockIdempotencyRepository) DeleteIdempotencyKey(ctx context.Context, id uint64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "WalletBelongsToUser", id, userId)
+	ret := m.ctrl.Call(m, "DeleteIdempotencyKey", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteIdempotencyKey indicates an expected call of DeleteIdempotencyKey.
+func (mr *MockIdempotencyRepositoryMockRecorder) DeleteIdempotencyKey(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteIdempotencyKey", reflect.TypeOf((*MockIdempotencyRepository)(nil).DeleteIdempotencyKey), ctx, id)
+}
+
+// DeleteIdempotencyKeysCompletedBefore mocks base method.
+func (m *MockIdempotencyRepository) DeleteIdempotencyKeysCompletedBefore(ctx context.Context, completedBefore time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteIdempotencyKeysCompletedBefore", ctx, completedBefore)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteIdempotencyKeysCompletedBefore indicates an expected call of DeleteIdempotencyKeysCompletedBefore.
+func (mr *MockIdempotencyRepositoryMockRecorder) DeleteIdempotencyKeysCompletedBefore(ctx, completedBefore any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteIdempotencyKeysCompletedBefore", reflect.TypeOf((*MockIdempotencyRepository)(nil).DeleteIdempotencyKeysCompletedBefore), ctx, completedBefore)
+}
+
+// GetIdempotencyKey mocks base method.
+func (m *MockIdempotencyRepository) GetIdempotencyKey(ctx context.Context, userId uint64, key string) (*entity.IdempotencyKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIdempotencyKey", ctx, userId, key)
+	ret0, _ := ret[0].(*entity.IdempotencyKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIdempotencyKey indicates an expected call of GetIdempotencyKey.
+func (mr *MockIdempotencyRepositoryMockRecorder) GetIdempotencyKey(ctx, userId, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIdempotencyKey", reflect.TypeOf((*MockIdempotencyRepository)(nil).GetIdempotencyKey), ctx, userId, key)
+}
+
+// ReserveStaleIdempotencyKey mocks base method.
+func (m *MockIdempotencyRepository) ReserveStaleIdempotencyKey(ctx context.Context, id uint64, requestHash string, staleBefore, expiredBefore time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReserveStaleIdempotencyKey", ctx, id, requestHash, staleBefore, expiredBefore)
 	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReserveStaleIdempotencyKey indicates an expected call of ReserveStaleIdempotencyKey.
+func (mr *MockIdempotencyRepositoryMockRecorder) ReserveStaleIdempotencyKey(ctx, id, requestHash, staleBefore, expiredBefore any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReserveStaleIdempotencyKey", reflect.TypeOf((*MockIdempotencyRepository)(nil).ReserveStaleIdempotencyKey), ctx, id, requestHash, staleBefore, expiredBefore)
+}
+
+// UpdateIdempotencyKey mocks base method.
+func (m *MockIdempotencyRepository) UpdateIdempotencyKey(ctx context.Context, idempotencyKey *entity.IdempotencyKey) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateIdempotencyKey", ctx, idempotencyKey)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateIdempotencyKey indicates an expected call of UpdateIdempotencyKey.
+func (mr *MockIdempotencyRepositoryMockRecorder) UpdateIdempotencyKey(ctx, idempotencyKey any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateIdempotencyKey", reflect.TypeOf((*MockIdempotencyRepository)(nil).UpdateIdempotencyKey), ctx, idempotencyKey)
+}
+
+// MockExchangeRateRepository is a mock of ExchangeRateRepository interface.
+type MockExchangeRateRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockExchangeRateRepositoryMockRecorder
+}
+
+// MockExchangeRateRepositoryMockRecorder is the mock recorder for MockExchangeRateRepository.
+type MockExchangeRateRepositoryMockRecorder struct {
+	mock *MockExchangeRateRepository
+}
+
+// NewMockExchangeRateRepository creates a new mock instance.
+func NewMockExchangeRateRepository(ctrl *gomock.Controller) *MockExchangeRateRepository {
+	mock := &MockExchangeRateRepository{ctrl: ctrl}
+	mock.recorder = &MockExchangeRateRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExchangeRateRepository) EXPECT() *MockExchangeRateRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetEffectiveExchangeRates mocks base method.
+func (m *MockExchangeRateRepository) GetEffectiveExchangeRates(ctx context.Context, date time.Time) ([]entity.ExchangeRate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEffectiveExchangeRates", ctx, date)
+	ret0, _ := ret[0].([]entity.ExchangeRate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEffectiveExchangeRates indicates an expected call of GetEffectiveExchangeRates.
+func (mr *MockExchangeRateRepositoryMockRecorder) GetEffectiveExchangeRates(ctx, date any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEffectiveExchangeRates", reflect.TypeOf((*MockExchangeRateRepository)(nil).GetEffectiveExchangeRates), ctx, date)
+}
+
+// SaveExchangeRates mocks base method.
+func (m *MockExchangeRateRepository) SaveExchangeRates(ctx context.Context, exchangeRates []entity.ExchangeRate) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveExchangeRates", ctx, exchangeRates)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveExchangeRates indicates an expected call of SaveExchangeRates.
+func (mr *MockExchangeRateRepositoryMockRecorder) SaveExchangeRates(ctx, exchangeRates any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveExchangeRates", reflect.TypeOf((*MockExchangeRateRepository)(nil).SaveExchangeRates), ctx, exchangeRates)
+}
+
+// MockPreferencesRepository is a mock of PreferencesRepository interface.
+type MockPreferencesRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockPreferencesRepositoryMockRecorder
+}
+
+// MockPreferencesRepositoryMockRecorder is the mock recorder for MockPreferencesRepository.
+type MockPreferencesRepositoryMockRecorder struct {
+	mock *MockPreferencesRepository
+}
+
+// NewMockPreferencesRepository creates a new mock instance.
+func NewMockPreferencesRepository(ctrl *gomock.Controller) *MockPreferencesRepository {
+	mock := &MockPreferencesRepository{ctrl: ctrl}
+	mock.recorder = &MockPreferencesRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPreferencesRepository) EXPECT() *MockPreferencesRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetPreferences mocks base method.
+func (m *MockPreferencesRepository) GetPreferences(ctx context.Context, userId uint64) (*entity.Preferences, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPreferences", ctx, userId)
+	ret0, _ := ret[0].(*entity.Preferences)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPreferences indicates an expected call of GetPreferences.
+func (mr *MockPreferencesRepositoryMockRecorder) GetPreferences(ctx, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPreferences", reflect.TypeOf((*MockPreferencesRepository)(nil).GetPreferences), ctx, userId)
+}
+
+// SavePreferences mocks base method.
+func (m *MockPreferencesRepository) SavePreferences(ctx context.Context, preferences *entity.Preferences) (*entity.Preferences, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SavePreferences", ctx, preferences)
+	ret0, _ := ret[0].(*entity.Preferences)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SavePreferences indicates an expected call of SavePreferences.
+func (mr *MockPreferencesRepositoryMockRecorder) SavePreferences(ctx, preferences any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SavePreferences", reflect.TypeOf((*MockPreferencesRepository)(nil).SavePreferences), ctx, preferences)
+}
+
+// MockWalletMemberRepository is a mock of WalletMemberRepository interface.
+type MockWalletMemberRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockWalletMemberRepositoryMockRecorder
+}
+
+// MockWalletMemberRepositoryMockRecorder is the mock recorder for MockWalletMemberRepository.
+type MockWalletMemberRepositoryMockRecorder struct {
+	mock *MockWalletMemberRepository
+}
+
+// NewMockWalletMemberRepository creates a new mock instance.
+func NewMockWalletMemberRepository(ctrl *gomock.Controller) *MockWalletMemberRepository {
+	mock := &MockWalletMemberRepository{ctrl: ctrl}
+	mock.recorder = &MockWalletMemberRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWalletMemberRepository) EXPECT() *MockWalletMemberRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateWalletMember mocks base method.
+func (m *MockWalletMemberRepository) CreateWalletMember(ctx context.Context, walletMember *entity.WalletMember) (*entity.WalletMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWalletMember", ctx, walletMember)
+	ret0, _ := ret[0].(*entity.WalletMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWalletMember indicates an expected call of CreateWalletMember.
+func (mr *MockWalletMemberRepositoryMockRecorder) CreateWalletMember(ctx, walletMember any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWalletMember", reflect.TypeOf((*MockWalletMemberRepository)(nil).CreateWalletMember), ctx, walletMember)
+}
+
+// DeleteWalletMember mocks base method.
+func (m *MockWalletMemberRepository) DeleteWalletMember(ctx context.Context, id uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWalletMember", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWalletMember indicates an expected call of DeleteWalletMember.
+func (mr *MockWalletMemberRepositoryMockRecorder) DeleteWalletMember(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWalletMember", reflect.TypeOf((*MockWalletMemberRepository)(nil).DeleteWalletMember), ctx, id)
+}
+
+// GetPendingInvitations mocks base method.
+func (m *MockWalletMemberRepository) GetPendingInvitations(ctx context.Context, userId uint64) ([]entity.WalletMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPendingInvitations", ctx, userId)
+	ret0, _ := ret[0].([]entity.WalletMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPendingInvitations indicates an expected call of GetPendingInvitations.
+func (mr *MockWalletMemberRepositoryMockRecorder) GetPendingInvitations(ctx, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPendingInvitations", reflect.TypeOf((*MockWalletMemberRepository)(nil).GetPendingInvitations), ctx, userId)
+}
+
+// GetWalletMember mocks base method.
+func (m *MockWalletMemberRepository) GetWalletMember(ctx context.Context, walletId, userId uint64) (*entity.WalletMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWalletMember", ctx, walletId, userId)
+	ret0, _ := ret[0].(*entity.WalletMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWalletMember indicates an expected call of GetWalletMember.
+func (mr *MockWalletMemberRepositoryMockRecorder) GetWalletMember(ctx, walletId, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWalletMember", reflect.TypeOf((*MockWalletMemberRepository)(nil).GetWalletMember), ctx, walletId, userId)
+}
+
+// GetWalletMemberById mocks base method.
+func (m *MockWalletMemberRepository) GetWalletMemberById(ctx context.Context, id uint64) (*entity.WalletMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWalletMemberById", ctx, id)
+	ret0, _ := ret[0].(*entity.WalletMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWalletMemberById indicates an expected call of GetWalletMemberById.
+func (mr *MockWalletMemberRepositoryMockRecorder) GetWalletMemberById(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWalletMemberById", reflect.TypeOf((*MockWalletMemberRepository)(nil).GetWalletMemberById), ctx, id)
+}
+
+// GetWalletMembers mocks base method.
+func (m *MockWalletMemberRepository) GetWalletMembers(ctx context.Context, walletId uint64) ([]entity.WalletMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWalletMembers", ctx, walletId)
+	ret0, _ := ret[0].([]entity.WalletMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWalletMembers indicates an expected call of GetWalletMembers.
+func (mr *MockWalletMemberRepositoryMockRecorder) GetWalletMembers(ctx, walletId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWalletMembers", reflect.TypeOf((*MockWalletMemberRepository)(nil).GetWalletMembers), ctx, walletId)
+}
+
+// UpdateWalletMember mocks base method.
+func (m *MockWalletMemberRepository) UpdateWalletMember(ctx context.Context, walletMember *entity.WalletMember) (*entity.WalletMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWalletMember", ctx, walletMember)
+	ret0, _ := ret[0].(*entity.WalletMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateWalletMember indicates an expected call of UpdateWalletMember.
+func (mr *MockWalletMemberRepositoryMockRecorder) UpdateWalletMember(ctx, walletMember any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWalletMember", reflect.TypeOf((*MockWalletMemberRepository)(nil).UpdateWalletMember), ctx, walletMember)
+}
+
+// MockWalletAuditRepository is a mock of WalletAuditRepository interface.
+type MockWalletAuditRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockWalletAuditRepositoryMockRecorder
+}
+
+// MockWalletAuditRepositoryMockRecorder is the mock recorder for MockWalletAuditRepository.
+type MockWalletAuditRepositoryMockRecorder struct {
+	mock *MockWalletAuditRepository
+}
+
+// NewMockWalletAuditRepository creates a new mock instance.
+func NewMockWalletAuditRepository(ctrl *gomock.Controller) *MockWalletAuditRepository {
+	mock := &MockWalletAuditRepository{ctrl: ctrl}
+	mock.recorder = &MockWalletAuditRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWalletAuditRepository) EXPECT() *MockWalletAuditRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateWalletAudit mocks base method.
+func (m *MockWalletAuditRepository) CreateWalletAudit(ctx context.Context, walletAudit *entity.WalletAudit) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWalletAudit", ctx, walletAudit)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateWalletAudit indicates an expected call of CreateWalletAudit.
+func (mr *MockWalletAuditRepositoryMockRecorder) CreateWalletAudit(ctx, walletAudit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWalletAudit", reflect.TypeOf((*MockWalletAuditRepository)(nil).CreateWalletAudit), ctx, walletAudit)
+}
+
+// GetWalletAuditsByWalletId mocks base method.
+func (m *MockWalletAuditRepository) GetWalletAuditsByWalletId(ctx context.Context, walletId uint64) ([]entity.WalletAudit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWalletAuditsByWalletId", ctx, walletId)
+	ret0, _ := ret[0].([]entity.WalletAudit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWalletAuditsByWalletId indicates an expected call of GetWalletAuditsByWalletId.
+func (mr *MockWalletAuditRepositoryMockRecorder) GetWalletAuditsByWalletId(ctx, walletId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWalletAuditsByWalletId", reflect.TypeOf((*MockWalletAuditRepository)(nil).GetWalletAuditsByWalletId), ctx, walletId)
+}
+
+// MockOutboxRepository is a mock of OutboxRepository interface.
+type MockOutboxRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockOutboxRepositoryMockRecorder
+}
+
+// MockOutboxRepositoryMockRecorder is the mock recorder for MockOutboxRepository.
+type MockOutboxRepositoryMockRecorder struct {
+	mock *MockOutboxRepository
+}
+
+// NewMockOutboxRepository creates a new mock instance.
+func NewMockOutboxRepository(ctrl *gomock.Controller) *MockOutboxRepository {
+	mock := &MockOutboxRepository{ctrl: ctrl}
+	mock.recorder = &MockOutboxRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOutboxRepository) EXPECT() *MockOutboxRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ClaimOutboxEvents mocks base method.
+func (m *MockOutboxRepository) ClaimOutboxEvents(ctx context.Context, limit int, claimedUntil time.Time) ([]entity.OutboxEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimOutboxEvents", ctx, limit, claimedUntil)
+	ret0, _ := ret[0].([]entity.OutboxEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimOutboxEvents indicates an expected call of ClaimOutboxEvents.
+func (mr *MockOutboxRepositoryMockRecorder) ClaimOutboxEvents(ctx, limit, claimedUntil any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimOutboxEvents", reflect.TypeOf((*MockOutboxRepository)(nil).ClaimOutboxEvents), ctx, limit, claimedUntil)
+}
+
+// CreateOutboxEvent mocks base method.
+func (m *MockOutboxRepository) CreateOutboxEvent(ctx context.Context, outboxEvent *entity.OutboxEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOutboxEvent", ctx, outboxEvent)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateOutboxEvent indicates an expected call of CreateOutboxEvent.
+func (mr *MockOutboxRepositoryMockRecorder) CreateOutboxEvent(ctx, outboxEvent any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOutboxEvent", reflect.TypeOf((*MockOutboxRepository)(nil).CreateOutboxEvent), ctx, outboxEvent)
+}
+
+// DeletePublishedOutboxEvents mocks base method.
+func (m *MockOutboxRepository) DeletePublishedOutboxEvents(ctx context.Context, publishedBefore time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePublishedOutboxEvents", ctx, publishedBefore)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeletePublishedOutboxEvents indicates an expected call of DeletePublishedOutboxEvents.
+func (mr *MockOutboxRepositoryMockRecorder) DeletePublishedOutboxEvents(ctx, publishedBefore any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePublishedOutboxEvents", reflect.TypeOf((*MockOutboxRepository)(nil).DeletePublishedOutboxEvents), ctx, publishedBefore)
+}
+
+// MarkOutboxEventPublished mocks base method.
+func (m *MockOutboxRepository) MarkOutboxEventPublished(ctx context.Context, id uint64, publishedAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkOutboxEventPublished", ctx, id, publishedAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkOutboxEventPublished indicates an expected call of MarkOutboxEventPublished.
+func (mr *MockOutboxRepositoryMockRecorder) MarkOutboxEventPublished(ctx, id, publishedAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkOutboxEventPublished", reflect.TypeOf((*MockOutboxRepository)(nil).MarkOutboxEventPublished), ctx, id, publishedAt)
+}
+
+// ReleaseOutboxEvents mocks base method.
+func (m *MockOutboxRepository) ReleaseOutboxEvents(ctx context.Context, ids []uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseOutboxEvents", ctx, ids)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReleaseOutboxEvents indicates an expected call of ReleaseOutboxEvents.
+func (mr *MockOutboxRepositoryMockRecorder) ReleaseOutboxEvents(ctx, ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseOutboxEvents", reflect.TypeOf((*MockOutboxRepository)(nil).ReleaseOutboxEvents), ctx, ids)
+}
+
+// MockUnitOfWork is a mock of UnitOfWork interface.
+type MockUnitOfWork struct {
+	ctrl     *gomock.Controller
+	recorder *MockUnitOfWorkMockRecorder
+}
+
+// MockUnitOfWorkMockRecorder is the mock recorder for MockUnitOfWork.
+type MockUnitOfWorkMockRecorder struct {
+	mock *MockUnitOfWork
+}
+
+// NewMockUnitOfWork creates a new mock instance.
+func NewMockUnitOfWork(ctrl *gomock.Controller) *MockUnitOfWork {
+	mock := &MockUnitOfWork{ctrl: ctrl}
+	mock.recorder = &MockUnitOfWorkMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUnitOfWork) EXPECT() *MockUnitOfWorkMockRecorder {
+	return m.recorder
+}
+
+// Do mocks base method.
+func (m *MockUnitOfWork) Do(ctx context.Context, fn func(context.Context, *repository.Manager) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Do", ctx, fn)
+	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// WalletBelongsToUser indicates an expected call of WalletBelongsToUser.
-func (mr *MockWalletRepositoryMockRecorder) WalletBelongsToUser(id, userId any) *gomock.Call {
+// Do indicates an expected call of Do.
+func (mr *MockUnitOfWorkMockRecorder) Do(ctx, fn any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WalletBelongsToUser", reflect.TypeOf((*MockWalletRepository)(nil).WalletBelongsToUser), id, userId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Do", reflect.TypeOf((*MockUnitOfWork)(nil).Do), ctx, fn)
 }
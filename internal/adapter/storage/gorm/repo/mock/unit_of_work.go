@@ -0,0 +1,22 @@
+package mock
+
+import (
+	"context"
+	"github.com/khivuksergey/portmonetka.wallet/internal/core/port/repository"
+)
+
+// passThroughUnitOfWork runs functions on the manager it belongs to without a transaction,
+// which is enough for services tested with mocked repositories.
+type passThroughUnitOfWork struct {
+	repositories *repository.Manager
+}
+
+func (u passThroughUnitOfWork) Do(ctx context.Context, fn func(ctx context.Context, repositories *repository.Manager) error) error {
+	return fn(ctx, u.repositories)
+}
+
+// WithPassThroughUnitOfWork sets the unit of work of the manager to run functions on the manager itself.
+func WithPassThroughUnitOfWork(repositories *repository.Manager) *repository.Manager {
+	repositories.UnitOfWork = passThroughUnitOfWork{repositories: repositories}
+	return repositories
+}
@@ -0,0 +1,79 @@
+package repo
+
+import (
+	"context"
+	"github.com/khivuksergey/portmonetka.wallet/internal/adapter/storage/entity"
+	"github.com/khivuksergey/portmonetka.wallet/internal/core/port/repository"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+type outboxRepository struct {
+	db        *gorm.DB
+	tableName string
+}
+
+func NewOutboxRepository(db *gorm.DB) repository.OutboxRepository {
+	return &outboxRepository{db: db, tableName: entity.OutboxEvent{}.TableName()}
+}
+
+func (o *outboxRepository) CreateOutboxEvent(ctx context.Context, outboxEvent *entity.OutboxEvent) error {
+	return o.db.WithContext(ctx).Create(outboxEvent).Error
+}
+
+// ClaimOutboxEvents selects the events with FOR UPDATE SKIP LOCKED, so concurrent relays don't claim
+// the same events, and claims them in its own transaction, which is committed before they are published.
+// SQLite, which has no row locks, ignores the clause, but serializes the transactions anyway.
+func (o *outboxRepository) ClaimOutboxEvents(ctx context.Context, limit int, claimedUntil time.Time) ([]entity.OutboxEvent, error) {
+	var outboxEvents []entity.OutboxEvent
+	err := o.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("published_at IS NULL AND (claimed_until IS NULL OR claimed_until < ?)", time.Now().UTC()).
+			Order("id").
+			Limit(limit).
+			Find(&outboxEvents).Error
+		if err != nil || len(outboxEvents) == 0 {
+			return err
+		}
+		ids := make([]uint64, len(outboxEvents))
+		for i := range outboxEvents {
+			ids[i] = outboxEvents[i].Id
+			outboxEvents[i].ClaimedUntil = &claimedUntil
+		}
+		return tx.Model(&entity.OutboxEvent{}).
+			Where("id IN ?", ids).
+			Update("claimed_until", claimedUntil.UTC()).
+			Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return outboxEvents, nil
+}
+
+func (o *outboxRepository) MarkOutboxEventPublished(ctx context.Context, id uint64, publishedAt time.Time) error {
+	return o.db.WithContext(ctx).
+		Model(&entity.OutboxEvent{}).
+		Where("id = ?", id).
+		Updates(map[string]any{"published_at": publishedAt.UTC(), "claimed_until": nil}).
+		Error
+}
+
+func (o *outboxRepository) ReleaseOutboxEvents(ctx context.Context, ids []uint64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return o.db.WithContext(ctx).
+		Model(&entity.OutboxEvent{}).
+		Where("id IN ? AND published_at IS NULL", ids).
+		Update("claimed_until", nil).
+		Error
+}
+
+func (o *outboxRepository) DeletePublishedOutboxEvents(ctx context.Context, publishedBefore time.Time) (int64, error) {
+	result := o.db.WithContext(ctx).
+		Where("published_at IS NOT NULL AND published_at < ?", publishedBefore.UTC()).
+		Delete(&entity.OutboxEvent{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,47 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"github.com/khivuksergey/portmonetka.wallet/internal/adapter/storage/entity"
+	"github.com/khivuksergey/portmonetka.wallet/internal/core/port/repository"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type preferencesRepository struct {
+	db        *gorm.DB
+	tableName string
+}
+
+func NewPreferencesRepository(db *gorm.DB) repository.PreferencesRepository {
+	return &preferencesRepository{db: db, tableName: entity.Preferences{}.TableName()}
+}
+
+func (p *preferencesRepository) GetPreferences(ctx context.Context, userId uint64) (*entity.Preferences, error) {
+	preferences := &entity.Preferences{}
+	result := p.db.WithContext(ctx).Where("user_id = ?", userId).First(preferences)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return preferences, nil
+}
+
+// SavePreferences creates or replaces the user's preferences.
+func (p *preferencesRepository) SavePreferences(ctx context.Context, preferences *entity.Preferences) (*entity.Preferences, error) {
+	result := p.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "user_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{
+				"base_currency", "default_wallet_id", "locale", "month_start_day", "updated_at",
+			}),
+		}).
+		Create(preferences)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return p.GetPreferences(ctx, preferences.UserId)
+}
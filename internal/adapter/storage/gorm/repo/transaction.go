@@ -0,0 +1,54 @@
+package repo
+
+import (
+	"context"
+	"github.com/khivuksergey/portmonetka.wallet/internal/adapter/storage/entity"
+	"github.com/khivuksergey/portmonetka.wallet/internal/core/port/repository"
+	"gorm.io/gorm"
+)
+
+type transactionRepository struct {
+	db        *gorm.DB
+	tableName string
+}
+
+func NewTransactionRepository(db *gorm.DB) repository.TransactionRepository {
+	return &transactionRepository{db: db, tableName: entity.Transaction{}.TableName()}
+}
+
+func (t *transactionRepository) GetTransactionById(ctx context.Context, id uint64) (*entity.Transaction, error) {
+	transaction := &entity.Transaction{}
+	result := t.db.WithContext(ctx).First(transaction, id)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return transaction, nil
+}
+
+func (t *transactionRepository) GetTransactionsByWalletId(ctx context.Context, walletId uint64) ([]entity.Transaction, error) {
+	var transactions []entity.Transaction
+	result := t.db.WithContext(ctx).
+		Where("wallet_id = ?", walletId).
+		Order("timestamp desc, id desc").
+		Find(&transactions)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return transactions, nil
+}
+
+func (t *transactionRepository) CreateTransaction(ctx context.Context, transaction *entity.Transaction) (*entity.Transaction, error) {
+	if err := t.db.WithContext(ctx).Create(transaction).Error; err != nil {
+		return nil, err
+	}
+	return transaction, nil
+}
+
+func (t *transactionRepository) UpdateTransaction(ctx context.Context, transaction *entity.Transaction) (*entity.Transaction, error) {
+	err := t.db.WithContext(ctx).Save(transaction).Error
+	return transaction, err
+}
+
+func (t *transactionRepository) DeleteTransaction(ctx context.Context, id uint64) error {
+	return t.db.WithContext(ctx).Delete(&entity.Transaction{}, id).Error
+}
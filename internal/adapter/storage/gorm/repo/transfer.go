@@ -0,0 +1,41 @@
+package repo
+
+import (
+	"context"
+	"github.com/khivuksergey/portmonetka.wallet/internal/adapter/storage/entity"
+	"github.com/khivuksergey/portmonetka.wallet/internal/core/port/repository"
+	"gorm.io/gorm"
+)
+
+type transferRepository struct {
+	db        *gorm.DB
+	tableName string
+}
+
+func NewTransferRepository(db *gorm.DB) repository.TransferRepository {
+	return &transferRepository{db: db, tableName: entity.Transfer{}.TableName()}
+}
+
+// CreateTransfer stores the transfer together with its debit and credit transactions
+// in a single database transaction, so either both wallets are affected or none.
+func (t *transferRepository) CreateTransfer(ctx context.Context, transfer *entity.Transfer) (*entity.Transfer, error) {
+	err := t.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		legs := transfer.Transactions
+		transfer.Transactions = nil
+		if err := tx.Create(transfer).Error; err != nil {
+			return err
+		}
+		for i := range legs {
+			legs[i].TransferId = &transfer.Id
+		}
+		if err := tx.Create(&legs).Error; err != nil {
+			return err
+		}
+		transfer.Transactions = legs
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return transfer, nil
+}
@@ -1,48 +1,137 @@
 package repo
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	serviceerror "github.com/khivuksergey/portmonetka.wallet/error"
 	"github.com/khivuksergey/portmonetka.wallet/internal/adapter/storage/entity"
 	"github.com/khivuksergey/portmonetka.wallet/internal/core/port/repository"
+	"github.com/khivuksergey/portmonetka.wallet/internal/model"
+	"github.com/shopspring/decimal"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"strings"
+	"time"
 )
 
-type walletRepository struct {
-	db        *gorm.DB
-	tableName string
+// currentBalanceExpression computes the wallet balance as its initial amount plus incoming
+// and minus outgoing transactions in the same query that loads the wallet rows.
+var currentBalanceExpression = fmt.Sprintf(
+	"wallets.initial_amount + COALESCE(("+
+		"SELECT SUM(CASE WHEN t.direction = '%s' THEN t.amount ELSE -t.amount END) "+
+		"FROM %s t WHERE t.wallet_id = wallets.id AND t.deleted_at IS NULL"+
+		"), 0)",
+	entity.TransactionDirectionIn,
+	entity.Transaction{}.TableName(),
+)
+
+// sqliteBalanceScale is the number of decimal places SQLite balance is rounded to,
+// as it sums numeric columns as floating point numbers.
+const sqliteBalanceScale = 8
+
+var walletSortColumns = map[string]string{
+	model.WalletSortName:      "wallets.name",
+	model.WalletSortCreatedAt: "wallets.created_at",
+	model.WalletSortUpdatedAt: "wallets.updated_at",
 }
 
-func NewWalletRepository(db *gorm.DB) repository.WalletRepository {
-	return &walletRepository{db: db, tableName: entity.Wallet{}.TableName()}
+type walletRepository struct {
+	db                *gorm.DB
+	tableName         string
+	balanceExpression string
 }
 
-func (w *walletRepository) ExistsWithName(userId uint64, name string) bool {
-	var count int64
-	w.db.Model(&entity.Wallet{}).Where("user_id = ? AND name = ?", userId, name).Count(&count)
-	return count == 1
+func NewWalletRepository(db *gorm.DB) repository.WalletRepository {
+	balanceExpression := currentBalanceExpression
+	if db.Dialector.Name() == "sqlite" {
+		balanceExpression = fmt.Sprintf("ROUND(%s, %d)", currentBalanceExpression, sqliteBalanceScale)
+	}
+	return &walletRepository{
+		db:                db,
+		tableName:         entity.Wallet{}.TableName(),
+		balanceExpression: balanceExpression,
+	}
 }
 
-func (w *walletRepository) GetWalletById(id uint64) (*entity.Wallet, error) {
+func (w *walletRepository) GetWalletById(ctx context.Context, id uint64) (*entity.Wallet, error) {
 	wallet := &entity.Wallet{}
-	result := w.db.First(wallet, id)
+	result := w.withCurrentBalance(ctx).First(wallet, id)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, repository.ErrNotFound
+	}
 	if result.Error != nil {
 		return nil, result.Error
 	}
 	return wallet, nil
 }
 
-func (w *walletRepository) WalletBelongsToUser(id, userId uint64) bool {
-	wallet, err := w.GetWalletById(id)
-	if err != nil || wallet == nil {
-		return false
+// GetWalletForUpdate locks the wallet row until the end of the unit of work and only then
+// reads the wallet, so its balance includes the changes of the previous lock holder.
+// The lock is NO KEY UPDATE, as posting a transaction takes a key share lock on its wallet,
+// which FOR UPDATE would conflict with. SQLite, which has no row locks, ignores the clause.
+func (w *walletRepository) GetWalletForUpdate(ctx context.Context, id uint64) (*entity.Wallet, error) {
+	var ids []uint64
+	result := w.db.WithContext(ctx).
+		Model(&entity.Wallet{}).
+		Clauses(clause.Locking{Strength: "NO KEY UPDATE"}).
+		Where("id = ?", id).
+		Pluck("id", &ids)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if len(ids) == 0 {
+		return nil, repository.ErrNotFound
+	}
+	return w.GetWalletById(ctx, id)
+}
+
+func (w *walletRepository) GetWalletsByUserId(ctx context.Context, userId uint64, walletListQuery model.WalletListQuery, after *model.WalletCursor) ([]entity.Wallet, error) {
+	sortField, desc := walletListQuery.SortField()
+	sortExpression, placeholder, ok := w.sortExpression(sortField)
+	if !ok {
+		return nil, serviceerror.InvalidWalletSort
+	}
+
+	query := w.withCurrentBalance(ctx).Where(
+		"wallets.user_id = ? OR wallets.id IN (SELECT wallet_id FROM "+entity.WalletMember{}.TableName()+
+			" WHERE user_id = ? AND accepted_at IS NOT NULL)",
+		userId, userId,
+	)
+	query = applyWalletFilters(query, walletListQuery)
+
+	comparison, direction := ">", "asc"
+	if desc {
+		comparison, direction = "<", "desc"
+	}
+
+	if after != nil {
+		value, err := walletCursorValue(sortField, after.Value)
+		if err != nil {
+			return nil, serviceerror.InvalidWalletCursor
+		}
+		query = query.Where(
+			fmt.Sprintf("(%[1]s %[2]s %[3]s) OR (%[1]s = %[3]s AND wallets.id %[2]s ?)", sortExpression, comparison, placeholder),
+			value, value, after.Id,
+		)
+	}
+
+	var wallets []entity.Wallet
+	result := query.
+		Order(fmt.Sprintf("%s %s, wallets.id %s", sortExpression, direction, direction)).
+		Limit(walletListQuery.Limit).
+		Find(&wallets)
+	if result.Error != nil {
+		return nil, result.Error
 	}
-	return wallet.UserId == userId
+	return wallets, nil
 }
 
-func (w *walletRepository) GetWalletsByUserId(userId uint64) ([]entity.Wallet, error) {
+func (w *walletRepository) GetAllWalletsByUserId(ctx context.Context, userId uint64) ([]entity.Wallet, error) {
 	var wallets []entity.Wallet
-	result := w.db.
-		Where("user_id = ?", userId).
-		Order("updated_at desc").
+	result := w.withCurrentBalance(ctx).
+		Where("wallets.user_id = ?", userId).
+		Order("wallets.id").
 		Find(&wallets)
 	if result.Error != nil {
 		return nil, result.Error
@@ -50,18 +139,233 @@ func (w *walletRepository) GetWalletsByUserId(userId uint64) ([]entity.Wallet, e
 	return wallets, nil
 }
 
-func (w *walletRepository) CreateWallet(wallet *entity.Wallet) (*entity.Wallet, error) {
-	if err := w.db.Create(wallet).Error; err != nil {
-		return nil, err
+// CreateWallet fails with WalletAlreadyExists if the user has an active wallet with the same name,
+// compared case-insensitively by the unique index on NameKey, as do UpdateWallet and RestoreWallet.
+func (w *walletRepository) CreateWallet(ctx context.Context, wallet *entity.Wallet) (*entity.Wallet, error) {
+	wallet.NameKey = entity.WalletNameKey(wallet.Name)
+	if err := w.db.WithContext(ctx).Create(wallet).Error; err != nil {
+		return nil, walletError(err)
+	}
+	wallet.CurrentBalance = wallet.InitialAmount
+	return wallet, nil
+}
+
+// UpdateWallet saves the wallet only if its version wasn't changed since it was read
+// and increments the version, so concurrent updates can't overwrite each other.
+func (w *walletRepository) UpdateWallet(ctx context.Context, wallet *entity.Wallet) (*entity.Wallet, error) {
+	expectedVersion := wallet.Version
+	wallet.Version++
+	wallet.NameKey = entity.WalletNameKey(wallet.Name)
+	result := w.db.WithContext(ctx).
+		Model(wallet).
+		Where("version = ?", expectedVersion).
+		Select("*").
+		Updates(wallet)
+	if result.Error != nil {
+		return wallet, walletError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, serviceerror.WalletVersionMismatch
+	}
+	return wallet, w.withCurrentBalance(ctx).First(wallet, wallet.Id).Error
+}
+
+func (w *walletRepository) DeleteWallet(ctx context.Context, id uint64) error {
+	return w.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&entity.Wallet{}, id).Error; err != nil {
+			return err
+		}
+		return clearDefaultWallet(tx, id)
+	})
+}
+
+func (w *walletRepository) DeleteWalletWithVersion(ctx context.Context, id, version uint64) error {
+	return w.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("version = ?", version).Delete(&entity.Wallet{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return serviceerror.WalletVersionMismatch
+		}
+		return clearDefaultWallet(tx, id)
+	})
+}
+
+// clearDefaultWallet unsets the deleted wallet in preferences, as the foreign key only does it on purge.
+func clearDefaultWallet(tx *gorm.DB, walletId uint64) error {
+	return tx.Model(&entity.Preferences{}).
+		Where("default_wallet_id = ?", walletId).
+		Update("default_wallet_id", nil).
+		Error
+}
+
+func (w *walletRepository) GetDeletedWalletById(ctx context.Context, id uint64) (*entity.Wallet, error) {
+	wallet := &entity.Wallet{}
+	result := w.withCurrentBalance(ctx).
+		Unscoped().
+		Where("wallets.deleted_at IS NOT NULL").
+		First(wallet, id)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, repository.ErrNotFound
+	}
+	if result.Error != nil {
+		return nil, result.Error
 	}
 	return wallet, nil
 }
 
-func (w *walletRepository) UpdateWallet(wallet *entity.Wallet) (*entity.Wallet, error) {
-	err := w.db.Save(wallet).Error
-	return wallet, err
+func (w *walletRepository) GetDeletedWalletsByUserId(ctx context.Context, userId uint64) ([]entity.Wallet, error) {
+	var wallets []entity.Wallet
+	result := w.withCurrentBalance(ctx).
+		Unscoped().
+		Where("wallets.user_id = ? AND wallets.deleted_at IS NOT NULL", userId).
+		Order("wallets.deleted_at desc, wallets.id desc").
+		Find(&wallets)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return wallets, nil
+}
+
+func (w *walletRepository) RestoreWallet(ctx context.Context, id uint64) (*entity.Wallet, error) {
+	result := w.db.WithContext(ctx).
+		Unscoped().
+		Model(&entity.Wallet{}).
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		Updates(map[string]any{
+			"deleted_at": nil,
+			"version":    gorm.Expr("version + 1"),
+		})
+	if result.Error != nil {
+		return nil, walletError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, repository.ErrNotFound
+	}
+	return w.GetWalletById(ctx, id)
+}
+
+// PurgeWallet permanently deletes the soft-deleted wallet together with its transactions and transfers.
+func (w *walletRepository) PurgeWallet(ctx context.Context, id uint64) error {
+	return w.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return purgeWallets(tx, []uint64{id})
+	})
+}
+
+// PurgeWalletsDeletedBefore permanently deletes wallets which were soft-deleted
+// before the given time and returns the number of purged wallets.
+func (w *walletRepository) PurgeWalletsDeletedBefore(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	var ids []uint64
+	err := w.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Unscoped().
+			Model(&entity.Wallet{}).
+			Where("deleted_at IS NOT NULL AND deleted_at < ?", deletedBefore.UTC()).
+			Pluck("id", &ids).Error
+		if err != nil || len(ids) == 0 {
+			return err
+		}
+		return purgeWallets(tx, ids)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return int64(len(ids)), nil
+}
+
+// purgeWallets deletes the wallets with their transactions and the transfers from or to them.
+// The legs of the transfers in other wallets are detached and stay as standalone transactions,
+// so the balances of those wallets don't change.
+func purgeWallets(tx *gorm.DB, ids []uint64) error {
+	transfers := tx.Model(&entity.Transfer{}).
+		Select("id").
+		Where("source_wallet_id IN ? OR target_wallet_id IN ?", ids, ids)
+	err := tx.Unscoped().
+		Model(&entity.Transaction{}).
+		Where("transfer_id IN (?)", transfers).
+		Update("transfer_id", nil).Error
+	if err != nil {
+		return err
+	}
+	err = tx.Unscoped().
+		Where("wallet_id IN ?", ids).
+		Delete(&entity.Transaction{}).Error
+	if err != nil {
+		return err
+	}
+	err = tx.Where("source_wallet_id IN ? OR target_wallet_id IN ?", ids, ids).
+		Delete(&entity.Transfer{}).Error
+	if err != nil {
+		return err
+	}
+	return tx.Unscoped().
+		Where("id IN ? AND deleted_at IS NOT NULL", ids).
+		Delete(&entity.Wallet{}).Error
+}
+
+// walletError translates the violation of the unique wallet name index, which is reported
+// as gorm.ErrDuplicatedKey by the dialects with error translation enabled.
+func walletError(err error) error {
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return serviceerror.WalletAlreadyExists
+	}
+	return err
+}
+
+func (w *walletRepository) withCurrentBalance(ctx context.Context) *gorm.DB {
+	return w.db.WithContext(ctx).Select("wallets.*, " + w.balanceExpression + " AS current_balance")
+}
+
+// sortExpression returns the expression wallets are ordered by and the placeholder
+// the cursor value is compared with. Balance is cast explicitly, because SQLite
+// doesn't convert a text parameter when comparing it with a computed number.
+func (w *walletRepository) sortExpression(sortField string) (string, string, bool) {
+	if sortField == model.WalletSortBalance {
+		return w.balanceExpression, "CAST(? AS NUMERIC)", true
+	}
+	column, ok := walletSortColumns[sortField]
+	return column, "?", ok
+}
+
+func applyWalletFilters(query *gorm.DB, walletListQuery model.WalletListQuery) *gorm.DB {
+	if walletListQuery.Currency != "" {
+		query = query.Where("wallets.currency = ?", strings.ToUpper(walletListQuery.Currency))
+	}
+	if walletListQuery.Type != "" {
+		query = query.Where("wallets.type = ?", walletListQuery.Type)
+	}
+	if walletListQuery.Name != "" {
+		query = query.Where(`wallets.name_key LIKE ? ESCAPE '\'`, "%"+escapeLike(entity.WalletNameKey(walletListQuery.Name))+"%")
+	}
+	if walletListQuery.CreatedFrom != nil {
+		query = query.Where("wallets.created_at >= ?", walletListQuery.CreatedFrom.UTC())
+	}
+	if walletListQuery.CreatedTo != nil {
+		query = query.Where("wallets.created_at < ?", walletListQuery.CreatedTo.UTC())
+	}
+	if walletListQuery.UpdatedFrom != nil {
+		query = query.Where("wallets.updated_at >= ?", walletListQuery.UpdatedFrom.UTC())
+	}
+	if walletListQuery.UpdatedTo != nil {
+		query = query.Where("wallets.updated_at < ?", walletListQuery.UpdatedTo.UTC())
+	}
+	return query
+}
+
+// walletCursorValue converts the cursor value back to the type of the sort field,
+// so it is compared with the column the same way the rows were ordered.
+func walletCursorValue(sortField, value string) (any, error) {
+	switch sortField {
+	case model.WalletSortCreatedAt, model.WalletSortUpdatedAt:
+		t, err := time.Parse(time.RFC3339Nano, value)
+		return t.UTC(), err
+	case model.WalletSortBalance:
+		return decimal.NewFromString(value)
+	default:
+		return value, nil
+	}
 }
 
-func (w *walletRepository) DeleteWallet(id uint64) error {
-	return w.db.Delete(&entity.Wallet{}, id).Error
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
 }
@@ -0,0 +1,30 @@
+package repo
+
+import (
+	"context"
+	"github.com/khivuksergey/portmonetka.wallet/internal/adapter/storage/entity"
+	"github.com/khivuksergey/portmonetka.wallet/internal/core/port/repository"
+	"gorm.io/gorm"
+)
+
+type walletAuditRepository struct {
+	db        *gorm.DB
+	tableName string
+}
+
+func NewWalletAuditRepository(db *gorm.DB) repository.WalletAuditRepository {
+	return &walletAuditRepository{db: db, tableName: entity.WalletAudit{}.TableName()}
+}
+
+func (a *walletAuditRepository) CreateWalletAudit(ctx context.Context, walletAudit *entity.WalletAudit) error {
+	return a.db.WithContext(ctx).Create(walletAudit).Error
+}
+
+func (a *walletAuditRepository) GetWalletAuditsByWalletId(ctx context.Context, walletId uint64) ([]entity.WalletAudit, error) {
+	var walletAudits []entity.WalletAudit
+	result := a.db.WithContext(ctx).Where("wallet_id = ?", walletId).Order("id").Find(&walletAudits)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return walletAudits, nil
+}
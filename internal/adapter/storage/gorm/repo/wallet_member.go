@@ -0,0 +1,82 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"github.com/khivuksergey/portmonetka.wallet/internal/adapter/storage/entity"
+	"github.com/khivuksergey/portmonetka.wallet/internal/core/port/repository"
+	"gorm.io/gorm"
+)
+
+type walletMemberRepository struct {
+	db        *gorm.DB
+	tableName string
+}
+
+func NewWalletMemberRepository(db *gorm.DB) repository.WalletMemberRepository {
+	return &walletMemberRepository{db: db, tableName: entity.WalletMember{}.TableName()}
+}
+
+func (m *walletMemberRepository) GetWalletMember(ctx context.Context, walletId, userId uint64) (*entity.WalletMember, error) {
+	walletMember := &entity.WalletMember{}
+	result := m.db.WithContext(ctx).Where("wallet_id = ? AND user_id = ?", walletId, userId).First(walletMember)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return walletMember, nil
+}
+
+func (m *walletMemberRepository) GetWalletMemberById(ctx context.Context, id uint64) (*entity.WalletMember, error) {
+	walletMember := &entity.WalletMember{}
+	result := m.db.WithContext(ctx).First(walletMember, id)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return walletMember, nil
+}
+
+func (m *walletMemberRepository) GetWalletMembers(ctx context.Context, walletId uint64) ([]entity.WalletMember, error) {
+	var walletMembers []entity.WalletMember
+	result := m.db.WithContext(ctx).Where("wallet_id = ?", walletId).Order("id").Find(&walletMembers)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return walletMembers, nil
+}
+
+// GetPendingInvitations returns the invitations to not deleted wallets the user hasn't accepted yet.
+func (m *walletMemberRepository) GetPendingInvitations(ctx context.Context, userId uint64) ([]entity.WalletMember, error) {
+	var walletMembers []entity.WalletMember
+	result := m.db.WithContext(ctx).
+		Where("user_id = ? AND accepted_at IS NULL", userId).
+		Where("wallet_id IN (?)", m.db.Model(&entity.Wallet{}).Select("id")).
+		Order("id").
+		Find(&walletMembers)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return walletMembers, nil
+}
+
+func (m *walletMemberRepository) CreateWalletMember(ctx context.Context, walletMember *entity.WalletMember) (*entity.WalletMember, error) {
+	result := m.db.WithContext(ctx).Create(walletMember)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return walletMember, nil
+}
+
+func (m *walletMemberRepository) UpdateWalletMember(ctx context.Context, walletMember *entity.WalletMember) (*entity.WalletMember, error) {
+	result := m.db.WithContext(ctx).Save(walletMember)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return walletMember, nil
+}
+
+func (m *walletMemberRepository) DeleteWalletMember(ctx context.Context, id uint64) error {
+	return m.db.WithContext(ctx).Delete(&entity.WalletMember{}, id).Error
+}
@@ -0,0 +1,93 @@
+package gorm
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"github.com/glebarez/sqlite"
+	"github.com/khivuksergey/portmonetka.wallet/config"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"strings"
+	"time"
+)
+
+const (
+	sqliteMemory = ":memory:"
+	sqliteSchema = "portmonetka"
+)
+
+// openSqlite opens the SQLite database at the path from the connection string (or ":memory:").
+// Tables are schema-qualified with "portmonetka.", so the database is attached under this name
+// to a scratch main database on every new connection.
+func openSqlite(config config.DBConfig) (*gorm.DB, error) {
+	path := config.ConnectionString
+	if path == "" {
+		path = sqliteMemory
+	}
+
+	// the registered driver is only needed to open raw connections, this doesn't connect yet
+	registered, err := sql.Open(sqlite.DriverName, "")
+	if err != nil {
+		return nil, err
+	}
+	sqlDB := sql.OpenDB(&sqliteConnector{driver: registered.Driver(), path: path})
+	_ = registered.Close()
+	if path == sqliteMemory {
+		// every connection to ":memory:" opens a separate empty database
+		sqlDB.SetMaxOpenConns(1)
+		sqlDB.SetMaxIdleConns(1)
+		sqlDB.SetConnMaxLifetime(0)
+		sqlDB.SetConnMaxIdleTime(0)
+	}
+
+	db, err := gorm.Open(
+		sqlite.Dialector{Conn: sqlDB},
+		&gorm.Config{
+			Logger: logger.Default.LogMode(logger.Silent),
+			// unique violations are reported as gorm.ErrDuplicatedKey, wrapped into repository.ErrDuplicatedKey
+			TranslateError: true,
+			// SQLite compares timestamps as text, so they must be stored in the same time zone
+			NowFunc: func() time.Time { return time.Now().UTC() },
+		},
+	)
+	if err != nil {
+		_ = sqlDB.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+type sqliteConnector struct {
+	driver driver.Driver
+	path   string
+}
+
+func (c *sqliteConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.driver.Open(sqliteMemory)
+	if err != nil {
+		return nil, err
+	}
+	execer, ok := conn.(driver.ExecerContext)
+	if !ok {
+		_ = conn.Close()
+		return nil, errors.New("sqlite connection doesn't support exec")
+	}
+	statements := []string{
+		"ATTACH DATABASE '" + strings.ReplaceAll(c.path, "'", "''") + "' AS " + sqliteSchema,
+		"PRAGMA foreign_keys = ON",
+		"PRAGMA busy_timeout = 5000",
+	}
+	for _, statement := range statements {
+		if _, err = execer.ExecContext(ctx, statement, nil); err != nil {
+			_ = conn.Close()
+			return nil, err
+		}
+	}
+	return conn, nil
+}
+
+func (c *sqliteConnector) Driver() driver.Driver {
+	return c.driver
+}
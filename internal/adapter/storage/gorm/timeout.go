@@ -0,0 +1,58 @@
+package gorm
+
+import (
+	"context"
+	"errors"
+	"gorm.io/gorm"
+	"time"
+)
+
+const (
+	queryTimeoutName      = "query_timeout"
+	queryTimeoutStart     = "query_timeout:start"
+	queryTimeoutStop      = "query_timeout:stop"
+	queryTimeoutCancelKey = "query_timeout:cancel"
+)
+
+// queryTimeout limits every statement by the timeout on top of the context the session is bound to,
+// so a slow query is aborted either when it exceeds the timeout or when the request is cancelled.
+// Row queries aren't limited, as their rows are read after the callbacks have finished.
+type queryTimeout struct {
+	timeout time.Duration
+}
+
+func (q queryTimeout) Name() string {
+	return queryTimeoutName
+}
+
+func (q queryTimeout) Initialize(db *gorm.DB) error {
+	callbacks := db.Callback()
+	return errors.Join(
+		callbacks.Create().Before("*").Register(queryTimeoutStart, q.start),
+		callbacks.Create().After("*").Register(queryTimeoutStop, q.stop),
+		callbacks.Query().Before("*").Register(queryTimeoutStart, q.start),
+		callbacks.Query().After("*").Register(queryTimeoutStop, q.stop),
+		callbacks.Update().Before("*").Register(queryTimeoutStart, q.start),
+		callbacks.Update().After("*").Register(queryTimeoutStop, q.stop),
+		callbacks.Delete().Before("*").Register(queryTimeoutStart, q.start),
+		callbacks.Delete().After("*").Register(queryTimeoutStop, q.stop),
+		callbacks.Raw().Before("*").Register(queryTimeoutStart, q.start),
+		callbacks.Raw().After("*").Register(queryTimeoutStop, q.stop),
+	)
+}
+
+func (q queryTimeout) start(db *gorm.DB) {
+	ctx := db.Statement.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, cancel := context.WithTimeout(ctx, q.timeout)
+	db.Statement.Context = ctx
+	db.InstanceSet(queryTimeoutCancelKey, cancel)
+}
+
+func (q queryTimeout) stop(db *gorm.DB) {
+	if cancel, ok := db.InstanceGet(queryTimeoutCancelKey); ok {
+		cancel.(context.CancelFunc)()
+	}
+}
@@ -0,0 +1,36 @@
+package gorm
+
+import (
+	"context"
+	"github.com/khivuksergey/portmonetka.wallet/internal/adapter/storage/gorm/repo"
+	"github.com/khivuksergey/portmonetka.wallet/internal/core/port/repository"
+	"gorm.io/gorm"
+)
+
+// unitOfWork runs functions in database transactions of the connection or, if it is already
+// a transaction, in its savepoints.
+type unitOfWork struct {
+	db *gorm.DB
+}
+
+func (u unitOfWork) Do(ctx context.Context, fn func(ctx context.Context, repositories *repository.Manager) error) error {
+	return u.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(ctx, newRepositoryManager(tx))
+	})
+}
+
+// newRepositoryManager returns repositories using the connection or the transaction.
+func newRepositoryManager(db *gorm.DB) *repository.Manager {
+	return &repository.Manager{
+		Wallet:       repo.NewWalletRepository(db),
+		Transaction:  repo.NewTransactionRepository(db),
+		Transfer:     repo.NewTransferRepository(db),
+		Idempotency:  repo.NewIdempotencyRepository(db),
+		ExchangeRate: repo.NewExchangeRateRepository(db),
+		Preferences:  repo.NewPreferencesRepository(db),
+		WalletMember: repo.NewWalletMemberRepository(db),
+		WalletAudit:  repo.NewWalletAuditRepository(db),
+		Outbox:       repo.NewOutboxRepository(db),
+		UnitOfWork:   unitOfWork{db: db},
+	}
+}
@@ -0,0 +1,61 @@
+package memory
+
+import (
+	"context"
+	"github.com/khivuksergey/portmonetka.wallet/internal/adapter/storage/entity"
+	"time"
+)
+
+type exchangeRateKey struct {
+	date          time.Time
+	baseCurrency  string
+	quoteCurrency string
+}
+
+type exchangeRatePair struct {
+	baseCurrency  string
+	quoteCurrency string
+}
+
+type exchangeRateRepository struct {
+	*store
+}
+
+// SaveExchangeRates inserts the rates, replacing already stored rates of the same pair on the same date.
+func (e *exchangeRateRepository) SaveExchangeRates(ctx context.Context, exchangeRates []entity.ExchangeRate) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for _, exchangeRate := range exchangeRates {
+		key := exchangeRateKey{exchangeRate.Date.UTC(), exchangeRate.BaseCurrency, exchangeRate.QuoteCurrency}
+		if stored, ok := e.exchangeRates[key]; ok {
+			exchangeRate.Id, exchangeRate.CreatedAt = stored.Id, stored.CreatedAt
+		} else {
+			e.exchangeRateSeq++
+			exchangeRate.Id, exchangeRate.CreatedAt = e.exchangeRateSeq, now()
+		}
+		exchangeRate.UpdatedAt = now()
+		e.exchangeRates[key] = exchangeRate
+	}
+	return nil
+}
+
+// GetEffectiveExchangeRates returns the latest rate of every currency pair published on or before the date.
+func (e *exchangeRateRepository) GetEffectiveExchangeRates(ctx context.Context, date time.Time) ([]entity.ExchangeRate, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	latest := map[exchangeRatePair]entity.ExchangeRate{}
+	for key, exchangeRate := range e.exchangeRates {
+		if key.date.After(date) {
+			continue
+		}
+		pair := exchangeRatePair{key.baseCurrency, key.quoteCurrency}
+		if current, ok := latest[pair]; !ok || current.Date.Before(exchangeRate.Date) {
+			latest[pair] = exchangeRate
+		}
+	}
+	exchangeRates := make([]entity.ExchangeRate, 0, len(latest))
+	for _, exchangeRate := range latest {
+		exchangeRates = append(exchangeRates, exchangeRate)
+	}
+	return exchangeRates, nil
+}
@@ -0,0 +1,97 @@
+package memory
+
+import (
+	"context"
+	"github.com/khivuksergey/portmonetka.wallet/internal/adapter/storage/entity"
+	"github.com/khivuksergey/portmonetka.wallet/internal/core/port/repository"
+	"time"
+)
+
+type idempotencyRepository struct {
+	*store
+}
+
+func (i *idempotencyRepository) GetIdempotencyKey(ctx context.Context, userId uint64, key string) (*entity.IdempotencyKey, error) {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	for _, idempotencyKey := range i.idempotencyKeys {
+		if idempotencyKey.UserId == userId && idempotencyKey.Key == key {
+			return copyIdempotencyKey(idempotencyKey), nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (i *idempotencyRepository) CreateIdempotencyKey(ctx context.Context, idempotencyKey *entity.IdempotencyKey) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	for _, stored := range i.idempotencyKeys {
+		if stored.UserId == idempotencyKey.UserId && stored.Key == idempotencyKey.Key {
+			return repository.ErrDuplicatedKey
+		}
+	}
+	i.idempotencySeq++
+	idempotencyKey.Id = i.idempotencySeq
+	idempotencyKey.CreatedAt, idempotencyKey.UpdatedAt = now(), now()
+	idempotencyKey.ReservedAt = idempotencyKey.ReservedAt.Truncate(time.Microsecond)
+	i.idempotencyKeys[idempotencyKey.Id] = *copyIdempotencyKey(*idempotencyKey)
+	return nil
+}
+
+func (i *idempotencyRepository) UpdateIdempotencyKey(ctx context.Context, idempotencyKey *entity.IdempotencyKey) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	stored, ok := i.idempotencyKeys[idempotencyKey.Id]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	idempotencyKey.CreatedAt = stored.CreatedAt
+	idempotencyKey.UpdatedAt = now()
+	i.idempotencyKeys[idempotencyKey.Id] = *copyIdempotencyKey(*idempotencyKey)
+	return nil
+}
+
+func (i *idempotencyRepository) DeleteIdempotencyKey(ctx context.Context, id uint64) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	delete(i.idempotencyKeys, id)
+	return nil
+}
+
+func (i *idempotencyRepository) ReserveStaleIdempotencyKey(ctx context.Context, id uint64, requestHash string, staleBefore, expiredBefore time.Time) (bool, error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	stored, ok := i.idempotencyKeys[id]
+	if !ok {
+		return false, nil
+	}
+	stale := stored.IsPending() && stored.ReservedAt.Before(staleBefore)
+	expired := !stored.IsPending() && stored.UpdatedAt.Before(expiredBefore)
+	if !stale && !expired {
+		return false, nil
+	}
+	stored.RequestHash = requestHash
+	stored.StatusCode = 0
+	stored.Response = nil
+	stored.ReservedAt, stored.UpdatedAt = now(), now()
+	i.idempotencyKeys[id] = stored
+	return true, nil
+}
+
+func (i *idempotencyRepository) DeleteIdempotencyKeysCompletedBefore(ctx context.Context, completedBefore time.Time) (int64, error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	var deleted int64
+	for id, stored := range i.idempotencyKeys {
+		if !stored.IsPending() && stored.UpdatedAt.Before(completedBefore) {
+			delete(i.idempotencyKeys, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func copyIdempotencyKey(idempotencyKey entity.IdempotencyKey) *entity.IdempotencyKey {
+	idempotencyKey.Response = append([]byte(nil), idempotencyKey.Response...)
+	return &idempotencyKey
+}
@@ -0,0 +1,138 @@
+package memory
+
+import (
+	"context"
+	"github.com/khivuksergey/portmonetka.wallet/internal/adapter/storage/entity"
+	"github.com/khivuksergey/portmonetka.wallet/internal/core/port/repository"
+	"maps"
+	"sync"
+	"time"
+)
+
+// store keeps all rows of the in-memory repositories behind a single lock,
+// so operations touching several tables are atomic like database transactions.
+// Rows are copied on the way in and out, callers never share memory with the store.
+type store struct {
+	mu sync.RWMutex
+
+	wallets         map[uint64]entity.Wallet
+	transactions    map[uint64]entity.Transaction
+	transfers       map[uint64]entity.Transfer
+	idempotencyKeys map[uint64]entity.IdempotencyKey
+	exchangeRates   map[exchangeRateKey]entity.ExchangeRate
+	preferences     map[uint64]entity.Preferences
+	walletMembers   map[uint64]entity.WalletMember
+	walletAudits    map[uint64]entity.WalletAudit
+	outboxEvents    map[uint64]entity.OutboxEvent
+
+	walletSeq       uint64
+	transactionSeq  uint64
+	transferSeq     uint64
+	idempotencySeq  uint64
+	exchangeRateSeq uint64
+	walletMemberSeq uint64
+	walletAuditSeq  uint64
+	outboxEventSeq  uint64
+}
+
+// NewRepositoryManager returns thread-safe repositories keeping data in memory.
+// They enforce the same constraints as the database schema and return the same
+// repository errors, so services can be tested against realistic behaviour without a database.
+func NewRepositoryManager() *repository.Manager {
+	s := &store{
+		wallets:         map[uint64]entity.Wallet{},
+		transactions:    map[uint64]entity.Transaction{},
+		transfers:       map[uint64]entity.Transfer{},
+		idempotencyKeys: map[uint64]entity.IdempotencyKey{},
+		exchangeRates:   map[exchangeRateKey]entity.ExchangeRate{},
+		preferences:     map[uint64]entity.Preferences{},
+		walletMembers:   map[uint64]entity.WalletMember{},
+		walletAudits:    map[uint64]entity.WalletAudit{},
+		outboxEvents:    map[uint64]entity.OutboxEvent{},
+	}
+	return s.repositoryManager()
+}
+
+func (s *store) repositoryManager() *repository.Manager {
+	return &repository.Manager{
+		Wallet:       &walletRepository{store: s},
+		Transaction:  &transactionRepository{store: s},
+		Transfer:     &transferRepository{store: s},
+		Idempotency:  &idempotencyRepository{store: s},
+		ExchangeRate: &exchangeRateRepository{store: s},
+		Preferences:  &preferencesRepository{store: s},
+		WalletMember: &walletMemberRepository{store: s},
+		WalletAudit:  &walletAuditRepository{store: s},
+		Outbox:       &outboxRepository{store: s},
+		UnitOfWork:   unitOfWork{store: s},
+	}
+}
+
+// unitOfWork runs functions on a copy of the store, which replaces the store's data if the function succeeds.
+// The store stays locked meanwhile, so units of work are serializable and other operations wait for them.
+type unitOfWork struct {
+	store *store
+}
+
+func (u unitOfWork) Do(ctx context.Context, fn func(ctx context.Context, repositories *repository.Manager) error) error {
+	u.store.mu.Lock()
+	defer u.store.mu.Unlock()
+
+	scoped := u.store.clone()
+	if err := fn(ctx, scoped.repositoryManager()); err != nil {
+		return err
+	}
+	u.store.commit(scoped)
+	return nil
+}
+
+// clone copies the data of the store, the caller must hold the lock.
+func (s *store) clone() *store {
+	return &store{
+		wallets:         maps.Clone(s.wallets),
+		transactions:    maps.Clone(s.transactions),
+		transfers:       maps.Clone(s.transfers),
+		idempotencyKeys: maps.Clone(s.idempotencyKeys),
+		exchangeRates:   maps.Clone(s.exchangeRates),
+		preferences:     maps.Clone(s.preferences),
+		walletMembers:   maps.Clone(s.walletMembers),
+		walletAudits:    maps.Clone(s.walletAudits),
+		outboxEvents:    maps.Clone(s.outboxEvents),
+
+		walletSeq:       s.walletSeq,
+		transactionSeq:  s.transactionSeq,
+		transferSeq:     s.transferSeq,
+		idempotencySeq:  s.idempotencySeq,
+		exchangeRateSeq: s.exchangeRateSeq,
+		walletMemberSeq: s.walletMemberSeq,
+		walletAuditSeq:  s.walletAuditSeq,
+		outboxEventSeq:  s.outboxEventSeq,
+	}
+}
+
+// commit takes over the data of the scoped copy, the caller must hold the lock.
+func (s *store) commit(scoped *store) {
+	s.wallets = scoped.wallets
+	s.transactions = scoped.transactions
+	s.transfers = scoped.transfers
+	s.idempotencyKeys = scoped.idempotencyKeys
+	s.exchangeRates = scoped.exchangeRates
+	s.preferences = scoped.preferences
+	s.walletMembers = scoped.walletMembers
+	s.walletAudits = scoped.walletAudits
+	s.outboxEvents = scoped.outboxEvents
+
+	s.walletSeq = scoped.walletSeq
+	s.transactionSeq = scoped.transactionSeq
+	s.transferSeq = scoped.transferSeq
+	s.idempotencySeq = scoped.idempotencySeq
+	s.exchangeRateSeq = scoped.exchangeRateSeq
+	s.walletMemberSeq = scoped.walletMemberSeq
+	s.walletAuditSeq = scoped.walletAuditSeq
+	s.outboxEventSeq = scoped.outboxEventSeq
+}
+
+// now returns the current time truncated to microseconds, the precision of Postgres timestamps.
+func now() time.Time {
+	return time.Now().Truncate(time.Microsecond)
+}
@@ -0,0 +1,98 @@
+package memory
+
+import (
+	"context"
+	"github.com/khivuksergey/portmonetka.wallet/internal/adapter/storage/entity"
+	"slices"
+	"sort"
+	"time"
+)
+
+type outboxRepository struct {
+	*store
+}
+
+func (o *outboxRepository) CreateOutboxEvent(ctx context.Context, outboxEvent *entity.OutboxEvent) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.outboxEventSeq++
+	outboxEvent.Id = o.outboxEventSeq
+	outboxEvent.CreatedAt = now()
+	o.outboxEvents[outboxEvent.Id] = *copyOutboxEvent(*outboxEvent)
+	return nil
+}
+
+func (o *outboxRepository) ClaimOutboxEvents(ctx context.Context, limit int, claimedUntil time.Time) ([]entity.OutboxEvent, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	claimedUntil = claimedUntil.Truncate(time.Microsecond)
+	current := now()
+	outboxEvents := []entity.OutboxEvent{}
+	for _, outboxEvent := range o.outboxEvents {
+		if outboxEvent.PublishedAt == nil && (outboxEvent.ClaimedUntil == nil || outboxEvent.ClaimedUntil.Before(current)) {
+			outboxEvents = append(outboxEvents, outboxEvent)
+		}
+	}
+	sort.Slice(outboxEvents, func(i, j int) bool { return outboxEvents[i].Id < outboxEvents[j].Id })
+	if len(outboxEvents) > limit {
+		outboxEvents = outboxEvents[:limit]
+	}
+	for i := range outboxEvents {
+		outboxEvents[i].ClaimedUntil = &claimedUntil
+		o.outboxEvents[outboxEvents[i].Id] = *copyOutboxEvent(outboxEvents[i])
+		outboxEvents[i] = *copyOutboxEvent(outboxEvents[i])
+	}
+	return outboxEvents, nil
+}
+
+func (o *outboxRepository) MarkOutboxEventPublished(ctx context.Context, id uint64, publishedAt time.Time) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	outboxEvent, ok := o.outboxEvents[id]
+	if !ok {
+		return nil
+	}
+	publishedAt = publishedAt.Truncate(time.Microsecond)
+	outboxEvent.PublishedAt = &publishedAt
+	outboxEvent.ClaimedUntil = nil
+	o.outboxEvents[id] = outboxEvent
+	return nil
+}
+
+func (o *outboxRepository) ReleaseOutboxEvents(ctx context.Context, ids []uint64) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	for _, id := range ids {
+		if outboxEvent, ok := o.outboxEvents[id]; ok && outboxEvent.PublishedAt == nil {
+			outboxEvent.ClaimedUntil = nil
+			o.outboxEvents[id] = outboxEvent
+		}
+	}
+	return nil
+}
+
+func (o *outboxRepository) DeletePublishedOutboxEvents(ctx context.Context, publishedBefore time.Time) (int64, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	var deleted int64
+	for id, outboxEvent := range o.outboxEvents {
+		if outboxEvent.PublishedAt != nil && outboxEvent.PublishedAt.Before(publishedBefore) {
+			delete(o.outboxEvents, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func copyOutboxEvent(outboxEvent entity.OutboxEvent) *entity.OutboxEvent {
+	outboxEvent.Payload = slices.Clone(outboxEvent.Payload)
+	if outboxEvent.PublishedAt != nil {
+		publishedAt := *outboxEvent.PublishedAt
+		outboxEvent.PublishedAt = &publishedAt
+	}
+	if outboxEvent.ClaimedUntil != nil {
+		claimedUntil := *outboxEvent.ClaimedUntil
+		outboxEvent.ClaimedUntil = &claimedUntil
+	}
+	return &outboxEvent
+}
@@ -0,0 +1,57 @@
+package memory
+
+import (
+	"context"
+	"github.com/khivuksergey/portmonetka.wallet/internal/adapter/storage/entity"
+	"github.com/khivuksergey/portmonetka.wallet/internal/core/port/repository"
+)
+
+type preferencesRepository struct {
+	*store
+}
+
+func (p *preferencesRepository) GetPreferences(ctx context.Context, userId uint64) (*entity.Preferences, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	preferences, ok := p.preferences[userId]
+	if !ok {
+		return nil, nil
+	}
+	return copyPreferences(preferences), nil
+}
+
+// SavePreferences creates or replaces the user's preferences.
+func (p *preferencesRepository) SavePreferences(ctx context.Context, preferences *entity.Preferences) (*entity.Preferences, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if preferences.DefaultWalletId != nil {
+		if _, ok := p.wallets[*preferences.DefaultWalletId]; !ok {
+			return nil, repository.ErrForeignKeyViolated
+		}
+	}
+	saved := *copyPreferences(*preferences)
+	saved.CreatedAt, saved.UpdatedAt = now(), now()
+	if stored, ok := p.preferences[preferences.UserId]; ok {
+		saved.CreatedAt = stored.CreatedAt
+	}
+	p.preferences[saved.UserId] = saved
+	return copyPreferences(saved), nil
+}
+
+// clearDefaultWallet unsets the deleted wallet in preferences. Must be called under the lock.
+func (s *store) clearDefaultWallet(walletId uint64) {
+	for userId, preferences := range s.preferences {
+		if preferences.DefaultWalletId != nil && *preferences.DefaultWalletId == walletId {
+			preferences.DefaultWalletId = nil
+			s.preferences[userId] = preferences
+		}
+	}
+}
+
+func copyPreferences(preferences entity.Preferences) *entity.Preferences {
+	if preferences.DefaultWalletId != nil {
+		defaultWalletId := *preferences.DefaultWalletId
+		preferences.DefaultWalletId = &defaultWalletId
+	}
+	return &preferences
+}
@@ -0,0 +1,99 @@
+package memory
+
+import (
+	"context"
+	"github.com/khivuksergey/portmonetka.wallet/internal/adapter/storage/entity"
+	"github.com/khivuksergey/portmonetka.wallet/internal/core/port/repository"
+	"gorm.io/gorm"
+	"sort"
+)
+
+type transactionRepository struct {
+	*store
+}
+
+func (t *transactionRepository) GetTransactionById(ctx context.Context, id uint64) (*entity.Transaction, error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	transaction, ok := t.transactions[id]
+	if !ok || transaction.DeletedAt.Valid {
+		return nil, repository.ErrNotFound
+	}
+	return &transaction, nil
+}
+
+func (t *transactionRepository) GetTransactionsByWalletId(ctx context.Context, walletId uint64) ([]entity.Transaction, error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	var transactions []entity.Transaction
+	for _, transaction := range t.transactions {
+		if transaction.WalletId == walletId && !transaction.DeletedAt.Valid {
+			transactions = append(transactions, transaction)
+		}
+	}
+	sortTransactions(transactions)
+	return transactions, nil
+}
+
+func (t *transactionRepository) CreateTransaction(ctx context.Context, transaction *entity.Transaction) (*entity.Transaction, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if err := t.insertTransaction(transaction); err != nil {
+		return nil, err
+	}
+	return transaction, nil
+}
+
+func (t *transactionRepository) UpdateTransaction(ctx context.Context, transaction *entity.Transaction) (*entity.Transaction, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	stored, ok := t.transactions[transaction.Id]
+	if !ok || stored.DeletedAt.Valid {
+		return transaction, repository.ErrNotFound
+	}
+	if _, ok = t.wallets[transaction.WalletId]; !ok {
+		return transaction, repository.ErrForeignKeyViolated
+	}
+	transaction.CreatedAt = stored.CreatedAt
+	transaction.UpdatedAt = now()
+	t.transactions[transaction.Id] = *transaction
+	return transaction, nil
+}
+
+func (t *transactionRepository) DeleteTransaction(ctx context.Context, id uint64) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if transaction, ok := t.transactions[id]; ok && !transaction.DeletedAt.Valid {
+		transaction.DeletedAt = gorm.DeletedAt{Time: now(), Valid: true}
+		t.transactions[id] = transaction
+	}
+	return nil
+}
+
+// insertTransaction stores a new transaction, the wallet it is posted to must exist
+// like the foreign key requires. Must be called under the write lock.
+func (s *store) insertTransaction(transaction *entity.Transaction) error {
+	if _, ok := s.wallets[transaction.WalletId]; !ok {
+		return repository.ErrForeignKeyViolated
+	}
+	if transaction.TransferId != nil {
+		if _, ok := s.transfers[*transaction.TransferId]; !ok {
+			return repository.ErrForeignKeyViolated
+		}
+	}
+	s.transactionSeq++
+	transaction.Id = s.transactionSeq
+	transaction.CreatedAt, transaction.UpdatedAt = now(), now()
+	s.transactions[transaction.Id] = *transaction
+	return nil
+}
+
+func sortTransactions(transactions []entity.Transaction) {
+	sort.Slice(transactions, func(i, j int) bool {
+		a, b := transactions[i], transactions[j]
+		if !a.Timestamp.Equal(b.Timestamp) {
+			return a.Timestamp.After(b.Timestamp)
+		}
+		return a.Id > b.Id
+	})
+}
@@ -0,0 +1,69 @@
+package memory
+
+import (
+	"context"
+	"github.com/khivuksergey/portmonetka.wallet/internal/adapter/storage/entity"
+)
+
+type transferRepository struct {
+	*store
+}
+
+// CreateTransfer stores the transfer together with its debit and credit transactions
+// under a single lock, so either both wallets are affected or none.
+func (t *transferRepository) CreateTransfer(ctx context.Context, transfer *entity.Transfer) (*entity.Transfer, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	transactionSeq := t.transactionSeq
+	legs := make([]entity.Transaction, len(transfer.Transactions))
+	copy(legs, transfer.Transactions)
+
+	t.transferSeq++
+	transferId := t.transferSeq
+	stored := *transfer
+	stored.Id = transferId
+	stored.CreatedAt = now()
+	stored.Transactions = nil
+	t.transfers[transferId] = stored
+
+	for i := range legs {
+		id := transferId
+		legs[i].TransferId = &id
+		if err := t.insertTransaction(&legs[i]); err != nil {
+			t.rollbackTransfer(transferId, transactionSeq)
+			return nil, err
+		}
+	}
+
+	transfer.Id, transfer.CreatedAt = stored.Id, stored.CreatedAt
+	transfer.Transactions = legs
+	return transfer, nil
+}
+
+// rollbackTransfer removes the transfer and the legs inserted after the given transaction sequence.
+func (t *transferRepository) rollbackTransfer(transferId, transactionSeq uint64) {
+	for id := transactionSeq + 1; id <= t.transactionSeq; id++ {
+		delete(t.transactions, id)
+	}
+	t.transactionSeq = transactionSeq
+	delete(t.transfers, transferId)
+	t.transferSeq--
+}
+
+// deleteWalletTransfers deletes the transfers from or to the wallet and detaches their legs,
+// so the leg in the other wallet stays as a standalone transaction. Must be called under the lock.
+func (s *store) deleteWalletTransfers(walletId uint64) {
+	for transferId, transfer := range s.transfers {
+		if transfer.SourceWalletId != walletId && transfer.TargetWalletId != walletId {
+			continue
+		}
+		for transactionId, transaction := range s.transactions {
+			if transaction.TransferId != nil && *transaction.TransferId == transferId {
+				transaction.TransferId = nil
+				s.transactions[transactionId] = transaction
+			}
+		}
+		delete(s.transfers, transferId)
+	}
+}
@@ -0,0 +1,357 @@
+package memory
+
+import (
+	"context"
+	serviceerror "github.com/khivuksergey/portmonetka.wallet/error"
+	"github.com/khivuksergey/portmonetka.wallet/internal/adapter/storage/entity"
+	"github.com/khivuksergey/portmonetka.wallet/internal/core/port/repository"
+	"github.com/khivuksergey/portmonetka.wallet/internal/model"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+	"sort"
+	"strings"
+	"time"
+)
+
+type walletRepository struct {
+	*store
+}
+
+func (w *walletRepository) GetWalletById(ctx context.Context, id uint64) (*entity.Wallet, error) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	wallet, ok := w.wallets[id]
+	if !ok || wallet.DeletedAt.Valid {
+		return nil, repository.ErrNotFound
+	}
+	return w.withCurrentBalance(wallet), nil
+}
+
+// GetWalletForUpdate returns the wallet as GetWalletById does: the memory store has no row locks,
+// as its unit of work already serializes the changes.
+func (w *walletRepository) GetWalletForUpdate(ctx context.Context, id uint64) (*entity.Wallet, error) {
+	return w.GetWalletById(ctx, id)
+}
+
+func (w *walletRepository) GetWalletsByUserId(ctx context.Context, userId uint64, walletListQuery model.WalletListQuery, after *model.WalletCursor) ([]entity.Wallet, error) {
+	sortField, desc := walletListQuery.SortField()
+	if _, ok := walletSortFields[sortField]; !ok {
+		return nil, serviceerror.InvalidWalletSort
+	}
+
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	var wallets []entity.Wallet
+	for _, wallet := range w.wallets {
+		if wallet.DeletedAt.Valid || !matchesWalletFilters(wallet, walletListQuery) {
+			continue
+		}
+		if wallet.UserId != userId && !w.isAcceptedMember(wallet.Id, userId) {
+			continue
+		}
+		wallets = append(wallets, *w.withCurrentBalance(wallet))
+	}
+
+	less := func(a, b entity.Wallet) bool {
+		if c := compareWallets(sortField, a, b); c != 0 {
+			return c < 0 != desc
+		}
+		return a.Id < b.Id != desc
+	}
+	sort.Slice(wallets, func(i, j int) bool { return less(wallets[i], wallets[j]) })
+
+	if after != nil {
+		cursor, err := cursorWallet(sortField, after)
+		if err != nil {
+			return nil, serviceerror.InvalidWalletCursor
+		}
+		start := sort.Search(len(wallets), func(i int) bool { return less(cursor, wallets[i]) })
+		wallets = wallets[start:]
+	}
+
+	if walletListQuery.Limit > 0 && len(wallets) > walletListQuery.Limit {
+		wallets = wallets[:walletListQuery.Limit]
+	}
+	return wallets, nil
+}
+
+func (w *walletRepository) GetAllWalletsByUserId(ctx context.Context, userId uint64) ([]entity.Wallet, error) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	var wallets []entity.Wallet
+	for _, wallet := range w.wallets {
+		if wallet.UserId == userId && !wallet.DeletedAt.Valid {
+			wallets = append(wallets, *w.withCurrentBalance(wallet))
+		}
+	}
+	sort.Slice(wallets, func(i, j int) bool { return wallets[i].Id < wallets[j].Id })
+	return wallets, nil
+}
+
+func (w *walletRepository) CreateWallet(ctx context.Context, wallet *entity.Wallet) (*entity.Wallet, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.nameTaken(wallet.UserId, wallet.Name, 0) {
+		return nil, serviceerror.WalletAlreadyExists
+	}
+	w.walletSeq++
+	wallet.Id = w.walletSeq
+	wallet.NameKey = entity.WalletNameKey(wallet.Name)
+	if wallet.Version == 0 {
+		wallet.Version = 1
+	}
+	if wallet.Type == "" {
+		wallet.Type = entity.WalletTypeCash
+	}
+	wallet.CreatedAt, wallet.UpdatedAt = now(), now()
+	wallet.CurrentBalance = decimal.Decimal{}
+	w.wallets[wallet.Id] = *copyWallet(*wallet)
+	wallet.CurrentBalance = wallet.InitialAmount
+	return wallet, nil
+}
+
+// UpdateWallet saves the wallet only if its version wasn't changed since it was read
+// and increments the version, so concurrent updates can't overwrite each other.
+func (w *walletRepository) UpdateWallet(ctx context.Context, wallet *entity.Wallet) (*entity.Wallet, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	stored, ok := w.wallets[wallet.Id]
+	if !ok || stored.DeletedAt.Valid || stored.Version != wallet.Version {
+		return nil, serviceerror.WalletVersionMismatch
+	}
+	if w.nameTaken(wallet.UserId, wallet.Name, wallet.Id) {
+		return nil, serviceerror.WalletAlreadyExists
+	}
+	updated := *copyWallet(*wallet)
+	updated.NameKey = entity.WalletNameKey(updated.Name)
+	updated.Version++
+	updated.CreatedAt = stored.CreatedAt
+	updated.UpdatedAt = now()
+	updated.CurrentBalance = decimal.Decimal{}
+	w.wallets[wallet.Id] = updated
+	*wallet = *w.withCurrentBalance(updated)
+	return wallet, nil
+}
+
+func (w *walletRepository) DeleteWallet(ctx context.Context, id uint64) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.softDelete(id)
+	return nil
+}
+
+func (w *walletRepository) DeleteWalletWithVersion(ctx context.Context, id, version uint64) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if wallet, ok := w.wallets[id]; !ok || wallet.Version != version || !w.softDelete(id) {
+		return serviceerror.WalletVersionMismatch
+	}
+	return nil
+}
+
+func (w *walletRepository) GetDeletedWalletById(ctx context.Context, id uint64) (*entity.Wallet, error) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	wallet, ok := w.wallets[id]
+	if !ok || !wallet.DeletedAt.Valid {
+		return nil, repository.ErrNotFound
+	}
+	return w.withCurrentBalance(wallet), nil
+}
+
+func (w *walletRepository) GetDeletedWalletsByUserId(ctx context.Context, userId uint64) ([]entity.Wallet, error) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	var wallets []entity.Wallet
+	for _, wallet := range w.wallets {
+		if wallet.UserId == userId && wallet.DeletedAt.Valid {
+			wallets = append(wallets, *w.withCurrentBalance(wallet))
+		}
+	}
+	sort.Slice(wallets, func(i, j int) bool {
+		a, b := wallets[i], wallets[j]
+		if !a.DeletedAt.Time.Equal(b.DeletedAt.Time) {
+			return a.DeletedAt.Time.After(b.DeletedAt.Time)
+		}
+		return a.Id > b.Id
+	})
+	return wallets, nil
+}
+
+func (w *walletRepository) RestoreWallet(ctx context.Context, id uint64) (*entity.Wallet, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	wallet, ok := w.wallets[id]
+	if !ok || !wallet.DeletedAt.Valid {
+		return nil, repository.ErrNotFound
+	}
+	if w.nameTaken(wallet.UserId, wallet.Name, wallet.Id) {
+		return nil, serviceerror.WalletAlreadyExists
+	}
+	wallet.DeletedAt = gorm.DeletedAt{}
+	wallet.Version++
+	wallet.UpdatedAt = now()
+	w.wallets[id] = wallet
+	return w.withCurrentBalance(wallet), nil
+}
+
+// PurgeWallet permanently deletes the soft-deleted wallet together with its transactions and transfers.
+func (w *walletRepository) PurgeWallet(ctx context.Context, id uint64) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.purge(id)
+	return nil
+}
+
+// PurgeWalletsDeletedBefore permanently deletes wallets which were soft-deleted
+// before the given time and returns the number of purged wallets.
+func (w *walletRepository) PurgeWalletsDeletedBefore(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	var purged int64
+	for id, wallet := range w.wallets {
+		if wallet.DeletedAt.Valid && wallet.DeletedAt.Time.Before(deletedBefore) {
+			w.purge(id)
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+// nameTaken reports whether another active wallet of the user has the name regardless of case,
+// the rule enforced by the unique index in the database, which the gorm repository reports
+// as WalletAlreadyExists.
+func (w *walletRepository) nameTaken(userId uint64, name string, exceptId uint64) bool {
+	nameKey := entity.WalletNameKey(name)
+	for _, wallet := range w.wallets {
+		if wallet.Id != exceptId && wallet.UserId == userId && wallet.NameKey == nameKey && !wallet.DeletedAt.Valid {
+			return true
+		}
+	}
+	return false
+}
+
+func (w *walletRepository) softDelete(id uint64) bool {
+	wallet, ok := w.wallets[id]
+	if !ok || wallet.DeletedAt.Valid {
+		return false
+	}
+	wallet.DeletedAt = gorm.DeletedAt{Time: now(), Valid: true}
+	w.wallets[id] = wallet
+	w.clearDefaultWallet(id)
+	return true
+}
+
+func (w *walletRepository) purge(id uint64) {
+	wallet, ok := w.wallets[id]
+	if !ok || !wallet.DeletedAt.Valid {
+		return
+	}
+	w.deleteWalletTransfers(id)
+	for transactionId, transaction := range w.transactions {
+		if transaction.WalletId == id {
+			delete(w.transactions, transactionId)
+		}
+	}
+	w.clearDefaultWallet(id)
+	w.deleteWalletMembers(id)
+	delete(w.wallets, id)
+}
+
+// withCurrentBalance returns a copy of the wallet with its balance computed from
+// the initial amount and not deleted transactions. Must be called under the lock.
+func (w *walletRepository) withCurrentBalance(wallet entity.Wallet) *entity.Wallet {
+	balance := wallet.InitialAmount
+	for _, transaction := range w.transactions {
+		if transaction.WalletId != wallet.Id || transaction.DeletedAt.Valid {
+			continue
+		}
+		if transaction.Direction == entity.TransactionDirectionIn {
+			balance = balance.Add(transaction.Amount)
+		} else {
+			balance = balance.Sub(transaction.Amount)
+		}
+	}
+	wallet.CurrentBalance = balance
+	return copyWallet(wallet)
+}
+
+func copyWallet(wallet entity.Wallet) *entity.Wallet {
+	if wallet.CreditLimit != nil {
+		creditLimit := *wallet.CreditLimit
+		wallet.CreditLimit = &creditLimit
+	}
+	if wallet.StatementDay != nil {
+		statementDay := *wallet.StatementDay
+		wallet.StatementDay = &statementDay
+	}
+	if wallet.InterestRate != nil {
+		interestRate := *wallet.InterestRate
+		wallet.InterestRate = &interestRate
+	}
+	return &wallet
+}
+
+var walletSortFields = map[string]struct{}{
+	model.WalletSortName:      {},
+	model.WalletSortCreatedAt: {},
+	model.WalletSortUpdatedAt: {},
+	model.WalletSortBalance:   {},
+}
+
+func compareWallets(sortField string, a, b entity.Wallet) int {
+	switch sortField {
+	case model.WalletSortName:
+		return strings.Compare(a.Name, b.Name)
+	case model.WalletSortCreatedAt:
+		return a.CreatedAt.Compare(b.CreatedAt)
+	case model.WalletSortUpdatedAt:
+		return a.UpdatedAt.Compare(b.UpdatedAt)
+	default:
+		return a.CurrentBalance.Cmp(b.CurrentBalance)
+	}
+}
+
+// cursorWallet builds a wallet holding the cursor position, so it can be compared
+// with the listed wallets the same way they are sorted.
+func cursorWallet(sortField string, cursor *model.WalletCursor) (entity.Wallet, error) {
+	wallet := entity.Wallet{Id: cursor.Id}
+	var err error
+	switch sortField {
+	case model.WalletSortName:
+		wallet.Name = cursor.Value
+	case model.WalletSortCreatedAt:
+		wallet.CreatedAt, err = time.Parse(time.RFC3339Nano, cursor.Value)
+	case model.WalletSortUpdatedAt:
+		wallet.UpdatedAt, err = time.Parse(time.RFC3339Nano, cursor.Value)
+	default:
+		wallet.CurrentBalance, err = decimal.NewFromString(cursor.Value)
+	}
+	return wallet, err
+}
+
+func matchesWalletFilters(wallet entity.Wallet, walletListQuery model.WalletListQuery) bool {
+	if walletListQuery.Currency != "" && wallet.Currency != strings.ToUpper(walletListQuery.Currency) {
+		return false
+	}
+	if walletListQuery.Type != "" && wallet.Type != walletListQuery.Type {
+		return false
+	}
+	if walletListQuery.Name != "" && !strings.Contains(wallet.NameKey, entity.WalletNameKey(walletListQuery.Name)) {
+		return false
+	}
+	if walletListQuery.CreatedFrom != nil && wallet.CreatedAt.Before(*walletListQuery.CreatedFrom) {
+		return false
+	}
+	if walletListQuery.CreatedTo != nil && !wallet.CreatedAt.Before(*walletListQuery.CreatedTo) {
+		return false
+	}
+	if walletListQuery.UpdatedFrom != nil && wallet.UpdatedAt.Before(*walletListQuery.UpdatedFrom) {
+		return false
+	}
+	if walletListQuery.UpdatedTo != nil && !wallet.UpdatedAt.Before(*walletListQuery.UpdatedTo) {
+		return false
+	}
+	return true
+}
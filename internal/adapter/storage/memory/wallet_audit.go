@@ -0,0 +1,40 @@
+package memory
+
+import (
+	"context"
+	"github.com/khivuksergey/portmonetka.wallet/internal/adapter/storage/entity"
+	"maps"
+	"sort"
+)
+
+type walletAuditRepository struct {
+	*store
+}
+
+func (a *walletAuditRepository) CreateWalletAudit(ctx context.Context, walletAudit *entity.WalletAudit) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.walletAuditSeq++
+	walletAudit.Id = a.walletAuditSeq
+	walletAudit.CreatedAt = now()
+	a.walletAudits[walletAudit.Id] = *copyWalletAudit(*walletAudit)
+	return nil
+}
+
+func (a *walletAuditRepository) GetWalletAuditsByWalletId(ctx context.Context, walletId uint64) ([]entity.WalletAudit, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	walletAudits := []entity.WalletAudit{}
+	for _, walletAudit := range a.walletAudits {
+		if walletAudit.WalletId == walletId {
+			walletAudits = append(walletAudits, *copyWalletAudit(walletAudit))
+		}
+	}
+	sort.Slice(walletAudits, func(i, j int) bool { return walletAudits[i].Id < walletAudits[j].Id })
+	return walletAudits, nil
+}
+
+func copyWalletAudit(walletAudit entity.WalletAudit) *entity.WalletAudit {
+	walletAudit.Changes = maps.Clone(walletAudit.Changes)
+	return &walletAudit
+}
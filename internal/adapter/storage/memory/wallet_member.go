@@ -0,0 +1,129 @@
+package memory
+
+import (
+	"context"
+	"github.com/khivuksergey/portmonetka.wallet/internal/adapter/storage/entity"
+	"github.com/khivuksergey/portmonetka.wallet/internal/core/port/repository"
+	"sort"
+)
+
+type walletMemberRepository struct {
+	*store
+}
+
+func (m *walletMemberRepository) GetWalletMember(ctx context.Context, walletId, userId uint64) (*entity.WalletMember, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, walletMember := range m.walletMembers {
+		if walletMember.WalletId == walletId && walletMember.UserId == userId {
+			return copyWalletMember(walletMember), nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *walletMemberRepository) GetWalletMemberById(ctx context.Context, id uint64) (*entity.WalletMember, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	walletMember, ok := m.walletMembers[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	return copyWalletMember(walletMember), nil
+}
+
+func (m *walletMemberRepository) GetWalletMembers(ctx context.Context, walletId uint64) ([]entity.WalletMember, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.filterWalletMembers(func(walletMember entity.WalletMember) bool {
+		return walletMember.WalletId == walletId
+	}), nil
+}
+
+// GetPendingInvitations returns the invitations to not deleted wallets the user hasn't accepted yet.
+func (m *walletMemberRepository) GetPendingInvitations(ctx context.Context, userId uint64) ([]entity.WalletMember, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.filterWalletMembers(func(walletMember entity.WalletMember) bool {
+		wallet, ok := m.wallets[walletMember.WalletId]
+		return walletMember.UserId == userId && !walletMember.IsAccepted() && ok && !wallet.DeletedAt.Valid
+	}), nil
+}
+
+func (m *walletMemberRepository) CreateWalletMember(ctx context.Context, walletMember *entity.WalletMember) (*entity.WalletMember, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.wallets[walletMember.WalletId]; !ok {
+		return nil, repository.ErrForeignKeyViolated
+	}
+	for _, stored := range m.walletMembers {
+		if stored.WalletId == walletMember.WalletId && stored.UserId == walletMember.UserId {
+			return nil, repository.ErrDuplicatedKey
+		}
+	}
+	m.walletMemberSeq++
+	walletMember.Id = m.walletMemberSeq
+	walletMember.CreatedAt, walletMember.UpdatedAt = now(), now()
+	m.walletMembers[walletMember.Id] = *copyWalletMember(*walletMember)
+	return walletMember, nil
+}
+
+func (m *walletMemberRepository) UpdateWalletMember(ctx context.Context, walletMember *entity.WalletMember) (*entity.WalletMember, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stored, ok := m.walletMembers[walletMember.Id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	updated := *copyWalletMember(*walletMember)
+	updated.CreatedAt, updated.UpdatedAt = stored.CreatedAt, now()
+	m.walletMembers[updated.Id] = updated
+	return copyWalletMember(updated), nil
+}
+
+func (m *walletMemberRepository) DeleteWalletMember(ctx context.Context, id uint64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.walletMembers, id)
+	return nil
+}
+
+// filterWalletMembers returns copies of the matching memberships ordered by id. Must be called under the lock.
+func (s *store) filterWalletMembers(match func(walletMember entity.WalletMember) bool) []entity.WalletMember {
+	walletMembers := []entity.WalletMember{}
+	for _, walletMember := range s.walletMembers {
+		if match(walletMember) {
+			walletMembers = append(walletMembers, *copyWalletMember(walletMember))
+		}
+	}
+	sort.Slice(walletMembers, func(i, j int) bool { return walletMembers[i].Id < walletMembers[j].Id })
+	return walletMembers
+}
+
+// isAcceptedMember reports whether the user has accepted the membership in the wallet. Must be called under the lock.
+func (s *store) isAcceptedMember(walletId, userId uint64) bool {
+	for _, walletMember := range s.walletMembers {
+		if walletMember.WalletId == walletId && walletMember.UserId == userId && walletMember.IsAccepted() {
+			return true
+		}
+	}
+	return false
+}
+
+// deleteWalletMembers removes memberships in the purged wallet, like ON DELETE CASCADE
+// of the foreign key in the database. Must be called under the lock.
+func (s *store) deleteWalletMembers(walletId uint64) {
+	for id, walletMember := range s.walletMembers {
+		if walletMember.WalletId == walletId {
+			delete(s.walletMembers, id)
+		}
+	}
+}
+
+func copyWalletMember(walletMember entity.WalletMember) *entity.WalletMember {
+	if walletMember.AcceptedAt != nil {
+		acceptedAt := *walletMember.AcceptedAt
+		walletMember.AcceptedAt = &acceptedAt
+	}
+	return &walletMember
+}
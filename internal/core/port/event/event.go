@@ -0,0 +1,13 @@
+package event
+
+import (
+	"context"
+	"github.com/khivuksergey/portmonetka.wallet/internal/adapter/storage/entity"
+)
+
+// Publisher delivers domain events from the outbox to other services. Events are relayed
+// in the order they were emitted and at least once, so consumers deduplicate them by ID.
+type Publisher interface {
+	Publish(ctx context.Context, outboxEvent entity.OutboxEvent) error
+	Close() error
+}
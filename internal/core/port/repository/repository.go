@@ -1,20 +1,141 @@
 package repository
 
 import (
+	"context"
+	"errors"
 	"github.com/khivuksergey/portmonetka.wallet/internal/adapter/storage/entity"
+	"github.com/khivuksergey/portmonetka.wallet/internal/model"
+	"time"
 )
 
 type Manager struct {
-	Wallet WalletRepository
+	Wallet       WalletRepository
+	Transaction  TransactionRepository
+	Transfer     TransferRepository
+	Idempotency  IdempotencyRepository
+	ExchangeRate ExchangeRateRepository
+	Preferences  PreferencesRepository
+	WalletMember WalletMemberRepository
+	WalletAudit  WalletAuditRepository
+	Outbox       OutboxRepository
+	UnitOfWork   UnitOfWork
+}
+
+// ErrNoUnitOfWork is returned by WithinTransaction of a manager without a unit of work,
+// as running the function outside of a transaction would lose its atomicity silently.
+var ErrNoUnitOfWork = errors.New("repository manager has no unit of work")
+
+// The errors reported by the repositories of every adapter, so services don't depend on the storage.
+var (
+	// ErrNotFound is returned when the requested record doesn't exist.
+	ErrNotFound = errors.New("record not found")
+	// ErrDuplicatedKey is returned when a write violates a unique constraint.
+	ErrDuplicatedKey = errors.New("duplicated key")
+	// ErrForeignKeyViolated is returned when a write refers to a record which doesn't exist.
+	ErrForeignKeyViolated = errors.New("foreign key violated")
+)
+
+// WithinTransaction runs fn with repositories bound to a single transaction of the unit of work,
+// so all their writes are committed together if fn returns nil and rolled back otherwise.
+func (m *Manager) WithinTransaction(ctx context.Context, fn func(ctx context.Context, repositories *Manager) error) error {
+	if m.UnitOfWork == nil {
+		return ErrNoUnitOfWork
+	}
+	return m.UnitOfWork.Do(ctx, fn)
 }
 
 //go:generate mockgen -source=repository.go -destination=../../../adapter/storage/gorm/repo/mock/mock_repository.go -package=mock
 type WalletRepository interface {
-	ExistsWithName(userId uint64, name string) bool
-	WalletBelongsToUser(id, userId uint64) bool
-	GetWalletById(id uint64) (*entity.Wallet, error)
-	GetWalletsByUserId(userId uint64) ([]entity.Wallet, error)
-	CreateWallet(wallet *entity.Wallet) (*entity.Wallet, error)
-	UpdateWallet(wallet *entity.Wallet) (*entity.Wallet, error)
-	DeleteWallet(id uint64) error
+	GetWalletById(ctx context.Context, id uint64) (*entity.Wallet, error)
+	// GetWalletForUpdate returns the wallet locking it until the end of the unit of work,
+	// so concurrent balance checks of the wallet are serialized.
+	GetWalletForUpdate(ctx context.Context, id uint64) (*entity.Wallet, error)
+	// GetWalletsByUserId returns a page of wallets created by the user or shared with them.
+	GetWalletsByUserId(ctx context.Context, userId uint64, walletListQuery model.WalletListQuery, after *model.WalletCursor) ([]entity.Wallet, error)
+	// GetAllWalletsByUserId returns all wallets created by the user, without shared ones.
+	GetAllWalletsByUserId(ctx context.Context, userId uint64) ([]entity.Wallet, error)
+	CreateWallet(ctx context.Context, wallet *entity.Wallet) (*entity.Wallet, error)
+	UpdateWallet(ctx context.Context, wallet *entity.Wallet) (*entity.Wallet, error)
+	// DeleteWallet and DeleteWalletWithVersion soft-delete the wallet and unset it as the default wallet in preferences.
+	DeleteWallet(ctx context.Context, id uint64) error
+	DeleteWalletWithVersion(ctx context.Context, id, version uint64) error
+	GetDeletedWalletById(ctx context.Context, id uint64) (*entity.Wallet, error)
+	GetDeletedWalletsByUserId(ctx context.Context, userId uint64) ([]entity.Wallet, error)
+	RestoreWallet(ctx context.Context, id uint64) (*entity.Wallet, error)
+	PurgeWallet(ctx context.Context, id uint64) error
+	PurgeWalletsDeletedBefore(ctx context.Context, deletedBefore time.Time) (int64, error)
+}
+
+type TransactionRepository interface {
+	GetTransactionById(ctx context.Context, id uint64) (*entity.Transaction, error)
+	GetTransactionsByWalletId(ctx context.Context, walletId uint64) ([]entity.Transaction, error)
+	CreateTransaction(ctx context.Context, transaction *entity.Transaction) (*entity.Transaction, error)
+	UpdateTransaction(ctx context.Context, transaction *entity.Transaction) (*entity.Transaction, error)
+	DeleteTransaction(ctx context.Context, id uint64) error
+}
+
+type TransferRepository interface {
+	CreateTransfer(ctx context.Context, transfer *entity.Transfer) (*entity.Transfer, error)
+}
+
+type IdempotencyRepository interface {
+	// GetIdempotencyKey returns ErrNotFound if the user hasn't used the key.
+	GetIdempotencyKey(ctx context.Context, userId uint64, key string) (*entity.IdempotencyKey, error)
+	// CreateIdempotencyKey fails with ErrDuplicatedKey if the user has used the key already.
+	CreateIdempotencyKey(ctx context.Context, idempotencyKey *entity.IdempotencyKey) error
+	UpdateIdempotencyKey(ctx context.Context, idempotencyKey *entity.IdempotencyKey) error
+	DeleteIdempotencyKey(ctx context.Context, id uint64) error
+	// ReserveStaleIdempotencyKey reserves the stored key for a new request with the given hash if the key
+	// is pending since before staleBefore or completed before expiredBefore, dropping the stored response.
+	// It reports false if the key isn't stale, e.g. as a concurrent request has reserved it first.
+	ReserveStaleIdempotencyKey(ctx context.Context, id uint64, requestHash string, staleBefore, expiredBefore time.Time) (bool, error)
+	// DeleteIdempotencyKeysCompletedBefore deletes the keys completed before the given time and returns their number.
+	DeleteIdempotencyKeysCompletedBefore(ctx context.Context, completedBefore time.Time) (int64, error)
+}
+
+type ExchangeRateRepository interface {
+	SaveExchangeRates(ctx context.Context, exchangeRates []entity.ExchangeRate) error
+	GetEffectiveExchangeRates(ctx context.Context, date time.Time) ([]entity.ExchangeRate, error)
+}
+
+type PreferencesRepository interface {
+	// GetPreferences returns nil without error if the user has no stored preferences.
+	GetPreferences(ctx context.Context, userId uint64) (*entity.Preferences, error)
+	SavePreferences(ctx context.Context, preferences *entity.Preferences) (*entity.Preferences, error)
+}
+
+type WalletMemberRepository interface {
+	// GetWalletMember returns nil without error if the user isn't a member of the wallet.
+	GetWalletMember(ctx context.Context, walletId, userId uint64) (*entity.WalletMember, error)
+	GetWalletMemberById(ctx context.Context, id uint64) (*entity.WalletMember, error)
+	GetWalletMembers(ctx context.Context, walletId uint64) ([]entity.WalletMember, error)
+	GetPendingInvitations(ctx context.Context, userId uint64) ([]entity.WalletMember, error)
+	CreateWalletMember(ctx context.Context, walletMember *entity.WalletMember) (*entity.WalletMember, error)
+	UpdateWalletMember(ctx context.Context, walletMember *entity.WalletMember) (*entity.WalletMember, error)
+	DeleteWalletMember(ctx context.Context, id uint64) error
+}
+
+// WalletAuditRepository keeps the append-only audit log of wallet changes, records are never updated or deleted.
+type WalletAuditRepository interface {
+	CreateWalletAudit(ctx context.Context, walletAudit *entity.WalletAudit) error
+	// GetWalletAuditsByWalletId returns the audit records of the wallet in the order they were made.
+	GetWalletAuditsByWalletId(ctx context.Context, walletId uint64) ([]entity.WalletAudit, error)
+}
+
+type OutboxRepository interface {
+	CreateOutboxEvent(ctx context.Context, outboxEvent *entity.OutboxEvent) error
+	// ClaimOutboxEvents returns up to limit oldest unpublished events which aren't claimed by another relay
+	// and claims them until the given time, after which they can be claimed again if still unpublished.
+	ClaimOutboxEvents(ctx context.Context, limit int, claimedUntil time.Time) ([]entity.OutboxEvent, error)
+	MarkOutboxEventPublished(ctx context.Context, id uint64, publishedAt time.Time) error
+	// ReleaseOutboxEvents drops the claims of the events, so they can be claimed again at once.
+	ReleaseOutboxEvents(ctx context.Context, ids []uint64) error
+	// DeletePublishedOutboxEvents deletes the events published before the given time and returns their number.
+	DeletePublishedOutboxEvents(ctx context.Context, publishedBefore time.Time) (int64, error)
+}
+
+type UnitOfWork interface {
+	// Do runs fn with transaction-scoped repositories, committing the transaction if fn returns nil
+	// and rolling it back otherwise. Nested calls within fn roll back only their own writes on failure.
+	Do(ctx context.Context, fn func(ctx context.Context, repositories *Manager) error) error
 }
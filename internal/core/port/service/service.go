@@ -1,17 +1,78 @@
 package service
 
 import (
+	"context"
 	"github.com/khivuksergey/portmonetka.wallet/internal/adapter/storage/entity"
+	"github.com/khivuksergey/portmonetka.wallet/internal/core/port/event"
 	"github.com/khivuksergey/portmonetka.wallet/internal/model"
+	"io"
+	"time"
 )
 
 type Manager struct {
-	Wallet WalletService
+	Wallet       WalletService
+	Transaction  TransactionService
+	Transfer     TransferService
+	Idempotency  IdempotencyService
+	ExchangeRate ExchangeRateService
+	Preferences  PreferencesService
+	WalletMember WalletMemberService
+	Outbox       OutboxService
 }
 
 type WalletService interface {
-	GetWalletsByUserId(userId uint64) ([]entity.Wallet, error)
-	CreateWallet(walletCreateDTO model.WalletCreateDTO) (*entity.Wallet, error)
-	UpdateWallet(walletUpdateDTO model.WalletUpdateDTO) (*entity.Wallet, error)
-	DeleteWallet(walletDeleteDTO model.WalletDeleteDTO) error
+	GetWalletsByUserId(ctx context.Context, userId uint64, walletListQuery model.WalletListQuery) ([]entity.Wallet, string, error)
+	GetWalletById(ctx context.Context, userId, id uint64) (*entity.Wallet, error)
+	GetWalletHistory(ctx context.Context, userId, id uint64) ([]entity.WalletAudit, error)
+	GetWalletsSummary(ctx context.Context, userId uint64, walletSummaryQuery model.WalletSummaryQuery) (*model.WalletsSummary, error)
+	CreateWallet(ctx context.Context, walletCreateDTO model.WalletCreateDTO) (*entity.Wallet, error)
+	UpdateWallet(ctx context.Context, walletUpdateDTO model.WalletUpdateDTO) (*entity.Wallet, error)
+	DeleteWallet(ctx context.Context, walletDeleteDTO model.WalletDeleteDTO) error
+	GetDeletedWalletsByUserId(ctx context.Context, userId uint64) ([]entity.Wallet, error)
+	RestoreWallet(ctx context.Context, walletTrashDTO model.WalletTrashDTO) (*entity.Wallet, error)
+	PurgeWallet(ctx context.Context, walletTrashDTO model.WalletTrashDTO) error
+	PurgeExpiredWallets(ctx context.Context, deletedBefore time.Time) (int64, error)
+}
+
+type TransactionService interface {
+	GetTransactionsByWalletId(ctx context.Context, userId, walletId uint64) ([]entity.Transaction, error)
+	GetTransactionById(ctx context.Context, userId, walletId, id uint64) (*entity.Transaction, error)
+	CreateTransaction(ctx context.Context, transactionCreateDTO model.TransactionCreateDTO) (*entity.Transaction, error)
+	UpdateTransaction(ctx context.Context, transactionUpdateDTO model.TransactionUpdateDTO) (*entity.Transaction, error)
+	DeleteTransaction(ctx context.Context, transactionDeleteDTO model.TransactionDeleteDTO) error
+}
+
+type TransferService interface {
+	CreateTransfer(ctx context.Context, transferCreateDTO model.TransferCreateDTO) (*entity.Transfer, error)
+}
+
+type IdempotencyService interface {
+	BeginRequest(ctx context.Context, idempotencyKeyDTO model.IdempotencyKeyDTO, leaseTimeout, ttl time.Duration) (*entity.IdempotencyKey, error)
+	CompleteRequest(ctx context.Context, idempotencyKeyDTO model.IdempotencyKeyDTO, statusCode int, response []byte) error
+	AbortRequest(ctx context.Context, idempotencyKeyDTO model.IdempotencyKeyDTO) error
+	DeleteExpiredKeys(ctx context.Context, completedBefore time.Time) (int64, error)
+}
+
+type ExchangeRateService interface {
+	ImportExchangeRates(ctx context.Context, format string, data io.Reader) (*model.ExchangeRateImportResult, error)
+	ImportExchangeRatesFromFile(ctx context.Context, path string) (*model.ExchangeRateImportResult, error)
+}
+
+type PreferencesService interface {
+	GetPreferences(ctx context.Context, userId uint64) (*entity.Preferences, error)
+	UpdatePreferences(ctx context.Context, preferencesUpdateDTO model.PreferencesUpdateDTO) (*entity.Preferences, error)
+}
+
+type OutboxService interface {
+	RelayEvents(ctx context.Context, publisher event.Publisher, limit int, claimTimeout time.Duration) (int, error)
+	DeletePublishedEvents(ctx context.Context, publishedBefore time.Time) (int64, error)
+}
+
+type WalletMemberService interface {
+	GetWalletMembers(ctx context.Context, userId, walletId uint64) ([]entity.WalletMember, error)
+	InviteWalletMember(ctx context.Context, walletMemberInviteDTO model.WalletMemberInviteDTO) (*entity.WalletMember, error)
+	RemoveWalletMember(ctx context.Context, walletMemberRemoveDTO model.WalletMemberRemoveDTO) error
+	GetInvitations(ctx context.Context, userId uint64) ([]entity.WalletMember, error)
+	AcceptInvitation(ctx context.Context, invitationDTO model.InvitationDTO) (*entity.WalletMember, error)
+	DeclineInvitation(ctx context.Context, invitationDTO model.InvitationDTO) error
 }
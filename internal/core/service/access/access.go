@@ -0,0 +1,37 @@
+package access
+
+import (
+	"context"
+	serviceerror "github.com/khivuksergey/portmonetka.wallet/error"
+	"github.com/khivuksergey/portmonetka.wallet/internal/adapter/storage/entity"
+	"github.com/khivuksergey/portmonetka.wallet/internal/core/port/repository"
+)
+
+// WalletRole returns the role of the user in the wallet: owner for the user who created it,
+// the role of the accepted membership otherwise, or empty if the user has no access to the wallet.
+func WalletRole(ctx context.Context, walletMemberRepository repository.WalletMemberRepository, wallet *entity.Wallet, userId uint64) (string, error) {
+	if wallet.UserId == userId {
+		return entity.WalletRoleOwner, nil
+	}
+	walletMember, err := walletMemberRepository.GetWalletMember(ctx, wallet.Id, userId)
+	if err != nil || walletMember == nil || !walletMember.IsAccepted() {
+		return "", err
+	}
+	return walletMember.Role, nil
+}
+
+// CheckWalletRole fails with WalletDoesntBelongToUser if the user has no access to the wallet
+// and with WalletPermissionDenied if the user's role doesn't include the required one.
+func CheckWalletRole(ctx context.Context, walletMemberRepository repository.WalletMemberRepository, wallet *entity.Wallet, userId uint64, required string) error {
+	role, err := WalletRole(ctx, walletMemberRepository, wallet, userId)
+	if err != nil {
+		return err
+	}
+	if role == "" {
+		return serviceerror.WalletDoesntBelongToUser
+	}
+	if !entity.WalletRoleAllows(role, required) {
+		return serviceerror.WalletPermissionDenied
+	}
+	return nil
+}
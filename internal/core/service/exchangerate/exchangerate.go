@@ -0,0 +1,85 @@
+package exchangerate
+
+import (
+	"context"
+	"fmt"
+	serviceerror "github.com/khivuksergey/portmonetka.wallet/error"
+	"github.com/khivuksergey/portmonetka.wallet/internal/adapter/storage/entity"
+	"github.com/khivuksergey/portmonetka.wallet/internal/core/port/repository"
+	"github.com/khivuksergey/portmonetka.wallet/internal/core/port/service"
+	"github.com/khivuksergey/portmonetka.wallet/internal/currency"
+	"github.com/khivuksergey/portmonetka.wallet/internal/model"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type exchangeRate struct {
+	exchangeRateRepository repository.ExchangeRateRepository
+}
+
+func NewExchangeRateService(repositoryManager *repository.Manager) service.ExchangeRateService {
+	return &exchangeRate{exchangeRateRepository: repositoryManager.ExchangeRate}
+}
+
+type exchangeRateKey struct {
+	date, base, quote string
+}
+
+// ImportExchangeRates parses rates in the given format and stores them,
+// replacing already known rates of the same pairs on the same dates.
+// Rates of currencies missing in the registry, e.g. withdrawn ones in historical files, are skipped.
+func (e *exchangeRate) ImportExchangeRates(ctx context.Context, format string, data io.Reader) (*model.ExchangeRateImportResult, error) {
+	var parsed []entity.ExchangeRate
+	var err error
+	switch format {
+	case model.ExchangeRateFormatXML:
+		parsed, err = parseECBXML(data)
+	case model.ExchangeRateFormatCSV:
+		parsed, err = parseCSV(data)
+	default:
+		return nil, serviceerror.ExchangeRateFormatError
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", serviceerror.ExchangeRateParseError, err)
+	}
+
+	result := &model.ExchangeRateImportResult{}
+	exchangeRates := make([]entity.ExchangeRate, 0, len(parsed))
+	// a rate repeated in the file replaces the earlier one, as a single upsert
+	// can't affect the same row twice
+	indexes := make(map[exchangeRateKey]int, len(parsed))
+	for _, exchangeRate := range parsed {
+		if !currency.IsValid(exchangeRate.BaseCurrency) || !currency.IsValid(exchangeRate.QuoteCurrency) {
+			result.Skipped++
+			continue
+		}
+		if !exchangeRate.Rate.IsPositive() {
+			return nil, fmt.Errorf("%w: %s/%s on %s", serviceerror.ExchangeRateError,
+				exchangeRate.BaseCurrency, exchangeRate.QuoteCurrency, exchangeRate.Date.Format(dateLayout))
+		}
+		key := exchangeRateKey{exchangeRate.Date.Format(dateLayout), exchangeRate.BaseCurrency, exchangeRate.QuoteCurrency}
+		if i, ok := indexes[key]; ok {
+			exchangeRates[i] = exchangeRate
+			continue
+		}
+		indexes[key] = len(exchangeRates)
+		exchangeRates = append(exchangeRates, exchangeRate)
+	}
+	if err = e.exchangeRateRepository.SaveExchangeRates(ctx, exchangeRates); err != nil {
+		return nil, err
+	}
+	result.Imported = len(exchangeRates)
+	return result, nil
+}
+
+// ImportExchangeRatesFromFile imports rates from the file, the format is taken from its extension.
+func (e *exchangeRate) ImportExchangeRatesFromFile(ctx context.Context, path string) (*model.ExchangeRateImportResult, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return e.ImportExchangeRates(ctx, strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), "."), file)
+}
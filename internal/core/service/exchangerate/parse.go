@@ -0,0 +1,132 @@
+package exchangerate
+
+import (
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"github.com/khivuksergey/portmonetka.wallet/internal/adapter/storage/entity"
+	"github.com/shopspring/decimal"
+	"io"
+	"strings"
+	"time"
+)
+
+// ecbBaseCurrency is the currency ECB reference rates are quoted against.
+const ecbBaseCurrency = "EUR"
+
+const dateLayout = time.DateOnly
+
+// ecbEnvelope is the eurofxref XML document: rates grouped by day, every rate is the price of 1 EUR.
+type ecbEnvelope struct {
+	Days []struct {
+		Time  string `xml:"time,attr"`
+		Rates []struct {
+			Currency string `xml:"currency,attr"`
+			Rate     string `xml:"rate,attr"`
+		} `xml:"Cube"`
+	} `xml:"Cube>Cube"`
+}
+
+// parseECBXML reads the ECB eurofxref daily or historical XML file.
+func parseECBXML(data io.Reader) ([]entity.ExchangeRate, error) {
+	var envelope ecbEnvelope
+	if err := xml.NewDecoder(data).Decode(&envelope); err != nil {
+		return nil, err
+	}
+	var exchangeRates []entity.ExchangeRate
+	for _, day := range envelope.Days {
+		for _, rate := range day.Rates {
+			exchangeRate, err := newExchangeRate(day.Time, ecbBaseCurrency, rate.Currency, rate.Rate)
+			if err != nil {
+				return nil, err
+			}
+			exchangeRates = append(exchangeRates, exchangeRate)
+		}
+	}
+	return exchangeRates, nil
+}
+
+// parseCSV reads rates either in the long format with the "date,base,quote,rate" header
+// or in the ECB eurofxref format with a "Date" column followed by a column per currency.
+func parseCSV(data io.Reader) ([]entity.ExchangeRate, error) {
+	reader := csv.NewReader(data)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, errors.New("empty CSV file")
+	}
+	header := normalizeHeader(records[0])
+	if strings.Join(header, ",") == "date,base,quote,rate" {
+		return parseLongCSV(records[1:])
+	}
+	if len(header) > 1 && header[0] == "date" {
+		return parseWideCSV(records[0], records[1:])
+	}
+	return nil, fmt.Errorf("unknown CSV header %q", strings.Join(records[0], ","))
+}
+
+func parseLongCSV(records [][]string) ([]entity.ExchangeRate, error) {
+	exchangeRates := make([]entity.ExchangeRate, 0, len(records))
+	for i, record := range records {
+		if len(record) < 4 {
+			return nil, fmt.Errorf("line %d: expected 4 fields", i+2)
+		}
+		exchangeRate, err := newExchangeRate(record[0], record[1], record[2], record[3])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+2, err)
+		}
+		exchangeRates = append(exchangeRates, exchangeRate)
+	}
+	return exchangeRates, nil
+}
+
+func parseWideCSV(header []string, records [][]string) ([]entity.ExchangeRate, error) {
+	var exchangeRates []entity.ExchangeRate
+	for i, record := range records {
+		for column := 1; column < len(record) && column < len(header); column++ {
+			quote, rate := strings.TrimSpace(header[column]), strings.TrimSpace(record[column])
+			// ECB files end lines with a comma and mark missing rates with N/A
+			if quote == "" || rate == "" || rate == "N/A" {
+				continue
+			}
+			exchangeRate, err := newExchangeRate(record[0], ecbBaseCurrency, quote, rate)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", i+2, err)
+			}
+			exchangeRates = append(exchangeRates, exchangeRate)
+		}
+	}
+	return exchangeRates, nil
+}
+
+func newExchangeRate(date, base, quote, rate string) (entity.ExchangeRate, error) {
+	parsedDate, err := time.Parse(dateLayout, strings.TrimSpace(date))
+	if err != nil {
+		return entity.ExchangeRate{}, fmt.Errorf("invalid date %q", date)
+	}
+	parsedRate, err := decimal.NewFromString(strings.TrimSpace(rate))
+	if err != nil {
+		return entity.ExchangeRate{}, fmt.Errorf("invalid rate %q", rate)
+	}
+	return entity.ExchangeRate{
+		Date:          parsedDate,
+		BaseCurrency:  strings.ToUpper(strings.TrimSpace(base)),
+		QuoteCurrency: strings.ToUpper(strings.TrimSpace(quote)),
+		Rate:          parsedRate,
+	}, nil
+}
+
+func normalizeHeader(header []string) []string {
+	normalized := make([]string, 0, len(header))
+	for _, column := range header {
+		if column = strings.ToLower(strings.TrimSpace(column)); column != "" {
+			normalized = append(normalized, column)
+		}
+	}
+	return normalized
+}
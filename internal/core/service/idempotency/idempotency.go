@@ -0,0 +1,105 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	serviceerror "github.com/khivuksergey/portmonetka.wallet/error"
+	"github.com/khivuksergey/portmonetka.wallet/internal/adapter/storage/entity"
+	"github.com/khivuksergey/portmonetka.wallet/internal/core/port/repository"
+	"github.com/khivuksergey/portmonetka.wallet/internal/core/port/service"
+	"github.com/khivuksergey/portmonetka.wallet/internal/model"
+	"time"
+)
+
+type idempotency struct {
+	idempotencyRepository repository.IdempotencyRepository
+}
+
+func NewIdempotencyService(repositoryManager *repository.Manager) service.IdempotencyService {
+	return &idempotency{idempotencyRepository: repositoryManager.Idempotency}
+}
+
+// BeginRequest reserves the idempotency key for the request. It returns nil if the request
+// must be processed, or the stored key with the original response if it must be replayed.
+// A key pending longer than leaseTimeout, whose request has likely died, and a key completed
+// longer than ttl ago, unless ttl is zero, are reserved for the request anew.
+func (i *idempotency) BeginRequest(ctx context.Context, idempotencyKeyDTO model.IdempotencyKeyDTO, leaseTimeout, ttl time.Duration) (*entity.IdempotencyKey, error) {
+	if len(idempotencyKeyDTO.Key) == 0 || len(idempotencyKeyDTO.Key) > 255 {
+		return nil, serviceerror.IdempotencyKeyLengthError
+	}
+	existing, err := i.idempotencyRepository.GetIdempotencyKey(ctx, idempotencyKeyDTO.UserId, idempotencyKeyDTO.Key)
+	if err == nil && existing != nil {
+		return i.checkStoredKey(ctx, existing, idempotencyKeyDTO, leaseTimeout, ttl)
+	}
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return nil, err
+	}
+	err = i.idempotencyRepository.CreateIdempotencyKey(ctx, &entity.IdempotencyKey{
+		UserId:      idempotencyKeyDTO.UserId,
+		Key:         idempotencyKeyDTO.Key,
+		RequestHash: idempotencyKeyDTO.RequestHash,
+		ReservedAt:  time.Now().UTC(),
+	})
+	if errors.Is(err, repository.ErrDuplicatedKey) {
+		// the key has been reserved by a concurrent request in the meantime
+		existing, getErr := i.idempotencyRepository.GetIdempotencyKey(ctx, idempotencyKeyDTO.UserId, idempotencyKeyDTO.Key)
+		if getErr != nil || existing == nil {
+			return nil, err
+		}
+		return i.checkStoredKey(ctx, existing, idempotencyKeyDTO, leaseTimeout, ttl)
+	}
+	return nil, err
+}
+
+func (i *idempotency) CompleteRequest(ctx context.Context, idempotencyKeyDTO model.IdempotencyKeyDTO, statusCode int, response []byte) error {
+	idempotencyKey, err := i.idempotencyRepository.GetIdempotencyKey(ctx, idempotencyKeyDTO.UserId, idempotencyKeyDTO.Key)
+	if err != nil {
+		return err
+	}
+	idempotencyKey.StatusCode = statusCode
+	idempotencyKey.Response = response
+	return i.idempotencyRepository.UpdateIdempotencyKey(ctx, idempotencyKey)
+}
+
+// AbortRequest releases the key reserved by BeginRequest, so the failed request can be retried.
+func (i *idempotency) AbortRequest(ctx context.Context, idempotencyKeyDTO model.IdempotencyKeyDTO) error {
+	idempotencyKey, err := i.idempotencyRepository.GetIdempotencyKey(ctx, idempotencyKeyDTO.UserId, idempotencyKeyDTO.Key)
+	if err != nil {
+		return err
+	}
+	return i.idempotencyRepository.DeleteIdempotencyKey(ctx, idempotencyKey.Id)
+}
+
+// DeleteExpiredKeys deletes the keys completed before the given time and returns their number.
+func (i *idempotency) DeleteExpiredKeys(ctx context.Context, completedBefore time.Time) (int64, error) {
+	return i.idempotencyRepository.DeleteIdempotencyKeysCompletedBefore(ctx, completedBefore)
+}
+
+func (i *idempotency) checkStoredKey(ctx context.Context, idempotencyKey *entity.IdempotencyKey, idempotencyKeyDTO model.IdempotencyKeyDTO, leaseTimeout, ttl time.Duration) (*entity.IdempotencyKey, error) {
+	now := time.Now()
+	staleBefore := now.Add(-leaseTimeout)
+	var expiredBefore time.Time
+	if ttl > 0 {
+		expiredBefore = now.Add(-ttl)
+	}
+	stale := idempotencyKey.IsPending() && idempotencyKey.ReservedAt.Before(staleBefore)
+	expired := !idempotencyKey.IsPending() && idempotencyKey.UpdatedAt.Before(expiredBefore)
+	if stale || expired {
+		reserved, err := i.idempotencyRepository.ReserveStaleIdempotencyKey(ctx, idempotencyKey.Id, idempotencyKeyDTO.RequestHash, staleBefore, expiredBefore)
+		if err != nil {
+			return nil, err
+		}
+		if !reserved {
+			// a concurrent request has reserved the key first
+			return nil, serviceerror.IdempotencyKeyInProgress
+		}
+		return nil, nil
+	}
+	if idempotencyKey.RequestHash != idempotencyKeyDTO.RequestHash {
+		return nil, serviceerror.IdempotencyKeyReused
+	}
+	if idempotencyKey.IsPending() {
+		return nil, serviceerror.IdempotencyKeyInProgress
+	}
+	return idempotencyKey, nil
+}
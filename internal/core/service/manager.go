@@ -3,11 +3,25 @@ package service
 import (
 	"github.com/khivuksergey/portmonetka.wallet/internal/core/port/repository"
 	"github.com/khivuksergey/portmonetka.wallet/internal/core/port/service"
+	"github.com/khivuksergey/portmonetka.wallet/internal/core/service/exchangerate"
+	"github.com/khivuksergey/portmonetka.wallet/internal/core/service/idempotency"
+	"github.com/khivuksergey/portmonetka.wallet/internal/core/service/member"
+	"github.com/khivuksergey/portmonetka.wallet/internal/core/service/outbox"
+	"github.com/khivuksergey/portmonetka.wallet/internal/core/service/preferences"
+	"github.com/khivuksergey/portmonetka.wallet/internal/core/service/transaction"
+	"github.com/khivuksergey/portmonetka.wallet/internal/core/service/transfer"
 	"github.com/khivuksergey/portmonetka.wallet/internal/core/service/wallet"
 )
 
 func NewServiceManager(repositoryManager *repository.Manager) *service.Manager {
 	return &service.Manager{
-		Wallet: wallet.NewWalletService(repositoryManager),
+		Wallet:       wallet.NewWalletService(repositoryManager),
+		Transaction:  transaction.NewTransactionService(repositoryManager),
+		Transfer:     transfer.NewTransferService(repositoryManager),
+		Idempotency:  idempotency.NewIdempotencyService(repositoryManager),
+		ExchangeRate: exchangerate.NewExchangeRateService(repositoryManager),
+		Preferences:  preferences.NewPreferencesService(repositoryManager),
+		WalletMember: member.NewWalletMemberService(repositoryManager),
+		Outbox:       outbox.NewOutboxService(repositoryManager),
 	}
 }
@@ -0,0 +1,134 @@
+package member
+
+import (
+	"context"
+	"errors"
+	serviceerror "github.com/khivuksergey/portmonetka.wallet/error"
+	"github.com/khivuksergey/portmonetka.wallet/internal/adapter/storage/entity"
+	"github.com/khivuksergey/portmonetka.wallet/internal/core/port/repository"
+	"github.com/khivuksergey/portmonetka.wallet/internal/core/port/service"
+	"github.com/khivuksergey/portmonetka.wallet/internal/core/service/access"
+	"github.com/khivuksergey/portmonetka.wallet/internal/model"
+	"time"
+)
+
+type walletMember struct {
+	walletRepository       repository.WalletRepository
+	walletMemberRepository repository.WalletMemberRepository
+}
+
+func NewWalletMemberService(repositoryManager *repository.Manager) service.WalletMemberService {
+	return &walletMember{
+		walletRepository:       repositoryManager.Wallet,
+		walletMemberRepository: repositoryManager.WalletMember,
+	}
+}
+
+// GetWalletMembers returns members and pending invitations of the wallet to any of its members.
+func (m *walletMember) GetWalletMembers(ctx context.Context, userId, walletId uint64) ([]entity.WalletMember, error) {
+	if _, err := m.getUserWallet(ctx, walletId, userId, entity.WalletRoleViewer); err != nil {
+		return nil, err
+	}
+	return m.walletMemberRepository.GetWalletMembers(ctx, walletId)
+}
+
+// InviteWalletMember creates an invitation to the wallet, only owners can invite.
+func (m *walletMember) InviteWalletMember(ctx context.Context, walletMemberInviteDTO model.WalletMemberInviteDTO) (*entity.WalletMember, error) {
+	wallet, err := m.getUserWallet(ctx, walletMemberInviteDTO.WalletId, walletMemberInviteDTO.UserId, entity.WalletRoleOwner)
+	if err != nil {
+		return nil, err
+	}
+	if walletMemberInviteDTO.InviteeId == wallet.UserId {
+		return nil, serviceerror.WalletOwnerInvited
+	}
+	existing, err := m.walletMemberRepository.GetWalletMember(ctx, wallet.Id, walletMemberInviteDTO.InviteeId)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, serviceerror.WalletMemberAlreadyExists
+	}
+	created, err := m.walletMemberRepository.CreateWalletMember(ctx, &entity.WalletMember{
+		WalletId:  wallet.Id,
+		UserId:    walletMemberInviteDTO.InviteeId,
+		Role:      walletMemberInviteDTO.Role,
+		InvitedBy: walletMemberInviteDTO.UserId,
+	})
+	if errors.Is(err, repository.ErrDuplicatedKey) {
+		// the user has been invited by a concurrent request in the meantime
+		return nil, serviceerror.WalletMemberAlreadyExists
+	}
+	return created, err
+}
+
+// RemoveWalletMember removes the member or cancels the invitation. Owners can remove anyone,
+// other members can only leave the wallet themselves.
+func (m *walletMember) RemoveWalletMember(ctx context.Context, walletMemberRemoveDTO model.WalletMemberRemoveDTO) error {
+	wallet, err := m.getUserWallet(ctx, walletMemberRemoveDTO.WalletId, walletMemberRemoveDTO.UserId, entity.WalletRoleViewer)
+	if err != nil {
+		return err
+	}
+	walletMemberToRemove, err := m.walletMemberRepository.GetWalletMemberById(ctx, walletMemberRemoveDTO.Id)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return err
+	}
+	if err != nil || walletMemberToRemove == nil || walletMemberToRemove.WalletId != wallet.Id {
+		return serviceerror.WalletMemberDoesntExist
+	}
+	if walletMemberToRemove.UserId != walletMemberRemoveDTO.UserId {
+		err = access.CheckWalletRole(ctx, m.walletMemberRepository, wallet, walletMemberRemoveDTO.UserId, entity.WalletRoleOwner)
+		if err != nil {
+			return err
+		}
+	}
+	return m.walletMemberRepository.DeleteWalletMember(ctx, walletMemberToRemove.Id)
+}
+
+func (m *walletMember) GetInvitations(ctx context.Context, userId uint64) ([]entity.WalletMember, error) {
+	return m.walletMemberRepository.GetPendingInvitations(ctx, userId)
+}
+
+func (m *walletMember) AcceptInvitation(ctx context.Context, invitationDTO model.InvitationDTO) (*entity.WalletMember, error) {
+	invitation, err := m.getPendingInvitation(ctx, invitationDTO)
+	if err != nil {
+		return nil, err
+	}
+	acceptedAt := time.Now()
+	invitation.AcceptedAt = &acceptedAt
+	return m.walletMemberRepository.UpdateWalletMember(ctx, invitation)
+}
+
+func (m *walletMember) DeclineInvitation(ctx context.Context, invitationDTO model.InvitationDTO) error {
+	invitation, err := m.getPendingInvitation(ctx, invitationDTO)
+	if err != nil {
+		return err
+	}
+	return m.walletMemberRepository.DeleteWalletMember(ctx, invitation.Id)
+}
+
+func (m *walletMember) getUserWallet(ctx context.Context, walletId, userId uint64, requiredRole string) (*entity.Wallet, error) {
+	wallet, err := m.walletRepository.GetWalletById(ctx, walletId)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return nil, err
+	}
+	if err != nil || wallet == nil {
+		return nil, serviceerror.WalletDoesntExist
+	}
+	if err = access.CheckWalletRole(ctx, m.walletMemberRepository, wallet, userId, requiredRole); err != nil {
+		return nil, err
+	}
+	return wallet, nil
+}
+
+// getPendingInvitation returns the invitation only if it is addressed to the user and not accepted yet,
+// so an invitation of another user is indistinguishable from a missing one.
+func (m *walletMember) getPendingInvitation(ctx context.Context, invitationDTO model.InvitationDTO) (*entity.WalletMember, error) {
+	invitation, err := m.walletMemberRepository.GetWalletMemberById(ctx, invitationDTO.Id)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return nil, err
+	}
+	if err != nil || invitation == nil || invitation.UserId != invitationDTO.UserId || invitation.IsAccepted() {
+		return nil, serviceerror.InvitationDoesntExist
+	}
+	return invitation, nil
+}
@@ -0,0 +1,60 @@
+package outbox
+
+import (
+	"context"
+	"github.com/khivuksergey/portmonetka.wallet/internal/core/port/event"
+	"github.com/khivuksergey/portmonetka.wallet/internal/core/port/repository"
+	"github.com/khivuksergey/portmonetka.wallet/internal/core/port/service"
+	"time"
+)
+
+type outbox struct {
+	repositoryManager *repository.Manager
+}
+
+func NewOutboxService(repositoryManager *repository.Manager) service.OutboxService {
+	return &outbox{repositoryManager: repositoryManager}
+}
+
+// RelayEvents claims up to limit oldest unpublished events until claimTimeout passes and publishes them
+// outside any transaction, so slow publishing doesn't hold database locks. Publishing stops at the first
+// failure, then the published events are marked and the rest are released in a short unit of work, so the
+// failed event and the ones after it are relayed in order next time. Events of a relay which died while
+// publishing are claimed again after the timeout, so an event can be published more than once.
+// It returns the number of published events.
+func (o *outbox) RelayEvents(ctx context.Context, publisher event.Publisher, limit int, claimTimeout time.Duration) (int, error) {
+	outboxEvents, err := o.repositoryManager.Outbox.ClaimOutboxEvents(ctx, limit, time.Now().Add(claimTimeout))
+	if err != nil || len(outboxEvents) == 0 {
+		return 0, err
+	}
+	relayed := 0
+	var publishErr error
+	for _, outboxEvent := range outboxEvents {
+		if publishErr = publisher.Publish(ctx, outboxEvent); publishErr != nil {
+			break
+		}
+		relayed++
+	}
+	err = o.repositoryManager.WithinTransaction(ctx, func(ctx context.Context, repositories *repository.Manager) error {
+		publishedAt := time.Now()
+		for _, outboxEvent := range outboxEvents[:relayed] {
+			if err := repositories.Outbox.MarkOutboxEventPublished(ctx, outboxEvent.Id, publishedAt); err != nil {
+				return err
+			}
+		}
+		unpublished := make([]uint64, 0, len(outboxEvents)-relayed)
+		for _, outboxEvent := range outboxEvents[relayed:] {
+			unpublished = append(unpublished, outboxEvent.Id)
+		}
+		return repositories.Outbox.ReleaseOutboxEvents(ctx, unpublished)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return relayed, publishErr
+}
+
+// DeletePublishedEvents deletes the events published before the given time and returns their number.
+func (o *outbox) DeletePublishedEvents(ctx context.Context, publishedBefore time.Time) (int64, error) {
+	return o.repositoryManager.Outbox.DeletePublishedOutboxEvents(ctx, publishedBefore)
+}
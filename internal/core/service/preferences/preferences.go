@@ -0,0 +1,84 @@
+package preferences
+
+import (
+	"context"
+	"errors"
+	serviceerror "github.com/khivuksergey/portmonetka.wallet/error"
+	"github.com/khivuksergey/portmonetka.wallet/internal/adapter/storage/entity"
+	"github.com/khivuksergey/portmonetka.wallet/internal/core/port/repository"
+	"github.com/khivuksergey/portmonetka.wallet/internal/core/port/service"
+	"github.com/khivuksergey/portmonetka.wallet/internal/core/service/access"
+	"github.com/khivuksergey/portmonetka.wallet/internal/currency"
+	"github.com/khivuksergey/portmonetka.wallet/internal/model"
+)
+
+type preferences struct {
+	preferencesRepository  repository.PreferencesRepository
+	walletRepository       repository.WalletRepository
+	walletMemberRepository repository.WalletMemberRepository
+}
+
+func NewPreferencesService(repositoryManager *repository.Manager) service.PreferencesService {
+	return &preferences{
+		preferencesRepository:  repositoryManager.Preferences,
+		walletRepository:       repositoryManager.Wallet,
+		walletMemberRepository: repositoryManager.WalletMember,
+	}
+}
+
+// GetPreferences returns user's stored preferences or the defaults if there are none.
+func (p *preferences) GetPreferences(ctx context.Context, userId uint64) (*entity.Preferences, error) {
+	stored, err := p.preferencesRepository.GetPreferences(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	if stored == nil {
+		return &entity.Preferences{
+			UserId:        userId,
+			Locale:        model.DefaultLocale,
+			MonthStartDay: model.DefaultMonthStartDay,
+		}, nil
+	}
+	return stored, nil
+}
+
+func (p *preferences) UpdatePreferences(ctx context.Context, preferencesUpdateDTO model.PreferencesUpdateDTO) (*entity.Preferences, error) {
+	updated := &entity.Preferences{
+		UserId:          preferencesUpdateDTO.UserId,
+		DefaultWalletId: preferencesUpdateDTO.DefaultWalletId,
+		Locale:          preferencesUpdateDTO.Locale,
+		MonthStartDay:   preferencesUpdateDTO.MonthStartDay,
+	}
+	if preferencesUpdateDTO.BaseCurrency != "" {
+		baseCurrency, ok := currency.Get(preferencesUpdateDTO.BaseCurrency)
+		if !ok {
+			return nil, serviceerror.WalletCurrencyError
+		}
+		updated.BaseCurrency = baseCurrency.Code
+	}
+	if updated.DefaultWalletId != nil {
+		wallet, err := p.walletRepository.GetWalletById(ctx, *updated.DefaultWalletId)
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, serviceerror.WalletDoesntExist
+		}
+		if err != nil {
+			return nil, err
+		}
+		err = access.CheckWalletRole(ctx, p.walletMemberRepository, wallet, updated.UserId, entity.WalletRoleViewer)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if updated.Locale == "" {
+		updated.Locale = model.DefaultLocale
+	}
+	if updated.MonthStartDay == 0 {
+		updated.MonthStartDay = model.DefaultMonthStartDay
+	}
+	saved, err := p.preferencesRepository.SavePreferences(ctx, updated)
+	if errors.Is(err, repository.ErrForeignKeyViolated) {
+		// the default wallet has been purged in the meantime
+		return nil, serviceerror.WalletDoesntExist
+	}
+	return saved, err
+}
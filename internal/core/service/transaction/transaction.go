@@ -0,0 +1,230 @@
+package transaction
+
+import (
+	"context"
+	"errors"
+	serviceerror "github.com/khivuksergey/portmonetka.wallet/error"
+	"github.com/khivuksergey/portmonetka.wallet/internal/adapter/storage/entity"
+	"github.com/khivuksergey/portmonetka.wallet/internal/core/port/repository"
+	"github.com/khivuksergey/portmonetka.wallet/internal/core/port/service"
+	"github.com/khivuksergey/portmonetka.wallet/internal/core/service/access"
+	"github.com/khivuksergey/portmonetka.wallet/internal/currency"
+	"github.com/khivuksergey/portmonetka.wallet/internal/model"
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+type walletGetter func(ctx context.Context, id uint64) (*entity.Wallet, error)
+
+type transaction struct {
+	repositoryManager      *repository.Manager
+	walletRepository       repository.WalletRepository
+	transactionRepository  repository.TransactionRepository
+	walletMemberRepository repository.WalletMemberRepository
+}
+
+func NewTransactionService(repositoryManager *repository.Manager) service.TransactionService {
+	return newTransaction(repositoryManager)
+}
+
+func newTransaction(repositoryManager *repository.Manager) *transaction {
+	return &transaction{
+		repositoryManager:      repositoryManager,
+		walletRepository:       repositoryManager.Wallet,
+		transactionRepository:  repositoryManager.Transaction,
+		walletMemberRepository: repositoryManager.WalletMember,
+	}
+}
+
+func (t *transaction) GetTransactionsByWalletId(ctx context.Context, userId, walletId uint64) ([]entity.Transaction, error) {
+	if _, err := t.getUserWallet(ctx, t.walletRepository.GetWalletById, walletId, userId, entity.WalletRoleViewer); err != nil {
+		return nil, err
+	}
+	return t.transactionRepository.GetTransactionsByWalletId(ctx, walletId)
+}
+
+func (t *transaction) GetTransactionById(ctx context.Context, userId, walletId, id uint64) (*entity.Transaction, error) {
+	if _, err := t.getUserWallet(ctx, t.walletRepository.GetWalletById, walletId, userId, entity.WalletRoleViewer); err != nil {
+		return nil, err
+	}
+	return t.getWalletTransaction(ctx, walletId, id)
+}
+
+// CreateTransaction posts the transaction in a unit of work with the balance check,
+// as do UpdateTransaction and DeleteTransaction.
+func (t *transaction) CreateTransaction(ctx context.Context, transactionCreateDTO model.TransactionCreateDTO) (created *entity.Transaction, err error) {
+	err = t.repositoryManager.WithinTransaction(ctx, func(ctx context.Context, repositories *repository.Manager) error {
+		created, err = newTransaction(repositories).createTransaction(ctx, transactionCreateDTO)
+		return err
+	})
+	return created, err
+}
+
+func (t *transaction) UpdateTransaction(ctx context.Context, transactionUpdateDTO model.TransactionUpdateDTO) (updated *entity.Transaction, err error) {
+	err = t.repositoryManager.WithinTransaction(ctx, func(ctx context.Context, repositories *repository.Manager) error {
+		updated, err = newTransaction(repositories).updateTransaction(ctx, transactionUpdateDTO)
+		return err
+	})
+	return updated, err
+}
+
+func (t *transaction) DeleteTransaction(ctx context.Context, transactionDeleteDTO model.TransactionDeleteDTO) error {
+	return t.repositoryManager.WithinTransaction(ctx, func(ctx context.Context, repositories *repository.Manager) error {
+		return newTransaction(repositories).deleteTransaction(ctx, transactionDeleteDTO)
+	})
+}
+
+func (t *transaction) createTransaction(ctx context.Context, transactionCreateDTO model.TransactionCreateDTO) (*entity.Transaction, error) {
+	wallet, err := t.getUserWallet(ctx, t.walletRepository.GetWalletForUpdate, transactionCreateDTO.WalletId, transactionCreateDTO.UserId, entity.WalletRoleEditor)
+	if err != nil {
+		return nil, err
+	}
+	if !transactionCreateDTO.Amount.IsPositive() {
+		return nil, serviceerror.TransactionAmountError
+	}
+	if !fitsWalletCurrency(wallet, transactionCreateDTO.Amount) {
+		return nil, serviceerror.TransactionAmountPrecisionError
+	}
+	timestamp := transactionCreateDTO.Timestamp
+	if timestamp.IsZero() {
+		timestamp = time.Now()
+	}
+	transactionToCreate := &entity.Transaction{
+		WalletId:  transactionCreateDTO.WalletId,
+		Amount:    transactionCreateDTO.Amount,
+		Direction: transactionCreateDTO.Direction,
+		Timestamp: timestamp,
+		Note:      transactionCreateDTO.Note,
+		Category:  transactionCreateDTO.Category,
+	}
+	if err = checkBalanceFloor(wallet, transactionToCreate.SignedAmount()); err != nil {
+		return nil, err
+	}
+	return t.transactionRepository.CreateTransaction(ctx, transactionToCreate)
+}
+
+func (t *transaction) updateTransaction(ctx context.Context, transactionUpdateDTO model.TransactionUpdateDTO) (*entity.Transaction, error) {
+	wallet, err := t.getUserWallet(ctx, t.walletRepository.GetWalletForUpdate, transactionUpdateDTO.WalletId, transactionUpdateDTO.UserId, entity.WalletRoleEditor)
+	if err != nil {
+		return nil, err
+	}
+	transactionToUpdate, err := t.getWalletTransaction(ctx, transactionUpdateDTO.WalletId, transactionUpdateDTO.Id)
+	if err != nil {
+		return nil, err
+	}
+	if transactionToUpdate.TransferId != nil {
+		return nil, serviceerror.TransactionIsTransferPart
+	}
+	previousAmount := transactionToUpdate.SignedAmount()
+	err = validateUpdateTransactionAttributes(wallet, transactionToUpdate, transactionUpdateDTO)
+	if err != nil {
+		return nil, err
+	}
+	err = checkBalanceFloor(wallet, transactionToUpdate.SignedAmount().Sub(previousAmount))
+	if err != nil {
+		return nil, err
+	}
+	return t.transactionRepository.UpdateTransaction(ctx, transactionToUpdate)
+}
+
+func (t *transaction) deleteTransaction(ctx context.Context, transactionDeleteDTO model.TransactionDeleteDTO) error {
+	wallet, err := t.getUserWallet(ctx, t.walletRepository.GetWalletForUpdate, transactionDeleteDTO.WalletId, transactionDeleteDTO.UserId, entity.WalletRoleEditor)
+	if err != nil {
+		return err
+	}
+	transactionToDelete, err := t.getWalletTransaction(ctx, transactionDeleteDTO.WalletId, transactionDeleteDTO.Id)
+	if err != nil {
+		return err
+	}
+	if transactionToDelete.TransferId != nil {
+		return serviceerror.TransactionIsTransferPart
+	}
+	if err = checkBalanceFloor(wallet, transactionToDelete.SignedAmount().Neg()); err != nil {
+		return err
+	}
+	return t.transactionRepository.DeleteTransaction(ctx, transactionDeleteDTO.Id)
+}
+
+// checkBalanceFloor rejects the change of the wallet balance if it is a debit bringing
+// the balance below the floor permitted by the wallet settings. Credits are always allowed,
+// so a wallet which is already below the floor can be topped up.
+func checkBalanceFloor(wallet *entity.Wallet, change decimal.Decimal) error {
+	if !change.IsNegative() {
+		return nil
+	}
+	floor, ok := wallet.BalanceFloor()
+	if balance := wallet.CurrentBalance.Add(change); ok && balance.LessThan(floor) {
+		return serviceerror.NewInsufficientFundsError(wallet.Id, balance, floor)
+	}
+	return nil
+}
+
+// fitsWalletCurrency reports whether the amount has no more decimal places than the wallet currency allows.
+// Amounts of wallets with currencies unknown to the registry are not checked, as transfers do.
+func fitsWalletCurrency(wallet *entity.Wallet, amount decimal.Decimal) bool {
+	walletCurrency, ok := currency.Get(wallet.Currency)
+	return !ok || walletCurrency.FitsMinorUnits(amount)
+}
+
+// getUserWallet returns the wallet if the user's role in it includes the required one:
+// transactions are readable by any member and writable by owners and editors.
+// Changes load the wallet with GetWalletForUpdate, so concurrent balance checks don't
+// pass on the same balance.
+func (t *transaction) getUserWallet(ctx context.Context, getWallet walletGetter, walletId, userId uint64, requiredRole string) (*entity.Wallet, error) {
+	wallet, err := getWallet(ctx, walletId)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return nil, err
+	}
+	if err != nil || wallet == nil {
+		return nil, serviceerror.WalletDoesntExist
+	}
+	if err = access.CheckWalletRole(ctx, t.walletMemberRepository, wallet, userId, requiredRole); err != nil {
+		return nil, err
+	}
+	return wallet, nil
+}
+
+// getWalletTransaction returns the transaction only if it is posted to the given wallet,
+// so a transaction id from another wallet is indistinguishable from a missing one.
+func (t *transaction) getWalletTransaction(ctx context.Context, walletId, id uint64) (*entity.Transaction, error) {
+	transaction, err := t.transactionRepository.GetTransactionById(ctx, id)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return nil, err
+	}
+	if err != nil || transaction == nil || transaction.WalletId != walletId {
+		return nil, serviceerror.TransactionDoesntExist
+	}
+	return transaction, nil
+}
+
+func validateUpdateTransactionAttributes(wallet *entity.Wallet, transaction *entity.Transaction, transactionUpdateDTO model.TransactionUpdateDTO) error {
+	if transactionUpdateDTO.Amount == nil &&
+		transactionUpdateDTO.Direction == nil &&
+		transactionUpdateDTO.Timestamp == nil &&
+		transactionUpdateDTO.Note == nil &&
+		transactionUpdateDTO.Category == nil {
+		return serviceerror.AtLeastOneTransactionField
+	}
+	if transactionUpdateDTO.Amount != nil {
+		if !transactionUpdateDTO.Amount.IsPositive() {
+			return serviceerror.TransactionAmountError
+		}
+		if !fitsWalletCurrency(wallet, *transactionUpdateDTO.Amount) {
+			return serviceerror.TransactionAmountPrecisionError
+		}
+		transaction.Amount = *transactionUpdateDTO.Amount
+	}
+	if transactionUpdateDTO.Direction != nil {
+		transaction.Direction = *transactionUpdateDTO.Direction
+	}
+	if transactionUpdateDTO.Timestamp != nil {
+		transaction.Timestamp = *transactionUpdateDTO.Timestamp
+	}
+	if transactionUpdateDTO.Note != nil {
+		transaction.Note = *transactionUpdateDTO.Note
+	}
+	if transactionUpdateDTO.Category != nil {
+		transaction.Category = *transactionUpdateDTO.Category
+	}
+	return nil
+}
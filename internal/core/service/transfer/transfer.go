@@ -0,0 +1,188 @@
+package transfer
+
+import (
+	"context"
+	"errors"
+	serviceerror "github.com/khivuksergey/portmonetka.wallet/error"
+	"github.com/khivuksergey/portmonetka.wallet/internal/adapter/storage/entity"
+	"github.com/khivuksergey/portmonetka.wallet/internal/core/port/repository"
+	"github.com/khivuksergey/portmonetka.wallet/internal/core/port/service"
+	"github.com/khivuksergey/portmonetka.wallet/internal/core/service/access"
+	"github.com/khivuksergey/portmonetka.wallet/internal/currency"
+	"github.com/khivuksergey/portmonetka.wallet/internal/model"
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+const ratePrecision = 8
+
+type walletGetter func(ctx context.Context, id uint64) (*entity.Wallet, error)
+
+type transfer struct {
+	repositoryManager      *repository.Manager
+	walletRepository       repository.WalletRepository
+	transferRepository     repository.TransferRepository
+	walletMemberRepository repository.WalletMemberRepository
+	preferencesRepository  repository.PreferencesRepository
+}
+
+func NewTransferService(repositoryManager *repository.Manager) service.TransferService {
+	return newTransfer(repositoryManager)
+}
+
+func newTransfer(repositoryManager *repository.Manager) *transfer {
+	return &transfer{
+		repositoryManager:      repositoryManager,
+		walletRepository:       repositoryManager.Wallet,
+		transferRepository:     repositoryManager.Transfer,
+		walletMemberRepository: repositoryManager.WalletMember,
+		preferencesRepository:  repositoryManager.Preferences,
+	}
+}
+
+// CreateTransfer checks both wallets and posts the transfer in a single unit of work.
+func (t *transfer) CreateTransfer(ctx context.Context, transferCreateDTO model.TransferCreateDTO) (created *entity.Transfer, err error) {
+	err = t.repositoryManager.WithinTransaction(ctx, func(ctx context.Context, repositories *repository.Manager) error {
+		created, err = newTransfer(repositories).createTransfer(ctx, transferCreateDTO)
+		return err
+	})
+	return created, err
+}
+
+func (t *transfer) createTransfer(ctx context.Context, transferCreateDTO model.TransferCreateDTO) (*entity.Transfer, error) {
+	if transferCreateDTO.SourceWalletId == 0 {
+		sourceWalletId, err := t.defaultWalletId(ctx, transferCreateDTO.UserId)
+		if err != nil {
+			return nil, err
+		}
+		transferCreateDTO.SourceWalletId = sourceWalletId
+	}
+	if transferCreateDTO.SourceWalletId == transferCreateDTO.TargetWalletId {
+		return nil, serviceerror.TransferSameWallet
+	}
+	if !transferCreateDTO.Amount.IsPositive() {
+		return nil, serviceerror.TransferAmountError
+	}
+	source, err := t.getUserWallet(ctx, t.walletRepository.GetWalletForUpdate, transferCreateDTO.SourceWalletId, transferCreateDTO.UserId)
+	if err != nil {
+		return nil, err
+	}
+	target, err := t.getUserWallet(ctx, t.walletRepository.GetWalletById, transferCreateDTO.TargetWalletId, transferCreateDTO.UserId)
+	if err != nil {
+		return nil, err
+	}
+	if sourceCurrency, ok := currency.Get(source.Currency); ok && !sourceCurrency.FitsMinorUnits(transferCreateDTO.Amount) {
+		return nil, serviceerror.TransferAmountPrecisionError
+	}
+	targetAmount, rate, err := convert(source.Currency, target.Currency, transferCreateDTO)
+	if err != nil {
+		return nil, err
+	}
+	floor, ok := source.BalanceFloor()
+	if balance := source.CurrentBalance.Sub(transferCreateDTO.Amount); ok && balance.LessThan(floor) {
+		return nil, serviceerror.NewInsufficientFundsError(source.Id, balance, floor)
+	}
+	timestamp := transferCreateDTO.Timestamp
+	if timestamp.IsZero() {
+		timestamp = time.Now()
+	}
+	return t.transferRepository.CreateTransfer(ctx, &entity.Transfer{
+		UserId:         transferCreateDTO.UserId,
+		SourceWalletId: source.Id,
+		TargetWalletId: target.Id,
+		SourceAmount:   transferCreateDTO.Amount,
+		TargetAmount:   targetAmount,
+		Rate:           rate,
+		Timestamp:      timestamp,
+		Note:           transferCreateDTO.Note,
+		Transactions: []entity.Transaction{
+			{
+				WalletId:  source.Id,
+				Amount:    transferCreateDTO.Amount,
+				Direction: entity.TransactionDirectionOut,
+				Timestamp: timestamp,
+				Note:      transferCreateDTO.Note,
+				Category:  entity.TransferCategory,
+			},
+			{
+				WalletId:  target.Id,
+				Amount:    targetAmount,
+				Direction: entity.TransactionDirectionIn,
+				Timestamp: timestamp,
+				Note:      transferCreateDTO.Note,
+				Category:  entity.TransferCategory,
+			},
+		},
+	})
+}
+
+// defaultWalletId returns the default wallet from user's preferences.
+func (t *transfer) defaultWalletId(ctx context.Context, userId uint64) (uint64, error) {
+	preferences, err := t.preferencesRepository.GetPreferences(ctx, userId)
+	if err != nil {
+		return 0, err
+	}
+	if preferences == nil || preferences.DefaultWalletId == nil {
+		return 0, serviceerror.TransferSourceWalletRequired
+	}
+	return *preferences.DefaultWalletId, nil
+}
+
+// getUserWallet returns the wallet if the user is its owner or editor. Only the source wallet
+// is locked for the balance check: crediting the target can't break its floor, and locking
+// both wallets would deadlock opposite transfers between them.
+func (t *transfer) getUserWallet(ctx context.Context, getWallet walletGetter, id, userId uint64) (*entity.Wallet, error) {
+	wallet, err := getWallet(ctx, id)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return nil, err
+	}
+	if err != nil || wallet == nil {
+		return nil, serviceerror.WalletDoesntExist
+	}
+	if err = access.CheckWalletRole(ctx, t.walletMemberRepository, wallet, userId, entity.WalletRoleEditor); err != nil {
+		return nil, err
+	}
+	return wallet, nil
+}
+
+// convert returns the amount credited to the target wallet and the applied rate.
+// Wallets in the same currency are always transferred 1:1, otherwise exactly one of
+// target amount or rate must be provided and the other one is derived from it.
+// The target amount derived from the rate is rounded half away from zero
+// to the minor units of the target currency.
+func convert(sourceCurrency, targetCurrency string, transferCreateDTO model.TransferCreateDTO) (decimal.Decimal, decimal.Decimal, error) {
+	targetAmount, rate := transferCreateDTO.TargetAmount, transferCreateDTO.Rate
+	if sourceCurrency == targetCurrency {
+		if targetAmount != nil || rate != nil {
+			return decimal.Zero, decimal.Zero, serviceerror.TransferRateNotAllowed
+		}
+		return transferCreateDTO.Amount, decimal.NewFromInt(1), nil
+	}
+	target, ok := currency.Get(targetCurrency)
+	switch {
+	case targetAmount != nil && rate != nil:
+		return decimal.Zero, decimal.Zero, serviceerror.TransferRateAmbiguous
+	case targetAmount != nil:
+		if !targetAmount.IsPositive() {
+			return decimal.Zero, decimal.Zero, serviceerror.TransferAmountError
+		}
+		if ok && !target.FitsMinorUnits(*targetAmount) {
+			return decimal.Zero, decimal.Zero, serviceerror.TransferTargetAmountPrecisionError
+		}
+		return *targetAmount, targetAmount.DivRound(transferCreateDTO.Amount, ratePrecision), nil
+	case rate != nil:
+		if !rate.IsPositive() {
+			return decimal.Zero, decimal.Zero, serviceerror.TransferRateError
+		}
+		converted := transferCreateDTO.Amount.Mul(*rate)
+		if ok {
+			converted = converted.Round(target.MinorUnits)
+		}
+		if !converted.IsPositive() {
+			return decimal.Zero, decimal.Zero, serviceerror.TransferAmountError
+		}
+		return converted, *rate, nil
+	default:
+		return decimal.Zero, decimal.Zero, serviceerror.TransferRateRequired
+	}
+}
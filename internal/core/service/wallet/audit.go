@@ -0,0 +1,115 @@
+package wallet
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/khivuksergey/portmonetka.wallet/internal/adapter/storage/entity"
+	"github.com/khivuksergey/portmonetka.wallet/internal/model"
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+// auditedWalletFields are the wallet fields recorded in the audit log by their JSON names,
+// with values of JSON types, so they compare equal and read the same after storing.
+var auditedWalletFields = []struct {
+	name  string
+	value func(wallet *entity.Wallet) any
+}{
+	{"name", func(wallet *entity.Wallet) any { return wallet.Name }},
+	{"description", func(wallet *entity.Wallet) any { return wallet.Description }},
+	{"currency", func(wallet *entity.Wallet) any { return wallet.Currency }},
+	{"initialAmount", func(wallet *entity.Wallet) any { return wallet.InitialAmount.String() }},
+	{"type", func(wallet *entity.Wallet) any { return wallet.Type }},
+	{"creditLimit", func(wallet *entity.Wallet) any { return decimalValue(wallet.CreditLimit) }},
+	{"statementDay", func(wallet *entity.Wallet) any {
+		if wallet.StatementDay == nil {
+			return nil
+		}
+		return *wallet.StatementDay
+	}},
+	{"interestRate", func(wallet *entity.Wallet) any { return decimalValue(wallet.InterestRate) }},
+	{"allowNegative", func(wallet *entity.Wallet) any { return wallet.AllowNegative }},
+	{"deletedAt", func(wallet *entity.Wallet) any {
+		if !wallet.DeletedAt.Valid {
+			return nil
+		}
+		return wallet.DeletedAt.Time.UTC().Format(time.RFC3339Nano)
+	}},
+}
+
+// walletEventTypes are the types of the domain events emitted for the audited actions.
+var walletEventTypes = map[string]string{
+	entity.WalletAuditActionCreate:  entity.WalletCreatedEvent,
+	entity.WalletAuditActionUpdate:  entity.WalletUpdatedEvent,
+	entity.WalletAuditActionDelete:  entity.WalletDeletedEvent,
+	entity.WalletAuditActionRestore: entity.WalletRestoredEvent,
+}
+
+// recordChange appends the change of the wallet to the audit log and emits its domain event to the outbox.
+// Before is nil for created wallets and the trashed wallet for restored ones, after is nil for deleted ones. The actor is the one
+// of the request or, outside requests, the user.
+func (w *wallet) recordChange(ctx context.Context, userId uint64, action string, before, after *entity.Wallet) error {
+	requestInfo, ok := model.RequestInfoFromContext(ctx)
+	if !ok {
+		requestInfo.ActorId = userId
+	}
+	changed := after
+	if changed == nil {
+		changed = before
+	}
+	changes := walletChanges(before, after)
+
+	err := w.walletAuditRepository.CreateWalletAudit(ctx, &entity.WalletAudit{
+		WalletId:    changed.Id,
+		ActorId:     requestInfo.ActorId,
+		RequestUuid: requestInfo.RequestUuid,
+		Action:      action,
+		Changes:     changes,
+	})
+	if err != nil {
+		return err
+	}
+
+	walletEvent := model.WalletEvent{
+		Wallet:      *changed,
+		ActorId:     requestInfo.ActorId,
+		RequestUuid: requestInfo.RequestUuid,
+	}
+	if action == entity.WalletAuditActionUpdate {
+		walletEvent.Changes = changes
+	}
+	payload, err := json.Marshal(walletEvent)
+	if err != nil {
+		return err
+	}
+	return w.outboxRepository.CreateOutboxEvent(ctx, &entity.OutboxEvent{
+		Type:        walletEventTypes[action],
+		AggregateId: changed.Id,
+		Payload:     payload,
+	})
+}
+
+// walletChanges returns the audited fields whose values differ between the wallets, either may be nil.
+func walletChanges(before, after *entity.Wallet) map[string]entity.FieldChange {
+	changes := map[string]entity.FieldChange{}
+	for _, field := range auditedWalletFields {
+		var change entity.FieldChange
+		if before != nil {
+			change.Before = field.value(before)
+		}
+		if after != nil {
+			change.After = field.value(after)
+		}
+		if change.Before != change.After {
+			changes[field.name] = change
+		}
+	}
+	return changes
+}
+
+func decimalValue(value *decimal.Decimal) any {
+	if value == nil {
+		return nil
+	}
+	return value.String()
+}
@@ -0,0 +1,79 @@
+package wallet
+
+import (
+	"context"
+	"fmt"
+	serviceerror "github.com/khivuksergey/portmonetka.wallet/error"
+	"github.com/khivuksergey/portmonetka.wallet/internal/currency"
+	"github.com/khivuksergey/portmonetka.wallet/internal/model"
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+// exchangeRateDisplayPrecision is the number of decimal places of the rates shown in the summary,
+// balances are converted with the exact cross rates.
+const exchangeRateDisplayPrecision = 8
+
+// GetWalletsSummary converts balances of all user's wallets to the base currency, the preferred one
+// by default, with the rates effective on the date, today by default, and sums them up.
+func (w *wallet) GetWalletsSummary(ctx context.Context, userId uint64, walletSummaryQuery model.WalletSummaryQuery) (*model.WalletsSummary, error) {
+	if walletSummaryQuery.BaseCurrency == "" {
+		preferences, err := w.preferencesRepository.GetPreferences(ctx, userId)
+		if err != nil {
+			return nil, err
+		}
+		if preferences == nil || preferences.BaseCurrency == "" {
+			return nil, serviceerror.BaseCurrencyRequired
+		}
+		walletSummaryQuery.BaseCurrency = preferences.BaseCurrency
+	}
+	baseCurrency, ok := currency.Get(walletSummaryQuery.BaseCurrency)
+	if !ok {
+		return nil, serviceerror.WalletCurrencyError
+	}
+	date := time.Now().UTC().Truncate(24 * time.Hour)
+	if walletSummaryQuery.Date != "" {
+		parsed, err := time.Parse(time.DateOnly, walletSummaryQuery.Date)
+		if err != nil {
+			return nil, err
+		}
+		date = parsed
+	}
+
+	wallets, err := w.walletRepository.GetAllWalletsByUserId(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	exchangeRates, err := w.exchangeRateRepository.GetEffectiveExchangeRates(ctx, date)
+	if err != nil {
+		return nil, err
+	}
+	converter := currency.NewConverter()
+	for _, exchangeRate := range exchangeRates {
+		converter.AddRate(exchangeRate.BaseCurrency, exchangeRate.QuoteCurrency, exchangeRate.Rate)
+	}
+
+	summary := &model.WalletsSummary{
+		BaseCurrency: baseCurrency.Code,
+		Date:         date.Format(time.DateOnly),
+		Total:        decimal.Zero,
+		Wallets:      make([]model.WalletBalance, 0, len(wallets)),
+	}
+	for _, wallet := range wallets {
+		rate, ok := converter.Rate(wallet.Currency, baseCurrency.Code)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s to %s on %s", serviceerror.ExchangeRateNotFound, wallet.Currency, baseCurrency.Code, summary.Date)
+		}
+		converted := wallet.CurrentBalance.Mul(rate).Round(baseCurrency.MinorUnits)
+		summary.Wallets = append(summary.Wallets, model.WalletBalance{
+			WalletId:         wallet.Id,
+			Name:             wallet.Name,
+			Currency:         wallet.Currency,
+			Balance:          wallet.CurrentBalance,
+			Rate:             rate.Round(exchangeRateDisplayPrecision),
+			ConvertedBalance: converted,
+		})
+		summary.Total = summary.Total.Add(converted)
+	}
+	return summary, nil
+}
@@ -0,0 +1,98 @@
+package wallet
+
+import (
+	"context"
+	"fmt"
+	serviceerror "github.com/khivuksergey/portmonetka.wallet/error"
+	"github.com/khivuksergey/portmonetka.wallet/internal/adapter/storage/entity"
+	"github.com/khivuksergey/portmonetka.wallet/internal/currency"
+	"github.com/shopspring/decimal"
+)
+
+type walletGetter func(ctx context.Context, id uint64) (*entity.Wallet, error)
+
+// typeAttributes lists the type-specific attributes a wallet type supports.
+type typeAttributes struct {
+	creditLimit  bool
+	statementDay bool
+	interestRate bool
+}
+
+var walletTypes = map[string]typeAttributes{
+	entity.WalletTypeCash:       {},
+	entity.WalletTypeDebitCard:  {creditLimit: true},
+	entity.WalletTypeCreditCard: {creditLimit: true, statementDay: true},
+	entity.WalletTypeSavings:    {interestRate: true},
+	entity.WalletTypeLoan:       {interestRate: true},
+	entity.WalletTypeInvestment: {},
+	entity.WalletTypeCrypto:     {},
+}
+
+var maxInterestRate = decimal.NewFromInt(100)
+
+// validateWalletType checks that the wallet has only the attributes its type supports,
+// has the ones the type requires, and that their values are valid.
+func validateWalletType(wallet *entity.Wallet) error {
+	attributes, ok := walletTypes[wallet.Type]
+	if !ok {
+		return serviceerror.WalletTypeError
+	}
+	if wallet.CreditLimit != nil && !attributes.creditLimit {
+		return fmt.Errorf("%w: creditLimit of %s", serviceerror.WalletTypeAttributeError, wallet.Type)
+	}
+	if wallet.StatementDay != nil && !attributes.statementDay {
+		return fmt.Errorf("%w: statementDay of %s", serviceerror.WalletTypeAttributeError, wallet.Type)
+	}
+	if wallet.InterestRate != nil && !attributes.interestRate {
+		return fmt.Errorf("%w: interestRate of %s", serviceerror.WalletTypeAttributeError, wallet.Type)
+	}
+	if wallet.Type == entity.WalletTypeCreditCard && (wallet.CreditLimit == nil || wallet.StatementDay == nil) {
+		return serviceerror.WalletCreditCardError
+	}
+	if wallet.CreditLimit != nil {
+		walletCurrency, ok := currency.Get(wallet.Currency)
+		if wallet.CreditLimit.IsNegative() || ok && !walletCurrency.FitsMinorUnits(*wallet.CreditLimit) {
+			return serviceerror.WalletCreditLimitError
+		}
+	}
+	if wallet.StatementDay != nil && (*wallet.StatementDay < 1 || *wallet.StatementDay > 31) {
+		return serviceerror.WalletStatementDayError
+	}
+	if wallet.InterestRate != nil && (wallet.InterestRate.IsNegative() || wallet.InterestRate.GreaterThan(maxInterestRate)) {
+		return serviceerror.WalletInterestRateError
+	}
+	return nil
+}
+
+// dropUnsupportedAttributes clears the attributes the new type of the wallet doesn't support.
+func dropUnsupportedAttributes(wallet *entity.Wallet) {
+	attributes := walletTypes[wallet.Type]
+	if !attributes.creditLimit {
+		wallet.CreditLimit = nil
+	}
+	if !attributes.statementDay {
+		wallet.StatementDay = nil
+	}
+	if !attributes.interestRate {
+		wallet.InterestRate = nil
+	}
+}
+
+// checkBalanceFloor rejects the update of the wallet if its balance would be below the floor
+// permitted by the new settings, e.g. because the update lowers the initial amount or the credit limit
+// or disallows a negative balance. Updates which don't bring the balance closer to the floor
+// are allowed, so a wallet which is already below it can still be renamed.
+func checkBalanceFloor(before, after *entity.Wallet) error {
+	floor, ok := after.BalanceFloor()
+	if !ok {
+		return nil
+	}
+	balance := before.CurrentBalance.Sub(before.InitialAmount).Add(after.InitialAmount)
+	if !balance.LessThan(floor) {
+		return nil
+	}
+	if previousFloor, ok := before.BalanceFloor(); ok && !balance.Sub(floor).LessThan(before.CurrentBalance.Sub(previousFloor)) {
+		return nil
+	}
+	return serviceerror.NewInsufficientFundsError(after.Id, balance, floor)
+}
@@ -1,89 +1,332 @@
 package wallet
 
 import (
+	"context"
+	"errors"
 	serviceerror "github.com/khivuksergey/portmonetka.wallet/error"
 	"github.com/khivuksergey/portmonetka.wallet/internal/adapter/storage/entity"
 	"github.com/khivuksergey/portmonetka.wallet/internal/core/port/repository"
 	"github.com/khivuksergey/portmonetka.wallet/internal/core/port/service"
+	"github.com/khivuksergey/portmonetka.wallet/internal/core/service/access"
+	"github.com/khivuksergey/portmonetka.wallet/internal/currency"
 	"github.com/khivuksergey/portmonetka.wallet/internal/model"
-	"strings"
+	"slices"
+	"time"
 )
 
 type wallet struct {
-	walletRepository repository.WalletRepository
+	repositoryManager      *repository.Manager
+	walletRepository       repository.WalletRepository
+	exchangeRateRepository repository.ExchangeRateRepository
+	preferencesRepository  repository.PreferencesRepository
+	walletMemberRepository repository.WalletMemberRepository
+	walletAuditRepository  repository.WalletAuditRepository
+	outboxRepository       repository.OutboxRepository
 }
 
 func NewWalletService(repositoryManager *repository.Manager) service.WalletService {
-	return &wallet{walletRepository: repositoryManager.Wallet}
+	return newWallet(repositoryManager)
 }
 
-func (w *wallet) GetWalletsByUserId(userId uint64) ([]entity.Wallet, error) {
-	return w.walletRepository.GetWalletsByUserId(userId)
+func newWallet(repositoryManager *repository.Manager) *wallet {
+	return &wallet{
+		repositoryManager:      repositoryManager,
+		walletRepository:       repositoryManager.Wallet,
+		exchangeRateRepository: repositoryManager.ExchangeRate,
+		preferencesRepository:  repositoryManager.Preferences,
+		walletMemberRepository: repositoryManager.WalletMember,
+		walletAuditRepository:  repositoryManager.WalletAudit,
+		outboxRepository:       repositoryManager.Outbox,
+	}
+}
+
+// GetWalletsByUserId returns a page of wallets owned by or shared with the user and the cursor
+// of the next page, which is empty when there are no more wallets.
+func (w *wallet) GetWalletsByUserId(ctx context.Context, userId uint64, walletListQuery model.WalletListQuery) ([]entity.Wallet, string, error) {
+	if walletListQuery.Sort == "" {
+		walletListQuery.Sort = model.DefaultWalletSort
+	}
+	sortField, _ := walletListQuery.SortField()
+
+	var after *model.WalletCursor
+	if walletListQuery.Cursor != "" {
+		cursor, err := model.DecodeWalletCursor(walletListQuery.Cursor)
+		if err != nil || cursor.Sort != walletListQuery.Sort {
+			return nil, "", serviceerror.InvalidWalletCursor
+		}
+		after = cursor
+	}
+
+	limit := walletListQuery.Limit
+	if limit <= 0 {
+		limit = model.DefaultWalletListLimit
+	}
+	walletListQuery.Limit = limit + 1
+
+	wallets, err := w.walletRepository.GetWalletsByUserId(ctx, userId, walletListQuery, after)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(wallets) <= limit {
+		return wallets, "", nil
+	}
+
+	wallets = wallets[:limit]
+	last := wallets[limit-1]
+	nextCursor := model.WalletCursor{
+		Sort:  walletListQuery.Sort,
+		Value: walletSortValue(last, sortField),
+		Id:    last.Id,
+	}
+	return wallets, nextCursor.Encode(), nil
+}
+
+// GetWalletById returns the wallet if the user has any role in it.
+func (w *wallet) GetWalletById(ctx context.Context, userId, id uint64) (*entity.Wallet, error) {
+	return w.getUserWallet(ctx, w.walletRepository.GetWalletById, userId, id, entity.WalletRoleViewer)
 }
 
-func (w *wallet) CreateWallet(walletCreateDTO model.WalletCreateDTO) (*entity.Wallet, error) {
-	if w.walletRepository.ExistsWithName(walletCreateDTO.UserId, walletCreateDTO.Name) {
-		return nil, serviceerror.WalletAlreadyExists
+// GetWalletHistory returns the audit log of the wallet if the user has any role in it.
+func (w *wallet) GetWalletHistory(ctx context.Context, userId, id uint64) ([]entity.WalletAudit, error) {
+	if _, err := w.getUserWallet(ctx, w.walletRepository.GetWalletById, userId, id, entity.WalletRoleViewer); err != nil {
+		return nil, err
 	}
-	return w.walletRepository.CreateWallet(&entity.Wallet{
+	return w.walletAuditRepository.GetWalletAuditsByWalletId(ctx, id)
+}
+
+// CreateWallet creates the wallet in a unit of work with its audit record and domain event,
+// the uniqueness of its name is left to the repository, so concurrent requests can't create
+// two wallets with the same name.
+func (w *wallet) CreateWallet(ctx context.Context, walletCreateDTO model.WalletCreateDTO) (created *entity.Wallet, err error) {
+	err = w.repositoryManager.WithinTransaction(ctx, func(ctx context.Context, repositories *repository.Manager) error {
+		created, err = newWallet(repositories).createWallet(ctx, walletCreateDTO)
+		return err
+	})
+	return created, err
+}
+
+func (w *wallet) createWallet(ctx context.Context, walletCreateDTO model.WalletCreateDTO) (*entity.Wallet, error) {
+	walletCurrency, ok := currency.Get(walletCreateDTO.Currency)
+	if !ok {
+		return nil, serviceerror.WalletCurrencyError
+	}
+	if !walletCurrency.FitsMinorUnits(walletCreateDTO.InitialAmount) {
+		return nil, serviceerror.WalletAmountPrecisionError
+	}
+	walletToCreate := &entity.Wallet{
 		UserId:        walletCreateDTO.UserId,
 		Name:          walletCreateDTO.Name,
 		Description:   walletCreateDTO.Description,
-		Currency:      strings.ToUpper(walletCreateDTO.Currency),
+		Currency:      walletCurrency.Code,
 		InitialAmount: walletCreateDTO.InitialAmount,
+		Type:          walletCreateDTO.Type,
+		CreditLimit:   walletCreateDTO.CreditLimit,
+		StatementDay:  walletCreateDTO.StatementDay,
+		InterestRate:  walletCreateDTO.InterestRate,
+		AllowNegative: walletCreateDTO.AllowNegative,
+	}
+	if walletToCreate.Type == "" {
+		walletToCreate.Type = entity.WalletTypeCash
+	}
+	if err := validateWalletType(walletToCreate); err != nil {
+		return nil, err
+	}
+	created, err := w.walletRepository.CreateWallet(ctx, walletToCreate)
+	if err != nil {
+		return nil, err
+	}
+	if err = w.recordChange(ctx, walletCreateDTO.UserId, entity.WalletAuditActionCreate, nil, created); err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// UpdateWallet updates the wallet if the user is its owner or editor,
+// in a unit of work with the check of its version, the audit record and the domain event.
+// Updates which change none of the audited fields are neither written, recorded nor emitted,
+// so they keep the version of the wallet.
+func (w *wallet) UpdateWallet(ctx context.Context, walletUpdateDTO model.WalletUpdateDTO) (updated *entity.Wallet, err error) {
+	err = w.repositoryManager.WithinTransaction(ctx, func(ctx context.Context, repositories *repository.Manager) error {
+		updated, err = newWallet(repositories).updateWallet(ctx, walletUpdateDTO)
+		return err
 	})
+	return updated, err
 }
 
-func (w *wallet) UpdateWallet(walletUpdateDTO model.WalletUpdateDTO) (*entity.Wallet, error) {
-	walletToUpdate, err := w.walletRepository.GetWalletById(walletUpdateDTO.Id)
+func (w *wallet) updateWallet(ctx context.Context, walletUpdateDTO model.WalletUpdateDTO) (*entity.Wallet, error) {
+	walletToUpdate, err := w.getUserWallet(ctx, w.walletRepository.GetWalletForUpdate, walletUpdateDTO.UserId, walletUpdateDTO.Id, entity.WalletRoleEditor)
 	if err != nil {
-		return nil, serviceerror.WalletDoesntExist
+		return nil, err
+	}
+	if len(walletUpdateDTO.Versions) > 0 && !slices.Contains(walletUpdateDTO.Versions, walletToUpdate.Version) {
+		return nil, serviceerror.WalletVersionMismatch
 	}
+	before := *walletToUpdate
 	err = w.validateUpdateWalletAttributes(walletToUpdate, walletUpdateDTO)
 	if err != nil {
 		return nil, err
 	}
-	return w.walletRepository.UpdateWallet(walletToUpdate)
+	if err = checkBalanceFloor(&before, walletToUpdate); err != nil {
+		return nil, err
+	}
+	if len(walletChanges(&before, walletToUpdate)) == 0 {
+		return walletToUpdate, nil
+	}
+	updated, err := w.walletRepository.UpdateWallet(ctx, walletToUpdate)
+	if err != nil {
+		return nil, err
+	}
+	if err = w.recordChange(ctx, walletUpdateDTO.UserId, entity.WalletAuditActionUpdate, &before, updated); err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// DeleteWallet moves the wallet to the trash if the user is its owner,
+// in a unit of work with the audit record and the domain event.
+func (w *wallet) DeleteWallet(ctx context.Context, walletDeleteDTO model.WalletDeleteDTO) error {
+	return w.repositoryManager.WithinTransaction(ctx, func(ctx context.Context, repositories *repository.Manager) error {
+		return newWallet(repositories).deleteWallet(ctx, walletDeleteDTO)
+	})
 }
 
-func (w *wallet) DeleteWallet(walletDeleteDTO model.WalletDeleteDTO) error {
-	if !w.walletRepository.WalletBelongsToUser(walletDeleteDTO.Id, walletDeleteDTO.UserId) {
-		return serviceerror.WalletDoesntBelongToUser
+func (w *wallet) deleteWallet(ctx context.Context, walletDeleteDTO model.WalletDeleteDTO) error {
+	walletToDelete, err := w.getUserWallet(ctx, w.walletRepository.GetWalletById, walletDeleteDTO.UserId, walletDeleteDTO.Id, entity.WalletRoleOwner)
+	if err != nil {
+		return err
 	}
-	return w.walletRepository.DeleteWallet(walletDeleteDTO.Id)
+	if len(walletDeleteDTO.Versions) > 0 {
+		// the wallet isn't locked, so the version it was read with is checked again by the delete
+		if !slices.Contains(walletDeleteDTO.Versions, walletToDelete.Version) {
+			return serviceerror.WalletVersionMismatch
+		}
+		err = w.walletRepository.DeleteWalletWithVersion(ctx, walletDeleteDTO.Id, walletToDelete.Version)
+	} else {
+		err = w.walletRepository.DeleteWallet(ctx, walletDeleteDTO.Id)
+	}
+	if err != nil {
+		return err
+	}
+	return w.recordChange(ctx, walletDeleteDTO.UserId, entity.WalletAuditActionDelete, walletToDelete, nil)
 }
 
-// TODO move attributes validation to validator
-func (w *wallet) validateUpdateWalletAttributes(wallet *entity.Wallet, walletUpdateDTO model.WalletUpdateDTO) error {
-	if walletUpdateDTO.Name == nil &&
-		walletUpdateDTO.Description == nil &&
-		walletUpdateDTO.Currency == nil &&
-		walletUpdateDTO.InitialAmount == nil {
-		return serviceerror.AtLeastOneFieldIsRequired
+func (w *wallet) GetDeletedWalletsByUserId(ctx context.Context, userId uint64) ([]entity.Wallet, error) {
+	return w.walletRepository.GetDeletedWalletsByUserId(ctx, userId)
+}
+
+// RestoreWallet moves the wallet out of the trash unless the user has already created another wallet
+// with the same name, in a unit of work with the audit record and the domain event.
+func (w *wallet) RestoreWallet(ctx context.Context, walletTrashDTO model.WalletTrashDTO) (restored *entity.Wallet, err error) {
+	err = w.repositoryManager.WithinTransaction(ctx, func(ctx context.Context, repositories *repository.Manager) error {
+		restored, err = newWallet(repositories).restoreWallet(ctx, walletTrashDTO)
+		return err
+	})
+	return restored, err
+}
+
+func (w *wallet) restoreWallet(ctx context.Context, walletTrashDTO model.WalletTrashDTO) (*entity.Wallet, error) {
+	deletedWallet, err := w.getDeletedUserWallet(ctx, walletTrashDTO)
+	if err != nil {
+		return nil, err
+	}
+	restored, err := w.walletRepository.RestoreWallet(ctx, deletedWallet.Id)
+	if err != nil {
+		return nil, err
+	}
+	if err = w.recordChange(ctx, walletTrashDTO.UserId, entity.WalletAuditActionRestore, deletedWallet, restored); err != nil {
+		return nil, err
+	}
+	return restored, nil
+}
+
+func (w *wallet) PurgeWallet(ctx context.Context, walletTrashDTO model.WalletTrashDTO) error {
+	deletedWallet, err := w.getDeletedUserWallet(ctx, walletTrashDTO)
+	if err != nil {
+		return err
+	}
+	return w.walletRepository.PurgeWallet(ctx, deletedWallet.Id)
+}
+
+func (w *wallet) PurgeExpiredWallets(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	return w.walletRepository.PurgeWalletsDeletedBefore(ctx, deletedBefore)
+}
+
+func (w *wallet) getDeletedUserWallet(ctx context.Context, walletTrashDTO model.WalletTrashDTO) (*entity.Wallet, error) {
+	deletedWallet, err := w.walletRepository.GetDeletedWalletById(ctx, walletTrashDTO.Id)
+	if err != nil || deletedWallet == nil {
+		return nil, serviceerror.DeletedWalletDoesntExist
+	}
+	// the trash is listed by the creating user only, so members can't restore or purge shared wallets
+	if deletedWallet.UserId != walletTrashDTO.UserId {
+		return nil, serviceerror.WalletDoesntBelongToUser
 	}
+	return deletedWallet, nil
+}
+
+// getUserWallet returns the wallet if the user's role in it includes the required one.
+// Updates load the wallet with GetWalletForUpdate, as they may raise its balance floor.
+func (w *wallet) getUserWallet(ctx context.Context, getWallet walletGetter, userId, id uint64, requiredRole string) (*entity.Wallet, error) {
+	wallet, err := getWallet(ctx, id)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return nil, err
+	}
+	if err != nil || wallet == nil {
+		return nil, serviceerror.WalletDoesntExist
+	}
+	if err = access.CheckWalletRole(ctx, w.walletMemberRepository, wallet, userId, requiredRole); err != nil {
+		return nil, err
+	}
+	return wallet, nil
+}
+
+func (w *wallet) validateUpdateWalletAttributes(wallet *entity.Wallet, walletUpdateDTO model.WalletUpdateDTO) error {
 	if walletUpdateDTO.Name != nil {
-		if len(*walletUpdateDTO.Name) < 3 || len(*walletUpdateDTO.Name) > 128 {
-			return serviceerror.WalletNameLengthError
-		}
-		if w.walletRepository.ExistsWithName(walletUpdateDTO.UserId, *walletUpdateDTO.Name) {
-			return serviceerror.WalletAlreadyExists
-		}
 		wallet.Name = *walletUpdateDTO.Name
 	}
 	if walletUpdateDTO.Description != nil {
-		if len(*walletUpdateDTO.Description) > 256 {
-			return serviceerror.WalletDescriptionLengthError
-		}
 		wallet.Description = *walletUpdateDTO.Description
 	}
 	if walletUpdateDTO.Currency != nil {
-		if len(*walletUpdateDTO.Currency) != 3 {
+		walletCurrency, ok := currency.Get(*walletUpdateDTO.Currency)
+		if !ok {
 			return serviceerror.WalletCurrencyError
 		}
-		wallet.Currency = *walletUpdateDTO.Currency
+		wallet.Currency = walletCurrency.Code
 	}
 	if walletUpdateDTO.InitialAmount != nil {
 		wallet.InitialAmount = *walletUpdateDTO.InitialAmount
 	}
-	return nil
+	if walletCurrency, ok := currency.Get(wallet.Currency); ok && !walletCurrency.FitsMinorUnits(wallet.InitialAmount) {
+		return serviceerror.WalletAmountPrecisionError
+	}
+	if walletUpdateDTO.Type != nil && *walletUpdateDTO.Type != wallet.Type {
+		wallet.Type = *walletUpdateDTO.Type
+		dropUnsupportedAttributes(wallet)
+	}
+	if walletUpdateDTO.CreditLimit != nil {
+		wallet.CreditLimit = walletUpdateDTO.CreditLimit
+	}
+	if walletUpdateDTO.StatementDay != nil {
+		wallet.StatementDay = walletUpdateDTO.StatementDay
+	}
+	if walletUpdateDTO.InterestRate != nil {
+		wallet.InterestRate = walletUpdateDTO.InterestRate
+	}
+	if walletUpdateDTO.AllowNegative != nil {
+		wallet.AllowNegative = *walletUpdateDTO.AllowNegative
+	}
+	return validateWalletType(wallet)
+}
+
+func walletSortValue(wallet entity.Wallet, sortField string) string {
+	switch sortField {
+	case model.WalletSortName:
+		return wallet.Name
+	case model.WalletSortCreatedAt:
+		return wallet.CreatedAt.Format(time.RFC3339Nano)
+	case model.WalletSortBalance:
+		return wallet.CurrentBalance.String()
+	default:
+		return wallet.UpdatedAt.Format(time.RFC3339Nano)
+	}
 }
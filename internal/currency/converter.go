@@ -0,0 +1,71 @@
+package currency
+
+import (
+	"github.com/shopspring/decimal"
+	"sort"
+	"strings"
+)
+
+// crossRatePrecision is the number of decimal places inverse rates are computed with.
+const crossRatePrecision = 16
+
+// Converter finds the rate between two currencies from a set of quoted pairs, directly,
+// through the inverse pair or through intermediate currencies, e.g. USD to RUB via EUR.
+type Converter struct {
+	rates map[string]map[string]decimal.Decimal
+}
+
+func NewConverter() *Converter {
+	return &Converter{rates: map[string]map[string]decimal.Decimal{}}
+}
+
+// AddRate adds the price of one unit of the base currency in the quote currency.
+func (c *Converter) AddRate(base, quote string, rate decimal.Decimal) {
+	if !rate.IsPositive() {
+		return
+	}
+	base, quote = strings.ToUpper(base), strings.ToUpper(quote)
+	c.set(base, quote, rate)
+	if _, ok := c.rates[quote][base]; !ok {
+		c.set(quote, base, decimal.NewFromInt(1).DivRound(rate, crossRatePrecision))
+	}
+}
+
+// Rate returns the price of one unit of the from currency in the to currency,
+// using the shortest chain of known rates.
+func (c *Converter) Rate(from, to string) (decimal.Decimal, bool) {
+	from, to = strings.ToUpper(from), strings.ToUpper(to)
+	if from == to {
+		return decimal.NewFromInt(1), true
+	}
+	visited := map[string]decimal.Decimal{from: decimal.NewFromInt(1)}
+	queue := []string{from}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		// neighbours are visited in order, so the same chain is chosen every time
+		neighbours := make([]string, 0, len(c.rates[current]))
+		for next := range c.rates[current] {
+			neighbours = append(neighbours, next)
+		}
+		sort.Strings(neighbours)
+		for _, next := range neighbours {
+			if _, ok := visited[next]; ok {
+				continue
+			}
+			visited[next] = visited[current].Mul(c.rates[current][next])
+			if next == to {
+				return visited[next], true
+			}
+			queue = append(queue, next)
+		}
+	}
+	return decimal.Zero, false
+}
+
+func (c *Converter) set(base, quote string, rate decimal.Decimal) {
+	if c.rates[base] == nil {
+		c.rates[base] = map[string]decimal.Decimal{}
+	}
+	c.rates[base][quote] = rate
+}
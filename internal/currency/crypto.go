@@ -0,0 +1,11 @@
+package currency
+
+// cryptocurrencies are the currencies of crypto wallets. They have no ISO 4217 numeric codes,
+// and their minor units are the smallest units the networks account in, e.g. satoshi for BTC.
+var cryptocurrencies = []Currency{
+	{"BTC", "", "Bitcoin", 8},
+	{"ETH", "", "Ether", 18},
+	{"LTC", "", "Litecoin", 8},
+	{"SOL", "", "Solana", 9},
+	{"XRP", "", "XRP", 6},
+}
@@ -0,0 +1,52 @@
+package currency
+
+import (
+	"github.com/shopspring/decimal"
+	"sort"
+	"strings"
+)
+
+// Currency is an ISO 4217 currency or a cryptocurrency with the number of digits after the decimal separator.
+// Cryptocurrencies have no numeric code.
+type Currency struct {
+	Code        string `json:"code"`
+	NumericCode string `json:"numericCode,omitempty"`
+	Name        string `json:"name"`
+	MinorUnits  int32  `json:"minorUnits"`
+}
+
+var byCode = func() map[string]Currency {
+	m := make(map[string]Currency, len(currencies)+len(cryptocurrencies))
+	for _, c := range currencies {
+		m[c.Code] = c
+	}
+	for _, c := range cryptocurrencies {
+		m[c.Code] = c
+	}
+	return m
+}()
+
+// Get returns the currency by its alphabetic code, case-insensitively.
+func Get(code string) (Currency, bool) {
+	c, ok := byCode[strings.ToUpper(code)]
+	return c, ok
+}
+
+// IsValid reports whether the code is a known ISO 4217 alphabetic code or cryptocurrency code, case-insensitively.
+func IsValid(code string) bool {
+	_, ok := Get(code)
+	return ok
+}
+
+// All returns all known currencies ordered by code.
+func All() []Currency {
+	all := make([]Currency, 0, len(currencies)+len(cryptocurrencies))
+	all = append(append(all, currencies...), cryptocurrencies...)
+	sort.Slice(all, func(i, j int) bool { return all[i].Code < all[j].Code })
+	return all
+}
+
+// FitsMinorUnits reports whether the amount has no more significant decimal places than the currency allows.
+func (c Currency) FitsMinorUnits(amount decimal.Decimal) bool {
+	return amount.Equal(amount.Truncate(c.MinorUnits))
+}
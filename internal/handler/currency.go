@@ -0,0 +1,34 @@
+package handler
+
+import (
+	"github.com/khivuksergey/portmonetka.common"
+	"github.com/khivuksergey/portmonetka.wallet/internal/currency"
+	"github.com/khivuksergey/portmonetka.wallet/internal/model"
+	"github.com/labstack/echo/v4"
+	"net/http"
+)
+
+type CurrencyHandler struct{}
+
+func NewCurrencyHandler() *CurrencyHandler {
+	return &CurrencyHandler{}
+}
+
+// GetCurrencies retrieves supported currencies.
+//
+// @Tags Currency
+// @Summary Get supported currencies
+// @Description Gets ISO 4217 currencies and cryptocurrencies wallets can be created in, with the number of minor units
+// @ID get-currencies
+// @Produce json
+// @Success 200 {object} model.Response "Currencies retrieved"
+// @Router /currencies [get]
+func (h CurrencyHandler) GetCurrencies(c echo.Context) error {
+	requestUuid := c.Get(common.RequestUuidKey).(string)
+
+	return c.JSON(http.StatusOK, model.Response{
+		Message:     "Currencies retrieved",
+		Data:        currency.All(),
+		RequestUuid: requestUuid,
+	})
+}
@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	serviceerror "github.com/khivuksergey/portmonetka.wallet/error"
+	"github.com/khivuksergey/portmonetka.wallet/internal/adapter/storage/entity"
+	"github.com/labstack/echo/v4"
+	"strconv"
+	"strings"
+)
+
+const (
+	headerETag        = "ETag"
+	headerIfMatch     = "If-Match"
+	headerIfNoneMatch = "If-None-Match"
+)
+
+// walletETag identifies the wallet representation as "<version>-<balance hash>".
+// Current balance is a part of it, because posting a transaction changes
+// the response without touching the wallet row and its version.
+func walletETag(wallet *entity.Wallet) string {
+	hash := sha1.Sum([]byte(fmt.Sprintf("%d|%s", wallet.Id, wallet.CurrentBalance.String())))
+	return fmt.Sprintf(`"%d-%s"`, wallet.Version, hex.EncodeToString(hash[:4]))
+}
+
+// etagMatches reports whether the If-None-Match header value matches the etag
+// using weak comparison as required for conditional GET.
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// versionsFromIfMatch returns the wallet versions listed by the If-Match header. Missing header or "*"
+// don't restrict the version and result in no versions. Otherwise one of the listed entity tags must
+// match the current version by strong comparison, which the service checks with the update itself,
+// so weak tags never match and a header of weak tags only fails the precondition.
+func versionsFromIfMatch(c echo.Context) ([]uint64, error) {
+	header := strings.TrimSpace(c.Request().Header.Get(headerIfMatch))
+	if header == "" || header == "*" {
+		return nil, nil
+	}
+	var versions []uint64
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if strings.HasPrefix(candidate, "W/") {
+			continue
+		}
+		version, ok := versionFromETag(candidate)
+		if !ok {
+			return nil, serviceerror.InvalidEntityTag
+		}
+		versions = append(versions, version)
+	}
+	if len(versions) == 0 {
+		return nil, serviceerror.WalletVersionMismatch
+	}
+	return versions, nil
+}
+
+// versionFromETag parses the version from a quoted wallet entity tag made by walletETag.
+func versionFromETag(etag string) (uint64, bool) {
+	if len(etag) < 2 || !strings.HasPrefix(etag, `"`) || !strings.HasSuffix(etag, `"`) {
+		return 0, false
+	}
+	versionStr, _, _ := strings.Cut(etag[1:len(etag)-1], "-")
+	version, err := strconv.ParseUint(versionStr, 10, 64)
+	return version, err == nil
+}
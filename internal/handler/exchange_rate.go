@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"github.com/khivuksergey/portmonetka.common"
+	serviceerror "github.com/khivuksergey/portmonetka.wallet/error"
+	"github.com/khivuksergey/portmonetka.wallet/internal/core/port/service"
+	"github.com/khivuksergey/portmonetka.wallet/internal/model"
+	"github.com/khivuksergey/webserver/logger"
+	"github.com/labstack/echo/v4"
+	"mime"
+	"net/http"
+)
+
+type ExchangeRateHandler struct {
+	exchangeRateService service.ExchangeRateService
+	logger              logger.Logger
+}
+
+func NewExchangeRateHandler(services *service.Manager, logger logger.Logger) *ExchangeRateHandler {
+	return &ExchangeRateHandler{
+		exchangeRateService: services.ExchangeRate,
+		logger:              logger,
+	}
+}
+
+// ImportExchangeRates loads exchange rates from the request body.
+//
+// @Tags Exchange rate
+// @Summary Import exchange rates
+// @Description Imports rates from an ECB eurofxref XML file, an ECB CSV file or a CSV file with the "date,base,quote,rate" header. Requires the admin role
+// @ID import-exchange-rates
+// @Accept xml
+// @Accept text/csv
+// @Produce json
+// @Success 200 {object} model.Response "Exchange rates imported"
+// @Failure 400 {object} model.Problem "Bad request"
+// @Failure 401 {object} model.Response "Unauthorized"
+// @Failure 403 {object} model.Response "Forbidden"
+// @Failure 422 {object} model.Problem "Unprocessable entity"
+// @Failure 500 {object} model.Problem "Internal server error"
+// @Router /admin/exchange-rates [post]
+func (e ExchangeRateHandler) ImportExchangeRates(c echo.Context) error {
+	requestUuid := c.Get(common.RequestUuidKey).(string)
+
+	format := exchangeRateFormat(c.Request().Header.Get(echo.HeaderContentType))
+	if format == "" {
+		return respondInvalidInput(c, serviceerror.ExchangeRateFormatError)
+	}
+
+	result, err := e.exchangeRateService.ImportExchangeRates(c.Request().Context(), format, c.Request().Body)
+	if err != nil {
+		return respondProblem(c, e.logger, serviceerror.CannotImportExchangeRates, err)
+	}
+
+	e.logger.Info(logger.LogMessage{
+		Action:      "ImportExchangeRates",
+		Message:     "Exchange rates imported",
+		Data:        result,
+		RequestUuid: requestUuid,
+	})
+
+	return c.JSON(http.StatusOK, model.Response{
+		Message:     "Exchange rates imported",
+		Data:        result,
+		RequestUuid: requestUuid,
+	})
+}
+
+func exchangeRateFormat(contentType string) string {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch mediaType {
+	case echo.MIMEApplicationXML, echo.MIMETextXML:
+		return model.ExchangeRateFormatXML
+	case "text/csv":
+		return model.ExchangeRateFormatCSV
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"github.com/go-playground/validator/v10"
+	"github.com/khivuksergey/portmonetka.common"
+	serviceerror "github.com/khivuksergey/portmonetka.wallet/error"
+	"github.com/khivuksergey/portmonetka.wallet/internal/core/port/service"
+	"github.com/khivuksergey/portmonetka.wallet/internal/model"
+	"github.com/khivuksergey/webserver/logger"
+	"github.com/labstack/echo/v4"
+	"net/http"
+)
+
+type PreferencesHandler struct {
+	preferencesService service.PreferencesService
+	logger             logger.Logger
+	validate           *validator.Validate
+}
+
+func NewPreferencesHandler(services *service.Manager, logger logger.Logger) *PreferencesHandler {
+	return &PreferencesHandler{
+		preferencesService: services.Preferences,
+		logger:             logger,
+		validate:           model.GetWalletValidator(),
+	}
+}
+
+// GetPreferences retrieves user's preferences.
+//
+// @Tags Preferences
+// @Summary Get user's preferences
+// @Description Gets user's preferences, the defaults if they were never updated
+// @ID get-preferences
+// @Accept json
+// @Produce json
+// @Param userId path uint64 true "Authorized user ID"
+// @Success 200 {object} model.Response "Preferences retrieved"
+// @Failure 500 {object} model.Problem "Internal server error"
+// @Router /users/{userId}/preferences [get]
+func (p PreferencesHandler) GetPreferences(c echo.Context) error {
+	requestUuid := c.Get(common.RequestUuidKey).(string)
+	userId := c.Get("userId").(uint64)
+
+	preferences, err := p.preferencesService.GetPreferences(c.Request().Context(), userId)
+	if err != nil {
+		return respondProblem(c, p.logger, serviceerror.CannotGetPreferences, err)
+	}
+
+	p.logger.Info(logger.LogMessage{
+		Action:      "GetPreferences",
+		Message:     "Preferences retrieved",
+		UserId:      &userId,
+		RequestUuid: requestUuid,
+	})
+
+	return c.JSON(http.StatusOK, model.Response{
+		Message:     "Preferences retrieved",
+		Data:        preferences,
+		RequestUuid: requestUuid,
+	})
+}
+
+// UpdatePreferences replaces user's preferences.
+//
+// @Tags Preferences
+// @Summary Update user's preferences
+// @Description Replaces user's preferences: base currency and default wallet used by summary and reporting endpoints, locale and the first day of month for reporting. Omitted fields are reset to defaults
+// @ID update-preferences
+// @Accept json
+// @Produce json
+// @Param userId path uint64 true "Authorized user ID"
+// @Param preferences body model.PreferencesUpdateDTO true "User's preferences"
+// @Success 200 {object} model.Response "Preferences updated"
+// @Failure 400 {object} model.Problem "Bad request"
+// @Failure 403 {object} model.Problem "The role in the default wallet doesn't permit the operation"
+// @Failure 404 {object} model.Problem "Default wallet not found"
+// @Failure 500 {object} model.Problem "Internal server error"
+// @Router /users/{userId}/preferences [put]
+func (p PreferencesHandler) UpdatePreferences(c echo.Context) error {
+	requestUuid := c.Get(common.RequestUuidKey).(string)
+	userId := c.Get("userId").(uint64)
+	preferencesUpdateDTO := &model.PreferencesUpdateDTO{}
+
+	err := bindDtoValidate[model.PreferencesUpdateDTO](c, p.validate, preferencesUpdateDTO)
+	if err != nil {
+		return respondInvalidInput(c, err)
+	}
+	preferencesUpdateDTO.UserId = userId
+
+	preferences, err := p.preferencesService.UpdatePreferences(c.Request().Context(), *preferencesUpdateDTO)
+	if err != nil {
+		return respondProblem(c, p.logger, serviceerror.CannotUpdatePreferences, err)
+	}
+
+	p.logger.Info(logger.LogMessage{
+		Action:      "UpdatePreferences",
+		Message:     "Preferences updated",
+		UserId:      &userId,
+		RequestUuid: requestUuid,
+	})
+
+	return c.JSON(http.StatusOK, model.Response{
+		Message:     "Preferences updated",
+		Data:        preferences,
+		RequestUuid: requestUuid,
+	})
+}
@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"github.com/khivuksergey/portmonetka.common"
+	serviceerror "github.com/khivuksergey/portmonetka.wallet/error"
+	"github.com/khivuksergey/portmonetka.wallet/internal/model"
+	"github.com/khivuksergey/webserver/logger"
+	"github.com/labstack/echo/v4"
+	"net/http"
+)
+
+const (
+	mimeApplicationProblemJSON = "application/problem+json"
+	problemTypeBlank           = "about:blank"
+	headerAcceptLanguage       = "Accept-Language"
+
+	invalidInputCode  = "invalid_input"
+	internalErrorCode = "internal_error"
+)
+
+var errInternal = errors.New("internal server error")
+
+// respondProblem responds with an application/problem+json body. Service errors are reported
+// with their own status, code and field. Any other error is unexpected, so it is logged and
+// reported as an internal server error without its text, which may expose storage details.
+// The message describes the failed operation and prefixes the detail.
+func respondProblem(c echo.Context, log logger.Logger, message string, err error) error {
+	var serviceError *serviceerror.ServiceError
+	if !errors.As(err, &serviceError) {
+		requestUuid := c.Get(common.RequestUuidKey).(string)
+		userId, _ := c.Get("userId").(uint64)
+		log.Error(logger.LogMessage{
+			Action:      c.Request().Method + " " + c.Path(),
+			Message:     message,
+			UserId:      &userId,
+			Data:        err.Error(),
+			RequestUuid: requestUuid,
+		})
+		return writeProblem(c, http.StatusInternalServerError, internalErrorCode, "", message, errInternal)
+	}
+	return writeProblem(c, serviceError.Status, serviceError.Code, serviceError.Field, message, err)
+}
+
+// respondInvalidInput responds to a request which can't be bound or fails validation
+// with a 400 problem, naming the first invalid field if it is known and listing
+// the translated messages of all invalid fields.
+func respondInvalidInput(c echo.Context, err error) error {
+	var serviceError *serviceerror.ServiceError
+	if errors.As(err, &serviceError) {
+		return writeProblem(c, serviceError.Status, serviceError.Code, serviceError.Field, serviceerror.InvalidInputData, err)
+	}
+	var invalidFieldsError *model.InvalidFieldsError
+	if !errors.As(err, &invalidFieldsError) || len(invalidFieldsError.Fields) == 0 {
+		return writeProblem(c, http.StatusBadRequest, invalidInputCode, "", serviceerror.InvalidInputData, err)
+	}
+	problem := newProblem(c, http.StatusBadRequest, invalidInputCode, invalidFieldsError.Fields[0].Field, serviceerror.InvalidInputData, err)
+	problem.Errors = invalidFieldsError.Fields
+	return sendProblem(c, problem)
+}
+
+func writeProblem(c echo.Context, status int, code, field, message string, err error) error {
+	return sendProblem(c, newProblem(c, status, code, field, message, err))
+}
+
+func sendProblem(c echo.Context, problem model.Problem) error {
+	c.Response().Header().Set(echo.HeaderContentType, mimeApplicationProblemJSON)
+	return c.JSON(problem.Status, problem)
+}
+
+func newProblem(c echo.Context, status int, code, field, message string, err error) model.Problem {
+	return model.Problem{
+		Type:        problemTypeBlank,
+		Title:       http.StatusText(status),
+		Status:      status,
+		Detail:      fmt.Sprintf("%s: %v", message, err),
+		Instance:    c.Request().URL.Path,
+		Code:        code,
+		Field:       field,
+		RequestUuid: c.Get(common.RequestUuidKey).(string),
+	}
+}
@@ -0,0 +1,259 @@
+package handler
+
+import (
+	"github.com/go-playground/validator/v10"
+	"github.com/khivuksergey/portmonetka.common"
+	serviceerror "github.com/khivuksergey/portmonetka.wallet/error"
+	"github.com/khivuksergey/portmonetka.wallet/internal/core/port/service"
+	"github.com/khivuksergey/portmonetka.wallet/internal/model"
+	"github.com/khivuksergey/webserver/logger"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"strconv"
+)
+
+type TransactionHandler struct {
+	transactionService service.TransactionService
+	logger             logger.Logger
+	validate           *validator.Validate
+}
+
+func NewTransactionHandler(services *service.Manager, logger logger.Logger) *TransactionHandler {
+	return &TransactionHandler{
+		transactionService: services.Transaction,
+		logger:             logger,
+		validate:           model.GetWalletValidator(),
+	}
+}
+
+// GetTransactions retrieves wallet's transactions.
+//
+// @Tags Transaction
+// @Summary Get wallet's transactions
+// @Description Gets transactions posted to the user's wallet
+// @ID get-transactions
+// @Accept json
+// @Produce json
+// @Param userId path uint64 true "Authorized user ID"
+// @Param walletId path uint64 true "Wallet ID"
+// @Success 200 {object} model.Response "Transactions retrieved"
+// @Failure 403 {object} model.Problem "Wallet belongs to another user or the role doesn't permit the operation"
+// @Failure 404 {object} model.Problem "Wallet not found"
+// @Failure 500 {object} model.Problem "Internal server error"
+// @Router /users/{userId}/wallets/{walletId}/transactions [get]
+func (t TransactionHandler) GetTransactions(c echo.Context) error {
+	requestUuid := c.Get(common.RequestUuidKey).(string)
+	userId := c.Get("userId").(uint64)
+	walletId, _ := strconv.ParseUint(c.Param("walletId"), 10, 64)
+
+	transactions, err := t.transactionService.GetTransactionsByWalletId(c.Request().Context(), userId, walletId)
+	if err != nil {
+		return respondProblem(c, t.logger, serviceerror.CannotGetTransactions, err)
+	}
+
+	t.logger.Info(logger.LogMessage{
+		Action:      "GetTransactions",
+		Message:     "Transactions retrieved",
+		UserId:      &userId,
+		Data:        map[string]uint64{"walletId": walletId},
+		RequestUuid: requestUuid,
+	})
+
+	return c.JSON(http.StatusOK, model.Response{
+		Message:     "Transactions retrieved",
+		Data:        transactions,
+		RequestUuid: requestUuid,
+	})
+}
+
+// GetTransaction retrieves wallet's transaction by ID.
+//
+// @Tags Transaction
+// @Summary Get transaction
+// @Description Gets transaction by the provided transaction ID
+// @ID get-transaction
+// @Accept json
+// @Produce json
+// @Param userId path uint64 true "Authorized user ID"
+// @Param walletId path uint64 true "Wallet ID"
+// @Param transactionId path uint64 true "Transaction ID"
+// @Success 200 {object} model.Response "Transaction retrieved"
+// @Failure 403 {object} model.Problem "Wallet belongs to another user or the role doesn't permit the operation"
+// @Failure 404 {object} model.Problem "Wallet or transaction not found"
+// @Failure 500 {object} model.Problem "Internal server error"
+// @Router /users/{userId}/wallets/{walletId}/transactions/{transactionId} [get]
+func (t TransactionHandler) GetTransaction(c echo.Context) error {
+	requestUuid := c.Get(common.RequestUuidKey).(string)
+	userId := c.Get("userId").(uint64)
+	walletId, _ := strconv.ParseUint(c.Param("walletId"), 10, 64)
+	transactionId, _ := strconv.ParseUint(c.Param("transactionId"), 10, 64)
+
+	transaction, err := t.transactionService.GetTransactionById(c.Request().Context(), userId, walletId, transactionId)
+	if err != nil {
+		return respondProblem(c, t.logger, serviceerror.CannotGetTransactions, err)
+	}
+
+	t.logger.Info(logger.LogMessage{
+		Action:      "GetTransaction",
+		Message:     "Transaction retrieved",
+		UserId:      &userId,
+		Data:        map[string]uint64{"id": transaction.Id},
+		RequestUuid: requestUuid,
+	})
+
+	return c.JSON(http.StatusOK, model.Response{
+		Message:     "Transaction retrieved",
+		Data:        transaction,
+		RequestUuid: requestUuid,
+	})
+}
+
+// CreateTransaction posts a new transaction to the wallet.
+//
+// @Tags Transaction
+// @Summary Create a new transaction
+// @Description Posts a new incoming or outgoing transaction to the user's wallet
+// @ID create-transaction
+// @Accept json
+// @Produce json
+// @Param userId path uint64 true "Authorized user ID"
+// @Param walletId path uint64 true "Wallet ID"
+// @Param Idempotency-Key header string false "Key making the request safe to retry"
+// @Param transaction body model.TransactionCreateDTO true "Transaction object to be created"
+// @Success 201 {object} model.Response "Transaction created"
+// @Failure 400 {object} model.Problem "Bad request"
+// @Failure 403 {object} model.Problem "Wallet belongs to another user or the role doesn't permit the operation"
+// @Failure 404 {object} model.Problem "Wallet not found"
+// @Failure 409 {object} model.Problem "Request with the same idempotency key is in progress"
+// @Failure 422 {object} model.Problem "Unprocessable entity, code insufficient_funds if the debit would bring the balance below the wallet floor"
+// @Failure 500 {object} model.Problem "Internal server error"
+// @Router /users/{userId}/wallets/{walletId}/transactions [post]
+func (t TransactionHandler) CreateTransaction(c echo.Context) error {
+	requestUuid := c.Get(common.RequestUuidKey).(string)
+	userId := c.Get("userId").(uint64)
+	walletId, _ := strconv.ParseUint(c.Param("walletId"), 10, 64)
+	transactionCreateDTO := &model.TransactionCreateDTO{}
+
+	err := bindDtoValidate[model.TransactionCreateDTO](c, t.validate, transactionCreateDTO)
+	if err != nil {
+		return respondInvalidInput(c, err)
+	}
+	transactionCreateDTO.UserId, transactionCreateDTO.WalletId = userId, walletId
+
+	transaction, err := t.transactionService.CreateTransaction(c.Request().Context(), *transactionCreateDTO)
+	if err != nil {
+		return respondProblem(c, t.logger, serviceerror.CannotCreateTransaction, err)
+	}
+
+	t.logger.Info(logger.LogMessage{
+		Action:      "CreateTransaction",
+		Message:     "Transaction created",
+		UserId:      &userId,
+		Data:        map[string]uint64{"id": transaction.Id, "walletId": walletId},
+		RequestUuid: requestUuid,
+	})
+
+	return c.JSON(http.StatusCreated, model.Response{
+		Message:     "Transaction created",
+		Data:        transaction,
+		RequestUuid: requestUuid,
+	})
+}
+
+// UpdateTransaction updates the transaction.
+//
+// @Tags Transaction
+// @Summary Update transaction
+// @Description Updates transaction's properties
+// @ID update-transaction
+// @Accept json
+// @Produce json
+// @Param userId path uint64 true "Authorized user ID"
+// @Param walletId path uint64 true "Wallet ID"
+// @Param transactionId path uint64 true "Transaction ID"
+// @Param transaction body model.TransactionUpdateDTO true "Transaction update attributes"
+// @Success 200 {object} model.Response "Transaction updated"
+// @Failure 400 {object} model.Problem "Bad request"
+// @Failure 403 {object} model.Problem "Wallet belongs to another user or the role doesn't permit the operation"
+// @Failure 404 {object} model.Problem "Wallet or transaction not found"
+// @Failure 409 {object} model.Problem "Transaction is a part of a transfer"
+// @Failure 422 {object} model.Problem "Unprocessable entity, code insufficient_funds if the debit would bring the balance below the wallet floor"
+// @Failure 500 {object} model.Problem "Internal server error"
+// @Router /users/{userId}/wallets/{walletId}/transactions/{transactionId} [patch]
+func (t TransactionHandler) UpdateTransaction(c echo.Context) error {
+	requestUuid := c.Get(common.RequestUuidKey).(string)
+	userId := c.Get("userId").(uint64)
+	walletId, _ := strconv.ParseUint(c.Param("walletId"), 10, 64)
+	transactionId, _ := strconv.ParseUint(c.Param("transactionId"), 10, 64)
+	transactionUpdateDTO := &model.TransactionUpdateDTO{}
+
+	err := bindDtoValidate[model.TransactionUpdateDTO](c, t.validate, transactionUpdateDTO)
+	if err != nil {
+		return respondInvalidInput(c, err)
+	}
+	transactionUpdateDTO.Id, transactionUpdateDTO.UserId, transactionUpdateDTO.WalletId = transactionId, userId, walletId
+
+	transaction, err := t.transactionService.UpdateTransaction(c.Request().Context(), *transactionUpdateDTO)
+	if err != nil {
+		return respondProblem(c, t.logger, serviceerror.CannotUpdateTransaction, err)
+	}
+
+	t.logger.Info(logger.LogMessage{
+		Action:      "UpdateTransaction",
+		Message:     "Transaction updated",
+		UserId:      &userId,
+		Data:        map[string]uint64{"id": transaction.Id, "walletId": walletId},
+		RequestUuid: requestUuid,
+	})
+
+	return c.JSON(http.StatusOK, model.Response{
+		Message:     "Transaction updated",
+		Data:        transaction,
+		RequestUuid: requestUuid,
+	})
+}
+
+// DeleteTransaction deletes the transaction by ID.
+//
+// @Tags Transaction
+// @Summary Delete transaction
+// @Description Deletes transaction by the provided transaction ID
+// @ID delete-transaction
+// @Accept json
+// @Produce json
+// @Param userId path uint64 true "Authorized user ID"
+// @Param walletId path uint64 true "Wallet ID"
+// @Param transactionId path uint64 true "Transaction ID"
+// @Success 204 {string} string "No content"
+// @Failure 403 {object} model.Problem "Wallet belongs to another user or the role doesn't permit the operation"
+// @Failure 404 {object} model.Problem "Wallet or transaction not found"
+// @Failure 409 {object} model.Problem "Transaction is a part of a transfer"
+// @Failure 422 {object} model.Problem "Unprocessable entity, code insufficient_funds if the debit would bring the balance below the wallet floor"
+// @Failure 500 {object} model.Problem "Internal server error"
+// @Router /users/{userId}/wallets/{walletId}/transactions/{transactionId} [delete]
+func (t TransactionHandler) DeleteTransaction(c echo.Context) error {
+	requestUuid := c.Get(common.RequestUuidKey).(string)
+	userId := c.Get("userId").(uint64)
+	walletId, _ := strconv.ParseUint(c.Param("walletId"), 10, 64)
+	transactionId, _ := strconv.ParseUint(c.Param("transactionId"), 10, 64)
+	transactionDeleteDTO := model.TransactionDeleteDTO{
+		Id:       transactionId,
+		UserId:   userId,
+		WalletId: walletId,
+	}
+
+	err := t.transactionService.DeleteTransaction(c.Request().Context(), transactionDeleteDTO)
+	if err != nil {
+		return respondProblem(c, t.logger, serviceerror.CannotDeleteTransaction, err)
+	}
+
+	t.logger.Info(logger.LogMessage{
+		Action:      "DeleteTransaction",
+		Message:     "Transaction deleted",
+		UserId:      &userId,
+		Data:        map[string]uint64{"id": transactionId, "walletId": walletId},
+		RequestUuid: requestUuid,
+	})
+
+	return c.NoContent(http.StatusNoContent)
+}
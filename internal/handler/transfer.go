@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"github.com/go-playground/validator/v10"
+	"github.com/khivuksergey/portmonetka.common"
+	serviceerror "github.com/khivuksergey/portmonetka.wallet/error"
+	"github.com/khivuksergey/portmonetka.wallet/internal/core/port/service"
+	"github.com/khivuksergey/portmonetka.wallet/internal/model"
+	"github.com/khivuksergey/webserver/logger"
+	"github.com/labstack/echo/v4"
+	"net/http"
+)
+
+type TransferHandler struct {
+	transferService service.TransferService
+	logger          logger.Logger
+	validate        *validator.Validate
+}
+
+func NewTransferHandler(services *service.Manager, logger logger.Logger) *TransferHandler {
+	return &TransferHandler{
+		transferService: services.Transfer,
+		logger:          logger,
+		validate:        model.GetWalletValidator(),
+	}
+}
+
+// CreateTransfer moves money between two user's wallets.
+//
+// @Tags Transfer
+// @Summary Create a new transfer
+// @Description Atomically debits the source wallet and credits the target wallet. The source wallet defaults to the default wallet from preferences. For wallets with different currencies either target amount or rate is required
+// @ID create-transfer
+// @Accept json
+// @Produce json
+// @Param userId path uint64 true "Authorized user ID"
+// @Param Idempotency-Key header string false "Key making the request safe to retry"
+// @Param transfer body model.TransferCreateDTO true "Transfer object to be created"
+// @Success 201 {object} model.Response "Transfer created"
+// @Failure 400 {object} model.Problem "Bad request"
+// @Failure 403 {object} model.Problem "Wallet belongs to another user or the role doesn't permit the operation"
+// @Failure 404 {object} model.Problem "Source or target wallet not found"
+// @Failure 409 {object} model.Problem "Request with the same idempotency key is in progress"
+// @Failure 422 {object} model.Problem "Unprocessable entity, code insufficient_funds if the debit would bring the balance below the wallet floor"
+// @Failure 500 {object} model.Problem "Internal server error"
+// @Router /users/{userId}/transfers [post]
+func (t TransferHandler) CreateTransfer(c echo.Context) error {
+	requestUuid := c.Get(common.RequestUuidKey).(string)
+	userId := c.Get("userId").(uint64)
+	transferCreateDTO := &model.TransferCreateDTO{}
+
+	err := bindDtoValidate[model.TransferCreateDTO](c, t.validate, transferCreateDTO)
+	if err != nil {
+		return respondInvalidInput(c, err)
+	}
+	transferCreateDTO.UserId = userId
+
+	transfer, err := t.transferService.CreateTransfer(c.Request().Context(), *transferCreateDTO)
+	if err != nil {
+		return respondProblem(c, t.logger, serviceerror.CannotCreateTransfer, err)
+	}
+
+	t.logger.Info(logger.LogMessage{
+		Action:  "CreateTransfer",
+		Message: "Transfer created",
+		UserId:  &userId,
+		Data: map[string]uint64{
+			"id":             transfer.Id,
+			"sourceWalletId": transfer.SourceWalletId,
+			"targetWalletId": transfer.TargetWalletId,
+		},
+		RequestUuid: requestUuid,
+	})
+
+	return c.JSON(http.StatusCreated, model.Response{
+		Message:     "Transfer created",
+		Data:        transfer,
+		RequestUuid: requestUuid,
+	})
+}
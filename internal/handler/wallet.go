@@ -35,16 +35,34 @@ func NewWalletHandler(services *service.Manager, logger logger.Logger) *WalletHa
 // @Accept json
 // @Produce json
 // @Param userId path uint64 true "Authorized user ID"
+// @Param currency query string false "Currency code"
+// @Param type query string false "Wallet type" Enums(cash, debit_card, credit_card, savings, loan, investment, crypto)
+// @Param name query string false "Case-insensitive substring of the wallet name"
+// @Param createdFrom query string false "Created at or after, RFC 3339"
+// @Param createdTo query string false "Created before, RFC 3339"
+// @Param updatedFrom query string false "Updated at or after, RFC 3339"
+// @Param updatedTo query string false "Updated before, RFC 3339"
+// @Param sort query string false "Sort field, prefix with '-' for descending order" Enums(name, -name, createdAt, -createdAt, updatedAt, -updatedAt, balance, -balance) default(-updatedAt)
+// @Param limit query int false "Page size" minimum(1) maximum(100) default(50)
+// @Param cursor query string false "Cursor of the next page from the previous response"
 // @Success 200 {object} model.Response "Wallets retrieved"
-// @Failure 422 {object} model.Response "Unprocessable entity"
+// @Failure 400 {object} model.Problem "Bad request"
+// @Failure 422 {object} model.Problem "Unprocessable entity"
+// @Failure 500 {object} model.Problem "Internal server error"
 // @Router /users/{userId}/wallets [get]
 func (w WalletHandler) GetWallets(c echo.Context) error {
 	requestUuid := c.Get(common.RequestUuidKey).(string)
 	userId := c.Get("userId").(uint64)
+	walletListQuery := &model.WalletListQuery{}
 
-	wallets, err := w.walletService.GetWalletsByUserId(userId)
+	err := bindDtoValidate[model.WalletListQuery](c, w.validate, walletListQuery)
 	if err != nil {
-		return common.NewUnprocessableEntityError(serviceerror.CannotGetWallets, err)
+		return respondInvalidInput(c, err)
+	}
+
+	wallets, nextCursor, err := w.walletService.GetWalletsByUserId(c.Request().Context(), userId, *walletListQuery)
+	if err != nil {
+		return respondProblem(c, w.logger, serviceerror.CannotGetWallets, err)
 	}
 
 	w.logger.Info(logger.LogMessage{
@@ -58,6 +76,145 @@ func (w WalletHandler) GetWallets(c echo.Context) error {
 		Message:     "Wallets retrieved",
 		Data:        wallets,
 		RequestUuid: requestUuid,
+		NextCursor:  nextCursor,
+	})
+}
+
+// GetWalletsSummary retrieves user's net worth in the base currency.
+//
+// @Tags Wallet
+// @Summary Get wallets summary
+// @Description Converts balances of all user's wallets to the base currency with the exchange rates effective on the date and sums them up
+// @ID get-wallets-summary
+// @Accept json
+// @Produce json
+// @Param userId path uint64 true "Authorized user ID"
+// @Param base query string false "Base currency code, the one from user's preferences by default"
+// @Param date query string false "Date of the exchange rates, YYYY-MM-DD, today by default"
+// @Success 200 {object} model.Response "Wallets summary retrieved"
+// @Failure 400 {object} model.Problem "Bad request"
+// @Failure 422 {object} model.Problem "Unprocessable entity"
+// @Failure 500 {object} model.Problem "Internal server error"
+// @Router /users/{userId}/wallets/summary [get]
+func (w WalletHandler) GetWalletsSummary(c echo.Context) error {
+	requestUuid := c.Get(common.RequestUuidKey).(string)
+	userId := c.Get("userId").(uint64)
+	walletSummaryQuery := &model.WalletSummaryQuery{}
+
+	err := bindDtoValidate[model.WalletSummaryQuery](c, w.validate, walletSummaryQuery)
+	if err != nil {
+		return respondInvalidInput(c, err)
+	}
+
+	summary, err := w.walletService.GetWalletsSummary(c.Request().Context(), userId, *walletSummaryQuery)
+	if err != nil {
+		return respondProblem(c, w.logger, serviceerror.CannotGetWalletsSummary, err)
+	}
+
+	w.logger.Info(logger.LogMessage{
+		Action:      "GetWalletsSummary",
+		Message:     "Wallets summary retrieved",
+		UserId:      &userId,
+		Data:        map[string]string{"base": summary.BaseCurrency, "date": summary.Date},
+		RequestUuid: requestUuid,
+	})
+
+	return c.JSON(http.StatusOK, model.Response{
+		Message:     "Wallets summary retrieved",
+		Data:        summary,
+		RequestUuid: requestUuid,
+	})
+}
+
+// GetWallet retrieves user's wallet by ID.
+//
+// @Tags Wallet
+// @Summary Get user's wallet
+// @Description Gets user's wallet by the provided wallet ID. Supports conditional requests with If-None-Match
+// @ID get-wallet
+// @Accept json
+// @Produce json
+// @Param userId path uint64 true "Authorized user ID"
+// @Param walletId path uint64 true "Wallet ID"
+// @Param If-None-Match header string false "ETag of the cached wallet"
+// @Success 200 {object} model.Response "Wallet retrieved"
+// @Success 304 {string} string "Not modified"
+// @Failure 403 {object} model.Problem "Wallet belongs to another user"
+// @Failure 404 {object} model.Problem "Wallet not found"
+// @Failure 422 {object} model.Problem "Unprocessable entity"
+// @Failure 500 {object} model.Problem "Internal server error"
+// @Router /users/{userId}/wallets/{walletId} [get]
+func (w WalletHandler) GetWallet(c echo.Context) error {
+	requestUuid := c.Get(common.RequestUuidKey).(string)
+	userId := c.Get("userId").(uint64)
+	walletId, _ := strconv.ParseUint(c.Param("walletId"), 10, 64)
+
+	wallet, err := w.walletService.GetWalletById(c.Request().Context(), userId, walletId)
+	if err != nil {
+		return respondProblem(c, w.logger, serviceerror.CannotGetWallets, err)
+	}
+
+	etag := walletETag(wallet)
+	c.Response().Header().Set(echo.HeaderCacheControl, "private, no-cache")
+	c.Response().Header().Set(headerETag, etag)
+
+	if ifNoneMatch := c.Request().Header.Get(headerIfNoneMatch); ifNoneMatch != "" && etagMatches(ifNoneMatch, etag) {
+		return c.NoContent(http.StatusNotModified)
+	}
+
+	w.logger.Info(logger.LogMessage{
+		Action:      "GetWallet",
+		Message:     "Wallet retrieved",
+		UserId:      &userId,
+		Data:        map[string]uint64{"id": wallet.Id},
+		RequestUuid: requestUuid,
+	})
+
+	return c.JSON(http.StatusOK, model.Response{
+		Message:     "Wallet retrieved",
+		Data:        wallet,
+		RequestUuid: requestUuid,
+	})
+}
+
+// GetWalletHistory retrieves the audit log of user's wallet.
+//
+// @Tags Wallet
+// @Summary Get wallet history
+// @Description Gets who created, updated, deleted and restored the wallet and when, with the values of the changed fields before and after each change, oldest first
+// @ID get-wallet-history
+// @Accept json
+// @Produce json
+// @Param userId path uint64 true "Authorized user ID"
+// @Param walletId path uint64 true "Wallet ID"
+// @Success 200 {object} model.Response "Wallet history retrieved"
+// @Failure 403 {object} model.Problem "Wallet belongs to another user"
+// @Failure 404 {object} model.Problem "Wallet not found"
+// @Failure 422 {object} model.Problem "Unprocessable entity"
+// @Failure 500 {object} model.Problem "Internal server error"
+// @Router /users/{userId}/wallets/{walletId}/history [get]
+func (w WalletHandler) GetWalletHistory(c echo.Context) error {
+	requestUuid := c.Get(common.RequestUuidKey).(string)
+	userId := c.Get("userId").(uint64)
+	walletId, _ := strconv.ParseUint(c.Param("walletId"), 10, 64)
+
+	history, err := w.walletService.GetWalletHistory(c.Request().Context(), userId, walletId)
+	if err != nil {
+		return respondProblem(c, w.logger, serviceerror.CannotGetWalletHistory, err)
+	}
+
+	w.logger.Info(logger.LogMessage{
+		Action:      "GetWalletHistory",
+		Message:     "Wallet history retrieved",
+		UserId:      &userId,
+		Data:        map[string]uint64{"id": walletId},
+		RequestUuid: requestUuid,
+	})
+
+	return c.JSON(http.StatusOK, model.Response{
+		Message:     "Wallet history retrieved",
+		Data:        history,
+		RequestUuid: requestUuid,
 	})
 }
 
@@ -70,10 +227,13 @@ func (w WalletHandler) GetWallets(c echo.Context) error {
 // @Accept json
 // @Produce json
 // @Param userId path uint64 true "Authorized user ID"
+// @Param Idempotency-Key header string false "Key making the request safe to retry"
 // @Param wallet body model.WalletCreateDTO true "Wallet object to be created"
 // @Success 201 {object} model.Response "Wallet created"
-// @Failure 400 {object} model.Response "Bad request"
-// @Failure 422 {object} model.Response "Unprocessable entity"
+// @Failure 400 {object} model.Problem "Bad request"
+// @Failure 409 {object} model.Problem "Wallet with this name already exists or request with the same idempotency key is in progress"
+// @Failure 422 {object} model.Problem "Unprocessable entity"
+// @Failure 500 {object} model.Problem "Internal server error"
 // @Router /users/{userId}/wallets [post]
 func (w WalletHandler) CreateWallet(c echo.Context) error {
 	requestUuid := c.Get(common.RequestUuidKey).(string)
@@ -82,12 +242,12 @@ func (w WalletHandler) CreateWallet(c echo.Context) error {
 
 	err := bindDtoValidate[model.WalletCreateDTO](c, w.validate, walletCreateDTO)
 	if err != nil {
-		return common.NewValidationError(serviceerror.InvalidInputData, err)
+		return respondInvalidInput(c, err)
 	}
 
-	wallet, err := w.walletService.CreateWallet(*walletCreateDTO)
+	wallet, err := w.walletService.CreateWallet(c.Request().Context(), *walletCreateDTO)
 	if err != nil {
-		return common.NewUnprocessableEntityError(serviceerror.CannotCreateWallet, err)
+		return respondProblem(c, w.logger, serviceerror.CannotCreateWallet, err)
 	}
 
 	w.logger.Info(logger.LogMessage{
@@ -114,10 +274,17 @@ func (w WalletHandler) CreateWallet(c echo.Context) error {
 // @Accept json
 // @Produce json
 // @Param userId path uint64 true "Authorized user ID"
+// @Param walletId path uint64 true "Wallet ID"
+// @Param If-Match header string false "ETag of the wallet version being updated"
 // @Param wallet body model.WalletUpdateDTO true "Wallet update attributes"
 // @Success 200 {object} model.Response "Wallet updated"
-// @Failure 400 {object} model.Response "Bad request"
-// @Failure 422 {object} model.Response "Unprocessable entity"
+// @Failure 400 {object} model.Problem "Bad request"
+// @Failure 403 {object} model.Problem "Wallet belongs to another user or the role doesn't permit the operation"
+// @Failure 404 {object} model.Problem "Wallet not found"
+// @Failure 409 {object} model.Problem "Wallet with this name already exists"
+// @Failure 412 {object} model.Problem "Precondition failed"
+// @Failure 422 {object} model.Problem "Unprocessable entity"
+// @Failure 500 {object} model.Problem "Internal server error"
 // @Router /users/{userId}/wallets/{walletId} [patch]
 func (w WalletHandler) UpdateWallet(c echo.Context) error {
 	requestUuid := c.Get(common.RequestUuidKey).(string)
@@ -130,14 +297,21 @@ func (w WalletHandler) UpdateWallet(c echo.Context) error {
 
 	err := bindDtoValidate[model.WalletUpdateDTO](c, w.validate, walletUpdateDTO)
 	if err != nil {
-		return common.NewValidationError(serviceerror.InvalidInputData, err)
+		return respondInvalidInput(c, err)
 	}
 
-	wallet, err := w.walletService.UpdateWallet(*walletUpdateDTO)
+	walletUpdateDTO.Versions, err = versionsFromIfMatch(c)
 	if err != nil {
-		return common.NewUnprocessableEntityError(serviceerror.CannotUpdateWallet, err)
+		return respondProblem(c, w.logger, serviceerror.CannotUpdateWallet, err)
 	}
 
+	wallet, err := w.walletService.UpdateWallet(c.Request().Context(), *walletUpdateDTO)
+	if err != nil {
+		return respondProblem(c, w.logger, serviceerror.CannotUpdateWallet, err)
+	}
+
+	c.Response().Header().Set(headerETag, walletETag(wallet))
+
 	w.logger.Info(logger.LogMessage{
 		Action:      "UpdateWallet",
 		Message:     "Wallet updated",
@@ -162,10 +336,16 @@ func (w WalletHandler) UpdateWallet(c echo.Context) error {
 // @Accept json
 // @Produce json
 // @Param userId path uint64 true "Authorized user ID"
+// @Param walletId path uint64 true "Wallet ID"
+// @Param If-Match header string false "ETag of the wallet version being deleted"
 // @Param wallet body model.WalletDeleteDTO true "Wallet delete request"
 // @Success 204 {string} string "No content"
-// @Failure 400 {object} model.Response "Bad request"
-// @Failure 422 {object} model.Response "Unprocessable entity"
+// @Failure 400 {object} model.Problem "Bad request"
+// @Failure 403 {object} model.Problem "Wallet belongs to another user or the role doesn't permit the operation"
+// @Failure 404 {object} model.Problem "Wallet not found"
+// @Failure 412 {object} model.Problem "Precondition failed"
+// @Failure 422 {object} model.Problem "Unprocessable entity"
+// @Failure 500 {object} model.Problem "Internal server error"
 // @Router /users/{userId}/wallets/{walletId} [delete]
 func (w WalletHandler) DeleteWallet(c echo.Context) error {
 	requestUuid := c.Get(common.RequestUuidKey).(string)
@@ -178,11 +358,17 @@ func (w WalletHandler) DeleteWallet(c echo.Context) error {
 
 	err := bindDtoValidate[model.WalletDeleteDTO](c, w.validate, walletDeleteDTO)
 	if err != nil {
-		return common.NewValidationError(serviceerror.InvalidInputData, err)
+		return respondInvalidInput(c, err)
+	}
+
+	walletDeleteDTO.Versions, err = versionsFromIfMatch(c)
+	if err != nil {
+		return respondProblem(c, w.logger, serviceerror.CannotDeleteWallet, err)
 	}
 
-	if err := w.walletService.DeleteWallet(*walletDeleteDTO); err != nil {
-		return common.NewUnprocessableEntityError(serviceerror.CannotDeleteWallet, err)
+	err = w.walletService.DeleteWallet(c.Request().Context(), *walletDeleteDTO)
+	if err != nil {
+		return respondProblem(c, w.logger, serviceerror.CannotDeleteWallet, err)
 	}
 
 	w.logger.Info(logger.LogMessage{
@@ -196,12 +382,136 @@ func (w WalletHandler) DeleteWallet(c echo.Context) error {
 	return c.NoContent(http.StatusNoContent)
 }
 
+// GetDeletedWallets retrieves user's wallets in the trash.
+//
+// @Tags Trash
+// @Summary Get user's deleted wallets
+// @Description Gets user's soft-deleted wallets which weren't purged yet
+// @ID get-deleted-wallets
+// @Accept json
+// @Produce json
+// @Param userId path uint64 true "Authorized user ID"
+// @Success 200 {object} model.Response "Deleted wallets retrieved"
+// @Failure 422 {object} model.Problem "Unprocessable entity"
+// @Failure 500 {object} model.Problem "Internal server error"
+// @Router /users/{userId}/wallets/trash [get]
+func (w WalletHandler) GetDeletedWallets(c echo.Context) error {
+	requestUuid := c.Get(common.RequestUuidKey).(string)
+	userId := c.Get("userId").(uint64)
+
+	wallets, err := w.walletService.GetDeletedWalletsByUserId(c.Request().Context(), userId)
+	if err != nil {
+		return respondProblem(c, w.logger, serviceerror.CannotGetDeletedWallets, err)
+	}
+
+	w.logger.Info(logger.LogMessage{
+		Action:      "GetDeletedWallets",
+		Message:     "Deleted wallets retrieved",
+		UserId:      &userId,
+		RequestUuid: requestUuid,
+	})
+
+	return c.JSON(http.StatusOK, model.Response{
+		Message:     "Deleted wallets retrieved",
+		Data:        model.NewDeletedWallets(wallets),
+		RequestUuid: requestUuid,
+	})
+}
+
+// RestoreWallet restores the wallet from the trash.
+//
+// @Tags Trash
+// @Summary Restore deleted wallet
+// @Description Restores soft-deleted wallet if there is no other wallet with the same name
+// @ID restore-wallet
+// @Accept json
+// @Produce json
+// @Param userId path uint64 true "Authorized user ID"
+// @Param walletId path uint64 true "Wallet ID"
+// @Success 200 {object} model.Response "Wallet restored"
+// @Failure 403 {object} model.Problem "Wallet belongs to another user"
+// @Failure 404 {object} model.Problem "Deleted wallet not found"
+// @Failure 409 {object} model.Problem "Wallet with this name already exists"
+// @Failure 422 {object} model.Problem "Unprocessable entity"
+// @Failure 500 {object} model.Problem "Internal server error"
+// @Router /users/{userId}/wallets/trash/{walletId}/restore [post]
+func (w WalletHandler) RestoreWallet(c echo.Context) error {
+	requestUuid := c.Get(common.RequestUuidKey).(string)
+	userId := c.Get("userId").(uint64)
+	walletId, _ := strconv.ParseUint(c.Param("walletId"), 10, 64)
+	walletTrashDTO := model.WalletTrashDTO{
+		Id:     walletId,
+		UserId: userId,
+	}
+
+	wallet, err := w.walletService.RestoreWallet(c.Request().Context(), walletTrashDTO)
+	if err != nil {
+		return respondProblem(c, w.logger, serviceerror.CannotRestoreWallet, err)
+	}
+
+	w.logger.Info(logger.LogMessage{
+		Action:      "RestoreWallet",
+		Message:     "Wallet restored",
+		UserId:      &userId,
+		Data:        map[string]uint64{"id": wallet.Id},
+		RequestUuid: requestUuid,
+	})
+
+	return c.JSON(http.StatusOK, model.Response{
+		Message:     "Wallet restored",
+		Data:        wallet,
+		RequestUuid: requestUuid,
+	})
+}
+
+// PurgeWallet permanently deletes the wallet from the trash.
+//
+// @Tags Trash
+// @Summary Purge deleted wallet
+// @Description Permanently deletes soft-deleted wallet and its transactions
+// @ID purge-wallet
+// @Accept json
+// @Produce json
+// @Param userId path uint64 true "Authorized user ID"
+// @Param walletId path uint64 true "Wallet ID"
+// @Success 204 {string} string "No content"
+// @Failure 403 {object} model.Problem "Wallet belongs to another user"
+// @Failure 404 {object} model.Problem "Deleted wallet not found"
+// @Failure 422 {object} model.Problem "Unprocessable entity"
+// @Failure 500 {object} model.Problem "Internal server error"
+// @Router /users/{userId}/wallets/trash/{walletId} [delete]
+func (w WalletHandler) PurgeWallet(c echo.Context) error {
+	requestUuid := c.Get(common.RequestUuidKey).(string)
+	userId := c.Get("userId").(uint64)
+	walletId, _ := strconv.ParseUint(c.Param("walletId"), 10, 64)
+	walletTrashDTO := model.WalletTrashDTO{
+		Id:     walletId,
+		UserId: userId,
+	}
+
+	if err := w.walletService.PurgeWallet(c.Request().Context(), walletTrashDTO); err != nil {
+		return respondProblem(c, w.logger, serviceerror.CannotPurgeWallet, err)
+	}
+
+	w.logger.Info(logger.LogMessage{
+		Action:      "PurgeWallet",
+		Message:     "Wallet purged",
+		UserId:      &userId,
+		Data:        map[string]uint64{"id": walletId},
+		RequestUuid: requestUuid,
+	})
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// bindDtoValidate binds the request into the DTO and validates it. Validation errors are returned
+// as model.InvalidFieldsError with messages in the language requested by the Accept-Language header.
 func bindDtoValidate[T any](c echo.Context, validate *validator.Validate, dto *T) error {
 	if err := c.Bind(dto); err != nil {
 		return err
 	}
 	if err := validate.Struct(dto); err != nil {
-		return err
+		return model.TranslateValidationError(err, c.Request().Header.Get(headerAcceptLanguage))
 	}
 	return nil
 }
@@ -0,0 +1,271 @@
+package handler
+
+import (
+	"github.com/go-playground/validator/v10"
+	"github.com/khivuksergey/portmonetka.common"
+	serviceerror "github.com/khivuksergey/portmonetka.wallet/error"
+	"github.com/khivuksergey/portmonetka.wallet/internal/core/port/service"
+	"github.com/khivuksergey/portmonetka.wallet/internal/model"
+	"github.com/khivuksergey/webserver/logger"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"strconv"
+)
+
+type WalletMemberHandler struct {
+	walletMemberService service.WalletMemberService
+	logger              logger.Logger
+	validate            *validator.Validate
+}
+
+func NewWalletMemberHandler(services *service.Manager, logger logger.Logger) *WalletMemberHandler {
+	return &WalletMemberHandler{
+		walletMemberService: services.WalletMember,
+		logger:              logger,
+		validate:            model.GetWalletValidator(),
+	}
+}
+
+// GetWalletMembers retrieves members of the wallet.
+//
+// @Tags Wallet member
+// @Summary Get wallet members
+// @Description Gets members and pending invitations of the wallet, available to any member. The owner who created the wallet is not listed
+// @ID get-wallet-members
+// @Accept json
+// @Produce json
+// @Param userId path uint64 true "Authorized user ID"
+// @Param walletId path uint64 true "Wallet ID"
+// @Success 200 {object} model.Response "Wallet members retrieved"
+// @Failure 403 {object} model.Problem "Wallet belongs to another user or the role doesn't permit the operation"
+// @Failure 404 {object} model.Problem "Wallet not found"
+// @Failure 500 {object} model.Problem "Internal server error"
+// @Router /users/{userId}/wallets/{walletId}/members [get]
+func (m WalletMemberHandler) GetWalletMembers(c echo.Context) error {
+	requestUuid := c.Get(common.RequestUuidKey).(string)
+	userId := c.Get("userId").(uint64)
+	walletId, _ := strconv.ParseUint(c.Param("walletId"), 10, 64)
+
+	walletMembers, err := m.walletMemberService.GetWalletMembers(c.Request().Context(), userId, walletId)
+	if err != nil {
+		return respondProblem(c, m.logger, serviceerror.CannotGetWalletMembers, err)
+	}
+
+	m.logger.Info(logger.LogMessage{
+		Action:      "GetWalletMembers",
+		Message:     "Wallet members retrieved",
+		UserId:      &userId,
+		Data:        map[string]uint64{"walletId": walletId},
+		RequestUuid: requestUuid,
+	})
+
+	return c.JSON(http.StatusOK, model.Response{
+		Message:     "Wallet members retrieved",
+		Data:        walletMembers,
+		RequestUuid: requestUuid,
+	})
+}
+
+// InviteWalletMember invites a user to the wallet.
+//
+// @Tags Wallet member
+// @Summary Invite wallet member
+// @Description Invites the user to the wallet with the editor or viewer role. Requires the owner role. The invitee gets access after accepting the invitation
+// @ID invite-wallet-member
+// @Accept json
+// @Produce json
+// @Param userId path uint64 true "Authorized user ID"
+// @Param walletId path uint64 true "Wallet ID"
+// @Param Idempotency-Key header string false "Key making the request safe to retry"
+// @Param member body model.WalletMemberInviteDTO true "Wallet member invite request"
+// @Success 201 {object} model.Response "Wallet member invited"
+// @Failure 400 {object} model.Problem "Bad request"
+// @Failure 403 {object} model.Problem "Wallet belongs to another user or the role doesn't permit the operation"
+// @Failure 404 {object} model.Problem "Wallet not found"
+// @Failure 409 {object} model.Problem "User is already a member of the wallet or invited to it"
+// @Failure 500 {object} model.Problem "Internal server error"
+// @Router /users/{userId}/wallets/{walletId}/members [post]
+func (m WalletMemberHandler) InviteWalletMember(c echo.Context) error {
+	requestUuid := c.Get(common.RequestUuidKey).(string)
+	userId := c.Get("userId").(uint64)
+	walletId, _ := strconv.ParseUint(c.Param("walletId"), 10, 64)
+	walletMemberInviteDTO := &model.WalletMemberInviteDTO{}
+
+	err := bindDtoValidate[model.WalletMemberInviteDTO](c, m.validate, walletMemberInviteDTO)
+	if err != nil {
+		return respondInvalidInput(c, err)
+	}
+	walletMemberInviteDTO.UserId = userId
+	walletMemberInviteDTO.WalletId = walletId
+
+	walletMember, err := m.walletMemberService.InviteWalletMember(c.Request().Context(), *walletMemberInviteDTO)
+	if err != nil {
+		return respondProblem(c, m.logger, serviceerror.CannotInviteWalletMember, err)
+	}
+
+	m.logger.Info(logger.LogMessage{
+		Action:      "InviteWalletMember",
+		Message:     "Wallet member invited",
+		UserId:      &userId,
+		Data:        map[string]uint64{"id": walletMember.Id, "walletId": walletId},
+		RequestUuid: requestUuid,
+	})
+
+	return c.JSON(http.StatusCreated, model.Response{
+		Message:     "Wallet member invited",
+		Data:        walletMember,
+		RequestUuid: requestUuid,
+	})
+}
+
+// RemoveWalletMember removes the member from the wallet.
+//
+// @Tags Wallet member
+// @Summary Remove wallet member
+// @Description Removes the member or cancels the invitation. Requires the owner role unless members remove themselves
+// @ID remove-wallet-member
+// @Accept json
+// @Produce json
+// @Param userId path uint64 true "Authorized user ID"
+// @Param walletId path uint64 true "Wallet ID"
+// @Param memberId path uint64 true "Wallet member ID"
+// @Success 204 {string} string "No content"
+// @Failure 403 {object} model.Problem "Wallet belongs to another user or the role doesn't permit the operation"
+// @Failure 404 {object} model.Problem "Wallet or member not found"
+// @Failure 500 {object} model.Problem "Internal server error"
+// @Router /users/{userId}/wallets/{walletId}/members/{memberId} [delete]
+func (m WalletMemberHandler) RemoveWalletMember(c echo.Context) error {
+	requestUuid := c.Get(common.RequestUuidKey).(string)
+	userId := c.Get("userId").(uint64)
+	walletId, _ := strconv.ParseUint(c.Param("walletId"), 10, 64)
+	memberId, _ := strconv.ParseUint(c.Param("memberId"), 10, 64)
+	walletMemberRemoveDTO := model.WalletMemberRemoveDTO{
+		Id:       memberId,
+		UserId:   userId,
+		WalletId: walletId,
+	}
+
+	err := m.walletMemberService.RemoveWalletMember(c.Request().Context(), walletMemberRemoveDTO)
+	if err != nil {
+		return respondProblem(c, m.logger, serviceerror.CannotRemoveWalletMember, err)
+	}
+
+	m.logger.Info(logger.LogMessage{
+		Action:      "RemoveWalletMember",
+		Message:     "Wallet member removed",
+		UserId:      &userId,
+		Data:        map[string]uint64{"id": memberId, "walletId": walletId},
+		RequestUuid: requestUuid,
+	})
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// GetInvitations retrieves user's pending invitations.
+//
+// @Tags Wallet member
+// @Summary Get user's invitations
+// @Description Gets invitations to other users' wallets which the user hasn't accepted yet
+// @ID get-invitations
+// @Accept json
+// @Produce json
+// @Param userId path uint64 true "Authorized user ID"
+// @Success 200 {object} model.Response "Invitations retrieved"
+// @Failure 500 {object} model.Problem "Internal server error"
+// @Router /users/{userId}/invitations [get]
+func (m WalletMemberHandler) GetInvitations(c echo.Context) error {
+	requestUuid := c.Get(common.RequestUuidKey).(string)
+	userId := c.Get("userId").(uint64)
+
+	invitations, err := m.walletMemberService.GetInvitations(c.Request().Context(), userId)
+	if err != nil {
+		return respondProblem(c, m.logger, serviceerror.CannotGetInvitations, err)
+	}
+
+	m.logger.Info(logger.LogMessage{
+		Action:      "GetInvitations",
+		Message:     "Invitations retrieved",
+		UserId:      &userId,
+		RequestUuid: requestUuid,
+	})
+
+	return c.JSON(http.StatusOK, model.Response{
+		Message:     "Invitations retrieved",
+		Data:        invitations,
+		RequestUuid: requestUuid,
+	})
+}
+
+// AcceptInvitation accepts the invitation to a wallet.
+//
+// @Tags Wallet member
+// @Summary Accept invitation
+// @Description Accepts the pending invitation, the wallet becomes available to the user with the invited role
+// @ID accept-invitation
+// @Accept json
+// @Produce json
+// @Param userId path uint64 true "Authorized user ID"
+// @Param invitationId path uint64 true "Invitation ID"
+// @Success 200 {object} model.Response "Invitation accepted"
+// @Failure 404 {object} model.Problem "Invitation not found"
+// @Failure 500 {object} model.Problem "Internal server error"
+// @Router /users/{userId}/invitations/{invitationId}/accept [post]
+func (m WalletMemberHandler) AcceptInvitation(c echo.Context) error {
+	requestUuid := c.Get(common.RequestUuidKey).(string)
+	userId := c.Get("userId").(uint64)
+	invitationId, _ := strconv.ParseUint(c.Param("invitationId"), 10, 64)
+
+	walletMember, err := m.walletMemberService.AcceptInvitation(c.Request().Context(), model.InvitationDTO{Id: invitationId, UserId: userId})
+	if err != nil {
+		return respondProblem(c, m.logger, serviceerror.CannotAcceptInvitation, err)
+	}
+
+	m.logger.Info(logger.LogMessage{
+		Action:      "AcceptInvitation",
+		Message:     "Invitation accepted",
+		UserId:      &userId,
+		Data:        map[string]uint64{"id": invitationId, "walletId": walletMember.WalletId},
+		RequestUuid: requestUuid,
+	})
+
+	return c.JSON(http.StatusOK, model.Response{
+		Message:     "Invitation accepted",
+		Data:        walletMember,
+		RequestUuid: requestUuid,
+	})
+}
+
+// DeclineInvitation declines the invitation to a wallet.
+//
+// @Tags Wallet member
+// @Summary Decline invitation
+// @Description Declines and deletes the pending invitation
+// @ID decline-invitation
+// @Accept json
+// @Produce json
+// @Param userId path uint64 true "Authorized user ID"
+// @Param invitationId path uint64 true "Invitation ID"
+// @Success 204 {string} string "No content"
+// @Failure 404 {object} model.Problem "Invitation not found"
+// @Failure 500 {object} model.Problem "Internal server error"
+// @Router /users/{userId}/invitations/{invitationId} [delete]
+func (m WalletMemberHandler) DeclineInvitation(c echo.Context) error {
+	requestUuid := c.Get(common.RequestUuidKey).(string)
+	userId := c.Get("userId").(uint64)
+	invitationId, _ := strconv.ParseUint(c.Param("invitationId"), 10, 64)
+
+	err := m.walletMemberService.DeclineInvitation(c.Request().Context(), model.InvitationDTO{Id: invitationId, UserId: userId})
+	if err != nil {
+		return respondProblem(c, m.logger, serviceerror.CannotDeclineInvitation, err)
+	}
+
+	m.logger.Info(logger.LogMessage{
+		Action:      "DeclineInvitation",
+		Message:     "Invitation declined",
+		UserId:      &userId,
+		Data:        map[string]uint64{"id": invitationId},
+		RequestUuid: requestUuid,
+	})
+
+	return c.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,110 @@
+package http
+
+import (
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/khivuksergey/portmonetka.common"
+	"github.com/khivuksergey/portmonetka.wallet/internal/model"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"slices"
+	"strconv"
+)
+
+const (
+	roleClaim    = "role"
+	subjectClaim = "sub"
+	adminRole    = "admin"
+	serviceRole  = "service"
+)
+
+// actingRoles may act on behalf of any user, e.g. support staff and internal services.
+var actingRoles = []string{adminRole, serviceRole}
+
+// authorizeUser lets through requests to the users/:userId routes whose JWT, already verified
+// by the authentication middleware, either has the subject equal to the userId path parameter
+// or carries a role which may act on behalf of any user. The userId from the path is then
+// set into the context for handlers, so they never act on a user other than the authorized one.
+// The request context carries the request info for the audit log, whose actor is the subject
+// of the token or, for acting roles without a subject, the user acted on.
+func authorizeUser(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		claims, err := tokenClaims(c)
+		if err != nil {
+			return err
+		}
+		userId, err := strconv.ParseUint(c.Param("userId"), 10, 64)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid path param userId")
+		}
+		subject, hasSubject := subjectId(claims)
+		if !hasRole(claims, actingRoles...) {
+			if !hasSubject {
+				return echo.NewHTTPError(http.StatusUnauthorized, "invalid subject claim")
+			}
+			if subject != userId {
+				return echo.NewHTTPError(http.StatusForbidden, "access to another user's resources is denied")
+			}
+		}
+		c.Set("userId", userId)
+
+		actorId := userId
+		if hasSubject {
+			actorId = subject
+		}
+		requestUuid, _ := c.Get(common.RequestUuidKey).(string)
+		ctx := model.ContextWithRequestInfo(c.Request().Context(), model.RequestInfo{ActorId: actorId, RequestUuid: requestUuid})
+		c.SetRequest(c.Request().WithContext(ctx))
+		return next(c)
+	}
+}
+
+// requireRole lets through only requests whose JWT, already verified by the authentication
+// middleware, carries one of the roles.
+func requireRole(roles ...string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			claims, err := tokenClaims(c)
+			if err != nil {
+				return err
+			}
+			if !hasRole(claims, roles...) {
+				return echo.NewHTTPError(http.StatusForbidden, "required role is missing")
+			}
+			return next(c)
+		}
+	}
+}
+
+func tokenClaims(c echo.Context) (jwt.MapClaims, error) {
+	token, ok := c.Get("user").(*jwt.Token)
+	if !ok {
+		return nil, echo.NewHTTPError(http.StatusUnauthorized, "invalid token")
+	}
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil, echo.NewHTTPError(http.StatusUnauthorized, "invalid token claims")
+	}
+	return claims, nil
+}
+
+func hasRole(claims jwt.MapClaims, roles ...string) bool {
+	role, ok := claims[roleClaim].(string)
+	return ok && slices.Contains(roles, role)
+}
+
+// subjectId returns the user ID from the subject claim, which is either a JSON number
+// or, as the JWT specification defines it, a string.
+func subjectId(claims jwt.MapClaims) (uint64, bool) {
+	switch sub := claims[subjectClaim].(type) {
+	case float64:
+		if sub < 0 || sub != float64(uint64(sub)) {
+			return 0, false
+		}
+		return uint64(sub), true
+	case string:
+		id, err := strconv.ParseUint(sub, 10, 64)
+		return id, err == nil
+	default:
+		return 0, false
+	}
+}
@@ -3,8 +3,10 @@ package http
 import (
 	"github.com/khivuksergey/portmonetka.common/middleware/authentication"
 	"github.com/khivuksergey/portmonetka.common/middleware/error"
+	"github.com/khivuksergey/portmonetka.wallet/config"
 	"github.com/khivuksergey/portmonetka.wallet/internal/core/port/service"
 	"github.com/khivuksergey/portmonetka.wallet/internal/handler"
+	"github.com/khivuksergey/portmonetka.wallet/internal/middleware/idempotency"
 	"github.com/khivuksergey/webserver/logger"
 	"github.com/spf13/viper"
 )
@@ -12,13 +14,27 @@ import (
 type Handlers struct {
 	error          *error.ErrorHandlingMiddleware
 	authentication *authentication.AuthenticationMiddleware
+	idempotency    *idempotency.IdempotencyMiddleware
 	wallet         *handler.WalletHandler
+	transaction    *handler.TransactionHandler
+	transfer       *handler.TransferHandler
+	currency       *handler.CurrencyHandler
+	exchangeRate   *handler.ExchangeRateHandler
+	preferences    *handler.PreferencesHandler
+	walletMember   *handler.WalletMemberHandler
 }
 
-func newHandlers(services *service.Manager, logger logger.Logger) Handlers {
+func newHandlers(services *service.Manager, cfg *config.Configuration, logger logger.Logger) Handlers {
 	return Handlers{
 		error:          error.NewErrorHandlingMiddleware(),
 		authentication: authentication.NewAuthenticationMiddleware(viper.GetString("JWT_SECRET"), logger),
+		idempotency:    idempotency.NewIdempotencyMiddleware(services, cfg.Idempotency, logger),
 		wallet:         handler.NewWalletHandler(services, logger),
+		transaction:    handler.NewTransactionHandler(services, logger),
+		transfer:       handler.NewTransferHandler(services, logger),
+		currency:       handler.NewCurrencyHandler(),
+		exchangeRate:   handler.NewExchangeRateHandler(services, logger),
+		preferences:    handler.NewPreferencesHandler(services, logger),
+		walletMember:   handler.NewWalletMemberHandler(services, logger),
 	}
 }
@@ -0,0 +1,27 @@
+package http
+
+import (
+	"github.com/khivuksergey/portmonetka.wallet/internal/core/port/service"
+	"github.com/labstack/echo/v4"
+)
+
+const headerAcceptLanguage = "Accept-Language"
+
+// preferredLanguage sets the Accept-Language header of requests without one to the locale from
+// the preferences of the user authorized by authorizeUser, so messages are translated into it.
+// Preferences which can't be loaded leave the default language rather than fail the request.
+func preferredLanguage(preferencesService service.PreferencesService) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			userId, ok := c.Get("userId").(uint64)
+			if !ok || c.Request().Header.Get(headerAcceptLanguage) != "" {
+				return next(c)
+			}
+			preferences, err := preferencesService.GetPreferences(c.Request().Context(), userId)
+			if err == nil && preferences.Locale != "" {
+				c.Request().Header.Set(headerAcceptLanguage, preferences.Locale)
+			}
+			return next(c)
+		}
+	}
+}
@@ -15,7 +15,8 @@ type Router struct {
 }
 
 func NewRouter(cfg *config.Configuration, services *service.Manager, logger logger.Logger) http.Handler {
-	handlers := newHandlers(services, logger)
+	handlers := newHandlers(services, cfg, logger)
+	language := preferredLanguage(services.Preferences)
 
 	e := router.NewEchoRouter().
 		WithConfig(cfg.Router).
@@ -23,11 +24,63 @@ func NewRouter(cfg *config.Configuration, services *service.Manager, logger logg
 		UseHealthCheck().
 		UseSwagger(docs.SwaggerInfo, cfg.Swagger)
 
-	wallets := e.Group("users/:userId/wallets", handlers.authentication.AuthenticateJWT)
+	e.GET("currencies", handlers.currency.GetCurrencies)
+
+	wallets := e.Group("users/:userId/wallets",
+		handlers.authentication.JWT,
+		authorizeUser,
+		language,
+		handlers.idempotency.HandleIdempotency,
+	)
 	wallets.GET("", handlers.wallet.GetWallets)
 	wallets.POST("", handlers.wallet.CreateWallet)
+	wallets.GET("/summary", handlers.wallet.GetWalletsSummary)
+	wallets.GET("/:walletId", handlers.wallet.GetWallet)
+	wallets.GET("/:walletId/history", handlers.wallet.GetWalletHistory)
 	wallets.DELETE("/:walletId", handlers.wallet.DeleteWallet)
 	wallets.PATCH("/:walletId", handlers.wallet.UpdateWallet)
 
+	trash := wallets.Group("/trash")
+	trash.GET("", handlers.wallet.GetDeletedWallets)
+	trash.POST("/:walletId/restore", handlers.wallet.RestoreWallet)
+	trash.DELETE("/:walletId", handlers.wallet.PurgeWallet)
+
+	transactions := wallets.Group("/:walletId/transactions")
+	transactions.GET("", handlers.transaction.GetTransactions)
+	transactions.POST("", handlers.transaction.CreateTransaction)
+	transactions.GET("/:transactionId", handlers.transaction.GetTransaction)
+	transactions.PATCH("/:transactionId", handlers.transaction.UpdateTransaction)
+	transactions.DELETE("/:transactionId", handlers.transaction.DeleteTransaction)
+
+	members := wallets.Group("/:walletId/members")
+	members.GET("", handlers.walletMember.GetWalletMembers)
+	members.POST("", handlers.walletMember.InviteWalletMember)
+	members.DELETE("/:memberId", handlers.walletMember.RemoveWalletMember)
+
+	invitations := e.Group("users/:userId/invitations",
+		handlers.authentication.JWT,
+		authorizeUser,
+		language,
+		handlers.idempotency.HandleIdempotency,
+	)
+	invitations.GET("", handlers.walletMember.GetInvitations)
+	invitations.POST("/:invitationId/accept", handlers.walletMember.AcceptInvitation)
+	invitations.DELETE("/:invitationId", handlers.walletMember.DeclineInvitation)
+
+	transfers := e.Group("users/:userId/transfers",
+		handlers.authentication.JWT,
+		authorizeUser,
+		language,
+		handlers.idempotency.HandleIdempotency,
+	)
+	transfers.POST("", handlers.transfer.CreateTransfer)
+
+	preferences := e.Group("users/:userId/preferences", handlers.authentication.JWT, authorizeUser, language)
+	preferences.GET("", handlers.preferences.GetPreferences)
+	preferences.PUT("", handlers.preferences.UpdatePreferences)
+
+	admin := e.Group("admin", handlers.authentication.JWT, requireRole(adminRole))
+	admin.POST("/exchange-rates", handlers.exchangeRate.ImportExchangeRates)
+
 	return e
 }
@@ -1,9 +1,13 @@
 package http
 
 import (
+	"context"
 	"github.com/khivuksergey/portmonetka.wallet/config"
+	eventpublisher "github.com/khivuksergey/portmonetka.wallet/internal/adapter/publisher"
 	"github.com/khivuksergey/portmonetka.wallet/internal/adapter/storage/gorm"
-	"github.com/khivuksergey/portmonetka.wallet/internal/core/service"
+	"github.com/khivuksergey/portmonetka.wallet/internal/core/port/service"
+	coreservice "github.com/khivuksergey/portmonetka.wallet/internal/core/service"
+	"github.com/khivuksergey/portmonetka.wallet/internal/worker"
 	"github.com/khivuksergey/webserver"
 	"github.com/khivuksergey/webserver/logger"
 )
@@ -11,23 +15,66 @@ import (
 const configPath = "config.json"
 
 func NewServer() webserver.Server {
-	config.LoadEnv()
-
 	cfg := config.LoadConfiguration(configPath)
 
+	config.LoadEnv(cfg.DB)
+
 	db := gorm.NewDbManager(cfg.DB)
 
-	services := service.NewServiceManager(db.InitRepositoryManager())
+	services := coreservice.NewServiceManager(db.InitRepositoryManager())
 
 	log := logger.Default.SetLevel(logger.GetLogLevelFromString(cfg.Logger.LogLevel))
 
+	importExchangeRates(services, cfg.ExchangeRates, log)
+
 	router := NewRouter(cfg, services, log)
 
+	trashPurger := worker.NewTrashPurger(services, cfg.Trash, log)
+	trashPurger.Start()
+
+	idempotencyKeyPurger := worker.NewIdempotencyKeyPurger(services, cfg.Idempotency, log)
+	idempotencyKeyPurger.Start()
+
+	publisher, err := eventpublisher.NewPublisher(cfg.Outbox, log)
+	if err != nil {
+		panic(err)
+	}
+	outboxRelay := worker.NewOutboxRelay(services, publisher, cfg.Outbox, log)
+	outboxRelay.Start()
+
 	server := webserver.
 		NewServer(router).
 		WithConfig(&cfg.Server).
 		AddLogger(log).
-		AddStopHandlers(webserver.NewStopHandler("Database", db.Close))
+		AddStopHandlers(
+			webserver.NewStopHandler("Trash purger", trashPurger.Stop),
+			webserver.NewStopHandler("Idempotency key purger", idempotencyKeyPurger.Stop),
+			webserver.NewStopHandler("Outbox relay", outboxRelay.Stop),
+			webserver.NewStopHandler("Event publisher", publisher.Close),
+			webserver.NewStopHandler("Database", db.Close),
+		)
 
 	return server
 }
+
+// importExchangeRates loads the configured exchange rates file, a failure doesn't stop the service,
+// as the rates can be imported later through the admin endpoint.
+func importExchangeRates(services *service.Manager, cfg config.ExchangeRatesConfig, log logger.Logger) {
+	if cfg.File == "" {
+		return
+	}
+	result, err := services.ExchangeRate.ImportExchangeRatesFromFile(context.Background(), cfg.File)
+	if err != nil {
+		log.Error(logger.LogMessage{
+			Action:  "ImportExchangeRates",
+			Message: "Cannot import exchange rates from " + cfg.File,
+			Data:    err.Error(),
+		})
+		return
+	}
+	log.Info(logger.LogMessage{
+		Action:  "ImportExchangeRates",
+		Message: "Exchange rates imported from " + cfg.File,
+		Data:    result,
+	})
+}
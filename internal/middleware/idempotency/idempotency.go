@@ -0,0 +1,148 @@
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"github.com/khivuksergey/portmonetka.common"
+	"github.com/khivuksergey/portmonetka.wallet/config"
+	serviceerror "github.com/khivuksergey/portmonetka.wallet/error"
+	"github.com/khivuksergey/portmonetka.wallet/internal/core/port/service"
+	"github.com/khivuksergey/portmonetka.wallet/internal/model"
+	"github.com/khivuksergey/webserver/logger"
+	"github.com/labstack/echo/v4"
+	"io"
+	"net/http"
+	"time"
+)
+
+const (
+	HeaderIdempotencyKey      = "Idempotency-Key"
+	HeaderIdempotentReplayed  = "Idempotent-Replayed"
+	idempotentReplayedMessage = "Idempotent response replayed"
+
+	defaultLeaseTimeout = time.Minute
+)
+
+type IdempotencyMiddleware struct {
+	idempotencyService service.IdempotencyService
+	cfg                config.IdempotencyConfig
+	logger             logger.Logger
+}
+
+func NewIdempotencyMiddleware(services *service.Manager, cfg config.IdempotencyConfig, logger logger.Logger) *IdempotencyMiddleware {
+	if cfg.LeaseTimeout <= 0 {
+		cfg.LeaseTimeout = defaultLeaseTimeout
+	}
+	return &IdempotencyMiddleware{
+		idempotencyService: services.Idempotency,
+		cfg:                cfg,
+		logger:             logger,
+	}
+}
+
+// HandleIdempotency makes POST requests carrying the Idempotency-Key header safe to retry.
+// The first successful response is stored and replayed for every request with the same key
+// and body, while reusing the key for a different request is rejected.
+// Must be used after authentication, as keys are scoped to the user from the context.
+func (i *IdempotencyMiddleware) HandleIdempotency(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		key := c.Request().Header.Get(HeaderIdempotencyKey)
+		if c.Request().Method != http.MethodPost || key == "" {
+			return next(c)
+		}
+
+		requestUuid := c.Get(common.RequestUuidKey).(string)
+		userId := c.Get("userId").(uint64)
+
+		body, err := io.ReadAll(c.Request().Body)
+		if err != nil {
+			return common.NewValidationError(serviceerror.InvalidInputData, err)
+		}
+		c.Request().Body = io.NopCloser(bytes.NewReader(body))
+
+		idempotencyKeyDTO := model.IdempotencyKeyDTO{
+			UserId:      userId,
+			Key:         key,
+			RequestHash: requestHash(c.Request(), body),
+		}
+
+		ctx := c.Request().Context()
+		stored, err := i.idempotencyService.BeginRequest(ctx, idempotencyKeyDTO, i.cfg.LeaseTimeout, i.cfg.TTL)
+		switch {
+		case errors.Is(err, serviceerror.IdempotencyKeyLengthError):
+			return common.NewValidationError(serviceerror.CannotProcessIdempotentRequest, err)
+		case errors.Is(err, serviceerror.IdempotencyKeyInProgress):
+			return echo.NewHTTPError(http.StatusConflict, serviceerror.CannotProcessIdempotentRequest+": "+err.Error())
+		case err != nil:
+			return common.NewUnprocessableEntityError(serviceerror.CannotProcessIdempotentRequest, err)
+		}
+
+		if stored != nil {
+			i.logger.Info(logger.LogMessage{
+				Action:      "HandleIdempotency",
+				Message:     idempotentReplayedMessage,
+				UserId:      &userId,
+				Data:        map[string]string{"key": key},
+				RequestUuid: requestUuid,
+			})
+			c.Response().Header().Set(HeaderIdempotentReplayed, "true")
+			return c.JSONBlob(stored.StatusCode, stored.Response)
+		}
+
+		recorder := &responseRecorder{ResponseWriter: c.Response().Writer}
+		c.Response().Writer = recorder
+
+		err = next(c)
+
+		// the key is released or completed even if the client has gone away meanwhile
+		ctx = context.WithoutCancel(ctx)
+		if status := c.Response().Status; err != nil || status < 200 || status >= 300 {
+			i.abort(ctx, idempotencyKeyDTO, requestUuid)
+			return err
+		}
+
+		if completeErr := i.idempotencyService.CompleteRequest(ctx, idempotencyKeyDTO, c.Response().Status, recorder.body.Bytes()); completeErr != nil {
+			i.logger.Error(logger.LogMessage{
+				Action:      "HandleIdempotency",
+				Message:     "Cannot store idempotent response",
+				UserId:      &userId,
+				Data:        completeErr.Error(),
+				RequestUuid: requestUuid,
+			})
+		}
+
+		return nil
+	}
+}
+
+func (i *IdempotencyMiddleware) abort(ctx context.Context, idempotencyKeyDTO model.IdempotencyKeyDTO, requestUuid string) {
+	if err := i.idempotencyService.AbortRequest(ctx, idempotencyKeyDTO); err != nil {
+		i.logger.Error(logger.LogMessage{
+			Action:      "HandleIdempotency",
+			Message:     "Cannot release idempotency key",
+			UserId:      &idempotencyKeyDTO.UserId,
+			Data:        err.Error(),
+			RequestUuid: requestUuid,
+		})
+	}
+}
+
+func requestHash(r *http.Request, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(r.Method + " " + r.URL.Path + "\n"))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+type responseRecorder struct {
+	http.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
@@ -1,25 +1,134 @@
 package model
 
-import "github.com/shopspring/decimal"
+import (
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+const (
+	ExchangeRateFormatXML = "xml"
+	ExchangeRateFormatCSV = "csv"
+
+	DefaultLocale        = "en"
+	DefaultMonthStartDay = 1
+)
 
 type WalletCreateDTO struct {
-	UserId        uint64          `json:"userId"`
-	Name          string          `json:"name" validate:"required"`
-	Description   string          `json:"description"`
-	Currency      string          `json:"currency" validate:"required,len=3"`
-	InitialAmount decimal.Decimal `json:"initialAmount"`
+	UserId        uint64           `json:"userId"`
+	Name          string           `json:"name" validate:"required,min=3,max=128"`
+	Description   string           `json:"description" validate:"max=256"`
+	Currency      string           `json:"currency" validate:"required,currency"`
+	InitialAmount decimal.Decimal  `json:"initialAmount"`
+	Type          string           `json:"type" validate:"omitempty,oneof=cash debit_card credit_card savings loan investment crypto"`
+	CreditLimit   *decimal.Decimal `json:"creditLimit"`
+	StatementDay  *int             `json:"statementDay" validate:"omitnil,min=1,max=31"`
+	InterestRate  *decimal.Decimal `json:"interestRate"`
+	AllowNegative bool             `json:"allowNegative"`
 }
 
+// WalletUpdateDTO changes the given fields of the wallet. Versions are the versions from If-Match,
+// one of which the wallet must have to be updated; empty Versions don't restrict it.
 type WalletUpdateDTO struct {
 	Id            uint64           `json:"id"`
 	UserId        uint64           `json:"userId"`
-	Name          *string          `json:"name"`
-	Description   *string          `json:"description"`
-	Currency      *string          `json:"currency"`
+	Name          *string          `json:"name" validate:"omitnil,min=3,max=128"`
+	Description   *string          `json:"description" validate:"omitnil,max=256"`
+	Currency      *string          `json:"currency" validate:"omitempty,currency"`
 	InitialAmount *decimal.Decimal `json:"initialAmount"`
+	Type          *string          `json:"type" validate:"omitnil,oneof=cash debit_card credit_card savings loan investment crypto"`
+	CreditLimit   *decimal.Decimal `json:"creditLimit"`
+	StatementDay  *int             `json:"statementDay" validate:"omitnil,min=1,max=31"`
+	InterestRate  *decimal.Decimal `json:"interestRate"`
+	AllowNegative *bool            `json:"allowNegative"`
+	Versions      []uint64         `json:"-"`
 }
 
+// WalletDeleteDTO moves the wallet to the trash, checking its version as WalletUpdateDTO does.
 type WalletDeleteDTO struct {
+	Id       uint64   `json:"id"`
+	UserId   uint64   `json:"userId"`
+	Versions []uint64 `json:"-"`
+}
+
+type WalletTrashDTO struct {
 	Id     uint64 `json:"id"`
 	UserId uint64 `json:"userId"`
 }
+
+type TransactionCreateDTO struct {
+	UserId    uint64          `json:"userId"`
+	WalletId  uint64          `json:"walletId"`
+	Amount    decimal.Decimal `json:"amount" validate:"required"`
+	Direction string          `json:"direction" validate:"required,oneof=in out"`
+	Timestamp time.Time       `json:"timestamp"`
+	Note      string          `json:"note" validate:"max=256"`
+	Category  string          `json:"category" validate:"max=64"`
+}
+
+type TransactionUpdateDTO struct {
+	Id        uint64           `json:"id"`
+	UserId    uint64           `json:"userId"`
+	WalletId  uint64           `json:"walletId"`
+	Amount    *decimal.Decimal `json:"amount"`
+	Direction *string          `json:"direction" validate:"omitnil,oneof=in out"`
+	Timestamp *time.Time       `json:"timestamp"`
+	Note      *string          `json:"note" validate:"omitnil,max=256"`
+	Category  *string          `json:"category" validate:"omitnil,max=64"`
+}
+
+type TransactionDeleteDTO struct {
+	Id       uint64 `json:"id"`
+	UserId   uint64 `json:"userId"`
+	WalletId uint64 `json:"walletId"`
+}
+
+// TransferCreateDTO moves funds between user's wallets. SourceWalletId defaults to the default wallet
+// from user's preferences.
+type TransferCreateDTO struct {
+	UserId         uint64           `json:"userId"`
+	SourceWalletId uint64           `json:"sourceWalletId"`
+	TargetWalletId uint64           `json:"targetWalletId" validate:"required,nefield=SourceWalletId"`
+	Amount         decimal.Decimal  `json:"amount" validate:"required"`
+	TargetAmount   *decimal.Decimal `json:"targetAmount"`
+	Rate           *decimal.Decimal `json:"rate"`
+	Timestamp      time.Time        `json:"timestamp"`
+	Note           string           `json:"note" validate:"max=256"`
+}
+
+// PreferencesUpdateDTO replaces user's preferences, omitted fields are reset to defaults.
+// BaseCurrency is the default base of the wallets summary, DefaultWalletId the default source
+// wallet of transfers, Locale the language of messages for requests without Accept-Language
+// and MonthStartDay the first day of the month for reporting.
+type PreferencesUpdateDTO struct {
+	UserId          uint64  `json:"userId"`
+	BaseCurrency    string  `json:"baseCurrency" validate:"omitempty,currency"`
+	DefaultWalletId *uint64 `json:"defaultWalletId"`
+	Locale          string  `json:"locale" validate:"omitempty,bcp47_language_tag"`
+	MonthStartDay   int     `json:"monthStartDay" validate:"omitempty,min=1,max=28"`
+}
+
+// WalletMemberInviteDTO invites another user to the wallet with the given role. The owner role
+// stays with the creator, as only they can restore and purge the wallet after it is deleted.
+type WalletMemberInviteDTO struct {
+	UserId    uint64 `json:"userId"`
+	WalletId  uint64 `json:"walletId"`
+	InviteeId uint64 `json:"inviteeId" validate:"required"`
+	Role      string `json:"role" validate:"required,oneof=editor viewer"`
+}
+
+type WalletMemberRemoveDTO struct {
+	Id       uint64 `json:"id"`
+	UserId   uint64 `json:"userId"`
+	WalletId uint64 `json:"walletId"`
+}
+
+type InvitationDTO struct {
+	Id     uint64 `json:"id"`
+	UserId uint64 `json:"userId"`
+}
+
+type IdempotencyKeyDTO struct {
+	UserId      uint64
+	Key         string
+	RequestHash string
+}
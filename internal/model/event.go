@@ -0,0 +1,12 @@
+package model
+
+import "github.com/khivuksergey/portmonetka.wallet/internal/adapter/storage/entity"
+
+// WalletEvent is the payload of wallet domain events. Wallet is its state after the change or,
+// for deleted wallets, before it. Changes lists the changed fields of updated wallets.
+type WalletEvent struct {
+	Wallet      entity.Wallet                 `json:"wallet"`
+	Changes     map[string]entity.FieldChange `json:"changes,omitempty"`
+	ActorId     uint64                        `json:"actorId"`
+	RequestUuid string                        `json:"requestUuid,omitempty"`
+}
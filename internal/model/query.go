@@ -0,0 +1,76 @@
+package model
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+const (
+	WalletSortName      = "name"
+	WalletSortCreatedAt = "createdAt"
+	WalletSortUpdatedAt = "updatedAt"
+	WalletSortBalance   = "balance"
+
+	DefaultWalletSort      = "-" + WalletSortUpdatedAt
+	DefaultWalletListLimit = 50
+)
+
+var invalidCursor = errors.New("invalid cursor")
+
+// WalletSummaryQuery selects the currency wallet balances are converted to and the date of rates.
+// The base currency defaults to the one from user's preferences.
+type WalletSummaryQuery struct {
+	BaseCurrency string `query:"base" validate:"omitempty,currency"`
+	Date         string `query:"date" validate:"omitempty,datetime=2006-01-02"`
+}
+
+type WalletListQuery struct {
+	Currency    string     `query:"currency" validate:"omitempty,currency"`
+	Type        string     `query:"type" validate:"omitempty,oneof=cash debit_card credit_card savings loan investment crypto"`
+	Name        string     `query:"name" validate:"max=128"`
+	CreatedFrom *time.Time `query:"createdFrom"`
+	CreatedTo   *time.Time `query:"createdTo"`
+	UpdatedFrom *time.Time `query:"updatedFrom"`
+	UpdatedTo   *time.Time `query:"updatedTo"`
+	Sort        string     `query:"sort" validate:"omitempty,oneof=name -name createdAt -createdAt updatedAt -updatedAt balance -balance"`
+	Limit       int        `query:"limit" validate:"omitempty,min=1,max=100"`
+	Cursor      string     `query:"cursor"`
+}
+
+// SortField returns the field the wallets are sorted by and whether the order is descending.
+// Sort "-name" means descending order by name.
+func (q WalletListQuery) SortField() (field string, desc bool) {
+	sort := q.Sort
+	if sort == "" {
+		sort = DefaultWalletSort
+	}
+	return strings.TrimPrefix(sort, "-"), strings.HasPrefix(sort, "-")
+}
+
+// WalletCursor points at the last wallet of the page: the value of the sort field and the wallet id
+// as a tiebreaker. Sort is stored to reject cursors used with a different sort order.
+type WalletCursor struct {
+	Sort  string `json:"s"`
+	Value string `json:"v"`
+	Id    uint64 `json:"id"`
+}
+
+func (c WalletCursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func DecodeWalletCursor(cursor string) (*WalletCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, invalidCursor
+	}
+	walletCursor := &WalletCursor{}
+	if err = json.Unmarshal(data, walletCursor); err != nil {
+		return nil, invalidCursor
+	}
+	return walletCursor, nil
+}
@@ -0,0 +1,23 @@
+package model
+
+import "context"
+
+// RequestInfo identifies the request a service call is made in and the user making it,
+// who is either the user acted on or a user with a role acting on their behalf.
+type RequestInfo struct {
+	ActorId     uint64
+	RequestUuid string
+}
+
+type requestInfoKey struct{}
+
+// ContextWithRequestInfo returns a copy of the context carrying the request info.
+func ContextWithRequestInfo(ctx context.Context, requestInfo RequestInfo) context.Context {
+	return context.WithValue(ctx, requestInfoKey{}, requestInfo)
+}
+
+// RequestInfoFromContext returns the request info of the context, if any.
+func RequestInfoFromContext(ctx context.Context) (RequestInfo, bool) {
+	requestInfo, ok := ctx.Value(requestInfoKey{}).(RequestInfo)
+	return requestInfo, ok
+}
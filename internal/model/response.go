@@ -1,7 +1,65 @@
 package model
 
+import (
+	"github.com/khivuksergey/portmonetka.wallet/internal/adapter/storage/entity"
+	"github.com/shopspring/decimal"
+	"time"
+)
+
 type Response struct {
 	Message     string `json:"message"`
+	Code        string `json:"code,omitempty"`
 	Data        any    `json:"data"`
 	RequestUuid string `json:"request_uuid"`
+	NextCursor  string `json:"nextCursor,omitempty"`
+}
+
+// Problem is an RFC 7807 problem details body, rendered as application/problem+json.
+// Code is the stable machine-readable error code and Field is the invalid input field, if any.
+// Errors lists the translated messages of all invalid input fields.
+type Problem struct {
+	Type        string       `json:"type"`
+	Title       string       `json:"title"`
+	Status      int          `json:"status"`
+	Detail      string       `json:"detail"`
+	Instance    string       `json:"instance"`
+	Code        string       `json:"code"`
+	Field       string       `json:"field,omitempty"`
+	Errors      []FieldError `json:"errors,omitempty"`
+	RequestUuid string       `json:"request_uuid"`
+}
+
+type DeletedWallet struct {
+	entity.Wallet
+	DeletedAt time.Time `json:"deletedAt"`
+}
+
+func NewDeletedWallets(wallets []entity.Wallet) []DeletedWallet {
+	deletedWallets := make([]DeletedWallet, len(wallets))
+	for i, wallet := range wallets {
+		deletedWallets[i] = DeletedWallet{Wallet: wallet, DeletedAt: wallet.DeletedAt.Time}
+	}
+	return deletedWallets
+}
+
+// WalletsSummary is the user's net worth: all wallet balances converted to the base currency.
+type WalletsSummary struct {
+	BaseCurrency string          `json:"baseCurrency"`
+	Date         string          `json:"date"`
+	Total        decimal.Decimal `json:"total"`
+	Wallets      []WalletBalance `json:"wallets"`
+}
+
+type WalletBalance struct {
+	WalletId         uint64          `json:"walletId"`
+	Name             string          `json:"name"`
+	Currency         string          `json:"currency"`
+	Balance          decimal.Decimal `json:"balance"`
+	Rate             decimal.Decimal `json:"rate"`
+	ConvertedBalance decimal.Decimal `json:"convertedBalance"`
+}
+
+type ExchangeRateImportResult struct {
+	Imported int `json:"imported"`
+	Skipped  int `json:"skipped"`
 }
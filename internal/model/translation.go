@@ -0,0 +1,111 @@
+package model
+
+import (
+	"errors"
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/ru"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	entranslations "github.com/go-playground/validator/v10/translations/en"
+	rutranslations "github.com/go-playground/validator/v10/translations/ru"
+	"golang.org/x/text/language"
+	"strings"
+)
+
+var (
+	universalTranslator = ut.New(en.New(), en.New(), ru.New())
+	languageMatcher     = language.NewMatcher([]language.Tag{language.English, language.Russian})
+)
+
+// customTranslations are messages of the tags missing from the validator default translations, by locale.
+var customTranslations = map[string]map[string]string{
+	"en": {
+		"currency":           "{0} must be a supported currency code",
+		"bcp47_language_tag": "{0} must be a valid BCP 47 language tag",
+		atLeastOneFieldTag:   "at least one field must be provided",
+	},
+	"ru": {
+		"currency":           "{0} должен быть кодом поддерживаемой валюты",
+		"bcp47_language_tag": "{0} должен быть языковым тегом BCP 47",
+		"datetime":           "{0} не соответствует формату {1}",
+		atLeastOneFieldTag:   "необходимо указать хотя бы одно поле",
+	},
+}
+
+// FieldError is the validation failure of a request field with the message in the client's language.
+// Field is empty for failures of the request as a whole.
+type FieldError struct {
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+// InvalidFieldsError lists translated validation failures of a request.
+type InvalidFieldsError struct {
+	Fields []FieldError
+}
+
+func (e *InvalidFieldsError) Error() string {
+	messages := make([]string, len(e.Fields))
+	for i, field := range e.Fields {
+		messages[i] = field.Message
+	}
+	return strings.Join(messages, "; ")
+}
+
+// TranslateValidationError turns validator errors into InvalidFieldsError with messages in the language
+// best matching the Accept-Language header, English by default. Other errors are returned as is.
+func TranslateValidationError(err error, acceptLanguage string) error {
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		return err
+	}
+	translator := Translator(acceptLanguage)
+	fields := make([]FieldError, len(validationErrors))
+	for i, fieldError := range validationErrors {
+		fields[i] = FieldError{Field: fieldError.Field(), Message: fieldError.Translate(translator)}
+	}
+	return &InvalidFieldsError{Fields: fields}
+}
+
+// Translator returns the translator of the supported language best matching the Accept-Language header.
+func Translator(acceptLanguage string) ut.Translator {
+	tag, _ := language.MatchStrings(languageMatcher, acceptLanguage)
+	base, _ := tag.Base()
+	translator, _ := universalTranslator.GetTranslator(base.String())
+	return translator
+}
+
+func registerTranslations(v *validator.Validate) error {
+	enTranslator, _ := universalTranslator.GetTranslator("en")
+	if err := entranslations.RegisterDefaultTranslations(v, enTranslator); err != nil {
+		return err
+	}
+	ruTranslator, _ := universalTranslator.GetTranslator("ru")
+	if err := rutranslations.RegisterDefaultTranslations(v, ruTranslator); err != nil {
+		return err
+	}
+	for locale, translations := range customTranslations {
+		translator, _ := universalTranslator.GetTranslator(locale)
+		for tag, message := range translations {
+			err := v.RegisterTranslation(tag, translator, registerMessage(tag, message), translateMessage)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func registerMessage(tag, message string) validator.RegisterTranslationsFunc {
+	return func(translator ut.Translator) error {
+		return translator.Add(tag, message, false)
+	}
+}
+
+func translateMessage(translator ut.Translator, fieldError validator.FieldError) string {
+	message, err := translator.T(fieldError.Tag(), fieldError.Field(), fieldError.Param())
+	if err != nil {
+		return fieldError.Error()
+	}
+	return message
+}
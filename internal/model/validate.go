@@ -2,28 +2,59 @@ package model
 
 import (
 	"github.com/go-playground/validator/v10"
+	"github.com/khivuksergey/portmonetka.wallet/internal/currency"
+	"reflect"
+	"strings"
+	"sync"
 )
 
-func GetWalletValidator() *validator.Validate {
-	v := validator.New(validator.WithRequiredStructEnabled())
-	//TODO Add custom validation messages
-	//v.RegisterStructValidation(validateWalletUpdate, WalletUpdateDTO{})
+const atLeastOneFieldTag = "atleastonefield"
 
-	//// Add custom message for the validation error
-	//v.RegisterTranslation("atleastonefieldrequired", en.Translations, func(ut validator.Translator) error {
-	//	return ut.Add("atleastonefieldrequired", "At least one field must be provided", true)
-	//}, func(ut validator.Translator, fe validator.FieldError) string {
-	//	t, _ := ut.T("atleastonefieldrequired", fe.Field())
-	//	return t
-	//})
+// GetWalletValidator returns the validator of request DTOs with English and Russian messages registered.
+// The validator is shared, as translations can be registered only once.
+var GetWalletValidator = sync.OnceValue(newWalletValidator)
 
+func newWalletValidator() *validator.Validate {
+	v := validator.New(validator.WithRequiredStructEnabled())
+	v.RegisterTagNameFunc(fieldName)
+	_ = v.RegisterValidation("currency", validateCurrency)
+	v.RegisterStructValidation(validateWalletUpdate, WalletUpdateDTO{})
+	if err := registerTranslations(v); err != nil {
+		panic(err)
+	}
 	return v
 }
 
-//func validateWalletUpdate(sl validator.StructLevel) {
-//	wallet := sl.Current().Interface().(WalletUpdateDTO)
-//
-//	if wallet.Name == nil && wallet.Description == nil && wallet.Currency == nil && wallet.InitialAmount == nil {
-//		sl.ReportError(wallet, "WalletUpdateDTO", "", "atleastonefieldrequired", "")
-//	}
-//}
+// validateWalletUpdate requires at least one of the updatable wallet fields.
+func validateWalletUpdate(sl validator.StructLevel) {
+	wallet := sl.Current().Interface().(WalletUpdateDTO)
+
+	if wallet.Name == nil &&
+		wallet.Description == nil &&
+		wallet.Currency == nil &&
+		wallet.InitialAmount == nil &&
+		wallet.Type == nil &&
+		wallet.CreditLimit == nil &&
+		wallet.StatementDay == nil &&
+		wallet.InterestRate == nil &&
+		wallet.AllowNegative == nil {
+		sl.ReportError(wallet, "", "", atLeastOneFieldTag, "")
+	}
+}
+
+// validateCurrency accepts ISO 4217 alphabetic codes and cryptocurrency codes known to the currency registry, case-insensitively.
+func validateCurrency(fl validator.FieldLevel) bool {
+	return currency.IsValid(fl.Field().String())
+}
+
+// fieldName reports validation errors by the name of the field in JSON or query parameters,
+// which is the one clients know, rather than by the Go struct field name.
+func fieldName(field reflect.StructField) string {
+	for _, tag := range []string{"json", "query"} {
+		name, _, _ := strings.Cut(field.Tag.Get(tag), ",")
+		if name != "" && name != "-" {
+			return name
+		}
+	}
+	return field.Name
+}
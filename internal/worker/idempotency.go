@@ -0,0 +1,80 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"github.com/khivuksergey/portmonetka.wallet/config"
+	"github.com/khivuksergey/portmonetka.wallet/internal/core/port/service"
+	"github.com/khivuksergey/webserver/logger"
+	"time"
+)
+
+// IdempotencyKeyPurger periodically deletes idempotency keys completed longer than their TTL ago.
+type IdempotencyKeyPurger struct {
+	idempotencyService service.IdempotencyService
+	cfg                config.IdempotencyConfig
+	logger             logger.Logger
+	ctx                context.Context
+	cancel             context.CancelFunc
+	done               chan struct{}
+}
+
+func NewIdempotencyKeyPurger(services *service.Manager, cfg config.IdempotencyConfig, logger logger.Logger) *IdempotencyKeyPurger {
+	if cfg.PurgeInterval <= 0 {
+		cfg.PurgeInterval = defaultPurgeInterval
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &IdempotencyKeyPurger{
+		idempotencyService: services.Idempotency,
+		cfg:                cfg,
+		logger:             logger,
+		ctx:                ctx,
+		cancel:             cancel,
+		done:               make(chan struct{}),
+	}
+}
+
+// Start runs the purge loop in background. Zero TTL disables purging.
+func (p *IdempotencyKeyPurger) Start() {
+	if p.cfg.TTL <= 0 {
+		close(p.done)
+		return
+	}
+	go func() {
+		defer close(p.done)
+		ticker := time.NewTicker(p.cfg.PurgeInterval)
+		defer ticker.Stop()
+		for {
+			p.purge()
+			select {
+			case <-ticker.C:
+			case <-p.ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// Stop aborts the running purge and waits for the loop to exit.
+func (p *IdempotencyKeyPurger) Stop() error {
+	p.cancel()
+	<-p.done
+	return nil
+}
+
+func (p *IdempotencyKeyPurger) purge() {
+	deleted, err := p.idempotencyService.DeleteExpiredKeys(p.ctx, time.Now().Add(-p.cfg.TTL))
+	if err != nil {
+		p.logger.Error(logger.LogMessage{
+			Action:  "DeleteExpiredKeys",
+			Message: fmt.Sprintf("Error deleting expired idempotency keys: %v", err),
+		})
+		return
+	}
+	if deleted > 0 {
+		p.logger.Info(logger.LogMessage{
+			Action:  "DeleteExpiredKeys",
+			Message: fmt.Sprintf("Deleted %d expired idempotency keys", deleted),
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"github.com/khivuksergey/portmonetka.wallet/config"
+	"github.com/khivuksergey/portmonetka.wallet/internal/core/port/event"
+	"github.com/khivuksergey/portmonetka.wallet/internal/core/port/service"
+	"github.com/khivuksergey/webserver/logger"
+	"time"
+)
+
+const (
+	defaultRelayInterval   = time.Second
+	defaultRelayBatch      = 100
+	defaultClaimTimeout    = time.Minute
+	defaultCleanupInterval = time.Hour
+)
+
+// OutboxRelay periodically publishes domain events written to the outbox
+// and deletes the ones published longer than the retention period ago.
+type OutboxRelay struct {
+	outboxService service.OutboxService
+	publisher     event.Publisher
+	cfg           config.OutboxConfig
+	logger        logger.Logger
+	ctx           context.Context
+	cancel        context.CancelFunc
+	done          chan struct{}
+}
+
+func NewOutboxRelay(services *service.Manager, publisher event.Publisher, cfg config.OutboxConfig, logger logger.Logger) *OutboxRelay {
+	if cfg.RelayInterval <= 0 {
+		cfg.RelayInterval = defaultRelayInterval
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = defaultRelayBatch
+	}
+	if cfg.ClaimTimeout <= 0 {
+		cfg.ClaimTimeout = defaultClaimTimeout
+	}
+	if cfg.CleanupInterval <= 0 {
+		cfg.CleanupInterval = defaultCleanupInterval
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &OutboxRelay{
+		outboxService: services.Outbox,
+		publisher:     publisher,
+		cfg:           cfg,
+		logger:        logger,
+		ctx:           ctx,
+		cancel:        cancel,
+		done:          make(chan struct{}),
+	}
+}
+
+// Start runs the relay loop in background. Zero retention period disables the cleanup.
+func (r *OutboxRelay) Start() {
+	go func() {
+		defer close(r.done)
+		ticker := time.NewTicker(r.cfg.RelayInterval)
+		defer ticker.Stop()
+		var cleanup <-chan time.Time
+		if r.cfg.RetentionPeriod > 0 {
+			cleanupTicker := time.NewTicker(r.cfg.CleanupInterval)
+			defer cleanupTicker.Stop()
+			cleanup = cleanupTicker.C
+			r.cleanup()
+		}
+		for {
+			r.relay()
+			select {
+			case <-ticker.C:
+			case <-cleanup:
+				r.cleanup()
+			case <-r.ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// Stop aborts the running relay and waits for the loop to exit.
+func (r *OutboxRelay) Stop() error {
+	r.cancel()
+	<-r.done
+	return nil
+}
+
+// relay publishes batches of events until the outbox is drained or publishing fails.
+func (r *OutboxRelay) relay() {
+	for r.ctx.Err() == nil {
+		relayed, err := r.outboxService.RelayEvents(r.ctx, r.publisher, r.cfg.BatchSize, r.cfg.ClaimTimeout)
+		if err != nil {
+			r.logger.Error(logger.LogMessage{
+				Action:  "RelayEvents",
+				Message: fmt.Sprintf("Error relaying events after %d published: %v", relayed, err),
+			})
+			return
+		}
+		if relayed > 0 {
+			r.logger.Debug(logger.LogMessage{
+				Action:  "RelayEvents",
+				Message: fmt.Sprintf("Relayed %d events", relayed),
+			})
+		}
+		if relayed < r.cfg.BatchSize {
+			return
+		}
+	}
+}
+
+func (r *OutboxRelay) cleanup() {
+	deleted, err := r.outboxService.DeletePublishedEvents(r.ctx, time.Now().Add(-r.cfg.RetentionPeriod))
+	if err != nil {
+		r.logger.Error(logger.LogMessage{
+			Action:  "DeletePublishedEvents",
+			Message: fmt.Sprintf("Error deleting published events: %v", err),
+		})
+		return
+	}
+	if deleted > 0 {
+		r.logger.Info(logger.LogMessage{
+			Action:  "DeletePublishedEvents",
+			Message: fmt.Sprintf("Deleted %d published events", deleted),
+		})
+	}
+}
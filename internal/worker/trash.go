@@ -0,0 +1,82 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"github.com/khivuksergey/portmonetka.wallet/config"
+	"github.com/khivuksergey/portmonetka.wallet/internal/core/port/service"
+	"github.com/khivuksergey/webserver/logger"
+	"time"
+)
+
+const defaultPurgeInterval = time.Hour
+
+// TrashPurger periodically hard-deletes wallets which stayed in the trash longer than the retention period.
+type TrashPurger struct {
+	walletService service.WalletService
+	cfg           config.TrashConfig
+	logger        logger.Logger
+	ctx           context.Context
+	cancel        context.CancelFunc
+	done          chan struct{}
+}
+
+func NewTrashPurger(services *service.Manager, cfg config.TrashConfig, logger logger.Logger) *TrashPurger {
+	if cfg.PurgeInterval <= 0 {
+		cfg.PurgeInterval = defaultPurgeInterval
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &TrashPurger{
+		walletService: services.Wallet,
+		cfg:           cfg,
+		logger:        logger,
+		ctx:           ctx,
+		cancel:        cancel,
+		done:          make(chan struct{}),
+	}
+}
+
+// Start runs the purge loop in background. Zero retention period disables purging.
+func (p *TrashPurger) Start() {
+	if p.cfg.RetentionPeriod <= 0 {
+		close(p.done)
+		return
+	}
+	go func() {
+		defer close(p.done)
+		ticker := time.NewTicker(p.cfg.PurgeInterval)
+		defer ticker.Stop()
+		for {
+			p.purge()
+			select {
+			case <-ticker.C:
+			case <-p.ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// Stop aborts the running purge and waits for the loop to exit.
+func (p *TrashPurger) Stop() error {
+	p.cancel()
+	<-p.done
+	return nil
+}
+
+func (p *TrashPurger) purge() {
+	purged, err := p.walletService.PurgeExpiredWallets(p.ctx, time.Now().Add(-p.cfg.RetentionPeriod))
+	if err != nil {
+		p.logger.Error(logger.LogMessage{
+			Action:  "PurgeExpiredWallets",
+			Message: fmt.Sprintf("Error purging expired wallets: %v", err),
+		})
+		return
+	}
+	if purged > 0 {
+		p.logger.Info(logger.LogMessage{
+			Action:  "PurgeExpiredWallets",
+			Message: fmt.Sprintf("Purged %d expired wallets", purged),
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package publisher
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"github.com/khivuksergey/portmonetka.wallet/config"
+	serviceerror "github.com/khivuksergey/portmonetka.wallet/error"
+	"github.com/khivuksergey/portmonetka.wallet/internal/adapter/publisher"
+	"github.com/khivuksergey/portmonetka.wallet/internal/adapter/storage/entity"
+	"github.com/khivuksergey/webserver/logger"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFilePublisher_AppendsNDJSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.ndjson")
+	outboxEvents := []entity.OutboxEvent{
+		{Id: 1, Type: entity.WalletCreatedEvent, AggregateId: 7, Payload: json.RawMessage(`{"wallet":{"name":"Cash"}}`)},
+		{Id: 2, Type: entity.WalletDeletedEvent, AggregateId: 7, Payload: json.RawMessage(`{"wallet":{"name":"Cash"}}`)},
+	}
+
+	for _, outboxEvent := range outboxEvents {
+		filePublisher, err := publisher.NewPublisher(config.OutboxConfig{Publisher: publisher.PublisherFile, File: path}, logger.Default)
+		assert.NoError(t, err)
+		assert.NoError(t, filePublisher.Publish(context.Background(), outboxEvent))
+		assert.NoError(t, filePublisher.Close())
+	}
+
+	file, err := os.Open(path)
+	assert.NoError(t, err)
+	defer file.Close()
+	var published []entity.OutboxEvent
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var outboxEvent entity.OutboxEvent
+		assert.NoError(t, json.Unmarshal(scanner.Bytes(), &outboxEvent))
+		published = append(published, outboxEvent)
+	}
+	assert.Equal(t, outboxEvents, published)
+}
+
+func TestFilePublisher_CancelledContext(t *testing.T) {
+	filePublisher, err := publisher.NewFilePublisher(filepath.Join(t.TempDir(), "events.ndjson"))
+	assert.NoError(t, err)
+	defer filePublisher.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, filePublisher.Publish(ctx, entity.OutboxEvent{Id: 1}), context.Canceled)
+}
+
+func TestNewPublisher_UnknownPublisher(t *testing.T) {
+	_, err := publisher.NewPublisher(config.OutboxConfig{Publisher: "kafka"}, logger.Default)
+	assert.ErrorIs(t, err, serviceerror.UnknownEventPublisher)
+
+	logPublisher, err := publisher.NewPublisher(config.OutboxConfig{}, logger.Default)
+	assert.NoError(t, err)
+	assert.NoError(t, logPublisher.Publish(context.Background(), entity.OutboxEvent{Id: 1, Type: entity.WalletCreatedEvent}))
+}
@@ -0,0 +1,87 @@
+package gorm
+
+import (
+	"context"
+	"github.com/khivuksergey/portmonetka.wallet/config"
+	"github.com/khivuksergey/portmonetka.wallet/internal/adapter/storage/entity"
+	"github.com/khivuksergey/portmonetka.wallet/internal/adapter/storage/gorm"
+	"github.com/khivuksergey/portmonetka.wallet/internal/adapter/storage/gorm/migration"
+	"github.com/khivuksergey/portmonetka.wallet/internal/core/port/repository"
+	"github.com/stretchr/testify/assert"
+	gormlib "gorm.io/gorm"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestIdempotency_ReserveStaleKey(t *testing.T) {
+	db := gorm.NewDbManager(config.DBConfig{Driver: gorm.DriverSqlite, MigrationMode: migration.ModeAuto})
+	defer db.Close()
+	repositories := db.InitRepositoryManager()
+	ctx := context.Background()
+
+	idempotencyKey := &entity.IdempotencyKey{UserId: 1, Key: "key", RequestHash: "hash", ReservedAt: time.Now().Add(-time.Hour).UTC()}
+	if !assert.NoError(t, repositories.Idempotency.CreateIdempotencyKey(ctx, idempotencyKey)) {
+		return
+	}
+
+	reserved, err := repositories.Idempotency.ReserveStaleIdempotencyKey(ctx, idempotencyKey.Id, "retry", time.Now().Add(-2*time.Hour), time.Time{})
+	assert.NoError(t, err)
+	assert.False(t, reserved, "keys pending within the lease must not be taken over")
+
+	reserved, err = repositories.Idempotency.ReserveStaleIdempotencyKey(ctx, idempotencyKey.Id, "retry", time.Now().Add(-time.Minute), time.Time{})
+	assert.NoError(t, err)
+	assert.True(t, reserved)
+	reserved, err = repositories.Idempotency.ReserveStaleIdempotencyKey(ctx, idempotencyKey.Id, "another retry", time.Now().Add(-time.Minute), time.Time{})
+	assert.NoError(t, err)
+	assert.False(t, reserved, "a key must be taken over only once")
+
+	stored, err := repositories.Idempotency.GetIdempotencyKey(ctx, 1, "key")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "retry", stored.RequestHash)
+		assert.True(t, stored.IsPending())
+		assert.WithinDuration(t, time.Now(), stored.ReservedAt, time.Minute)
+	}
+}
+
+func TestIdempotency_DeleteCompletedKeys(t *testing.T) {
+	db := gorm.NewDbManager(config.DBConfig{Driver: gorm.DriverSqlite, MigrationMode: migration.ModeAuto})
+	defer db.Close()
+	repositories := db.InitRepositoryManager()
+	ctx := context.Background()
+
+	for _, key := range []string{"pending", "completed"} {
+		assert.NoError(t, repositories.Idempotency.CreateIdempotencyKey(ctx, &entity.IdempotencyKey{UserId: 1, Key: key, RequestHash: "hash", ReservedAt: time.Now().UTC()}))
+	}
+	completed, err := repositories.Idempotency.GetIdempotencyKey(ctx, 1, "completed")
+	if !assert.NoError(t, err) {
+		return
+	}
+	completed.StatusCode = http.StatusCreated
+	assert.NoError(t, repositories.Idempotency.UpdateIdempotencyKey(ctx, completed))
+
+	deleted, err := repositories.Idempotency.DeleteIdempotencyKeysCompletedBefore(ctx, time.Now().Add(-time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), deleted)
+
+	deleted, err = repositories.Idempotency.DeleteIdempotencyKeysCompletedBefore(ctx, time.Now().Add(time.Minute))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+	_, err = repositories.Idempotency.GetIdempotencyKey(ctx, 1, "pending")
+	assert.NoError(t, err, "pending keys must be kept")
+}
+
+func TestIdempotency_RepositoryErrors(t *testing.T) {
+	db := gorm.NewDbManager(config.DBConfig{Driver: gorm.DriverSqlite, MigrationMode: migration.ModeAuto})
+	defer db.Close()
+	repositories := db.InitRepositoryManager()
+	ctx := context.Background()
+
+	_, err := repositories.Idempotency.GetIdempotencyKey(ctx, 1, "key")
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+	assert.ErrorIs(t, err, gormlib.ErrRecordNotFound, "the original error must be kept for the repositories")
+
+	assert.NoError(t, repositories.Idempotency.CreateIdempotencyKey(ctx, &entity.IdempotencyKey{UserId: 1, Key: "key", ReservedAt: time.Now().UTC()}))
+	err = repositories.Idempotency.CreateIdempotencyKey(ctx, &entity.IdempotencyKey{UserId: 1, Key: "key", ReservedAt: time.Now().UTC()})
+	assert.ErrorIs(t, err, repository.ErrDuplicatedKey)
+}
@@ -0,0 +1,45 @@
+package migration
+
+import (
+	"github.com/khivuksergey/portmonetka.wallet/internal/adapter/storage/gorm/migration"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"testing/fstest"
+)
+
+func TestLoadMigrations_Ordered(t *testing.T) {
+	fsys := fstest.MapFS{
+		"sql/bootstrap.sql":          {Data: []byte("bootstrap")},
+		"sql/0002_second.up.sql":     {Data: []byte("up 2")},
+		"sql/0002_second.down.sql":   {Data: []byte("down 2")},
+		"sql/0001_baseline.up.sql":   {Data: []byte("up 1")},
+		"sql/0001_baseline.down.sql": {Data: []byte("down 1")},
+	}
+
+	migrations, err := migration.LoadMigrations(fsys, "sql")
+
+	assert.NoError(t, err)
+	assert.Len(t, migrations, 2)
+	assert.Equal(t, migration.Migration{Version: 1, Name: "baseline", Up: "up 1", Down: "down 1"}, migrations[0])
+	assert.Equal(t, uint64(2), migrations[1].Version)
+}
+
+func TestLoadMigrations_MissingDown(t *testing.T) {
+	fsys := fstest.MapFS{
+		"sql/0001_baseline.up.sql": {Data: []byte("up 1")},
+	}
+
+	_, err := migration.LoadMigrations(fsys, "sql")
+
+	assert.Error(t, err)
+}
+
+func TestLoadMigrations_InvalidFileName(t *testing.T) {
+	fsys := fstest.MapFS{
+		"sql/baseline.sql": {Data: []byte("up 1")},
+	}
+
+	_, err := migration.LoadMigrations(fsys, "sql")
+
+	assert.Error(t, err)
+}
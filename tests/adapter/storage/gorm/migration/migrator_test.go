@@ -0,0 +1,165 @@
+package migration
+
+import (
+	"context"
+	"github.com/khivuksergey/portmonetka.wallet/config"
+	walletgorm "github.com/khivuksergey/portmonetka.wallet/internal/adapter/storage/gorm"
+	"github.com/khivuksergey/portmonetka.wallet/internal/adapter/storage/gorm/migration"
+	"github.com/khivuksergey/portmonetka.wallet/internal/adapter/storage/gorm/repo"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"os"
+	"sync"
+	"testing"
+)
+
+// postgresDsnEnv points the Postgres tests at a scratch database, whose portmonetka schema they drop.
+const postgresDsnEnv = "TEST_POSTGRES_DSN"
+
+func TestMigrator_UpAndDown_Sqlite(t *testing.T) {
+	db, err := walletgorm.Open(config.DBConfig{Driver: walletgorm.DriverSqlite})
+	if !assert.NoError(t, err) {
+		return
+	}
+	migrator, err := migration.NewMigrator(db)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	applied, err := migrator.Up()
+	assert.NoError(t, err)
+	assert.NotEmpty(t, applied)
+	assert.NoError(t, migrator.Check())
+
+	applied, err = migrator.Up()
+	assert.NoError(t, err)
+	assert.Empty(t, applied)
+
+	reverted, err := migrator.Down(1)
+	assert.NoError(t, err)
+	assert.Len(t, reverted, 1)
+	assert.ErrorContains(t, migrator.Check(), "pending")
+
+	applied, err = migrator.Up()
+	assert.NoError(t, err)
+	assert.Equal(t, reverted, applied)
+}
+
+func TestMigrator_BackfillsWalletNameKeys_Sqlite(t *testing.T) {
+	db, err := walletgorm.Open(config.DBConfig{Driver: walletgorm.DriverSqlite})
+	if !assert.NoError(t, err) {
+		return
+	}
+	migrator, err := migration.NewMigrator(db)
+	if !assert.NoError(t, err) {
+		return
+	}
+	if _, err = migrator.Up(); !assert.NoError(t, err) {
+		return
+	}
+	if _, err = migrator.Down(2); !assert.NoError(t, err) {
+		return
+	}
+	err = db.Exec(`INSERT INTO portmonetka.wallets (user_id, name, currency, initial_amount) VALUES
+		(1, 'Кошелёк', 'RUB', '0'), (1, 'Straße', 'EUR', '0'), (2, 'Straße', 'EUR', '0'), (2, 'STRASSE', 'EUR', '0')`).Error
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	_, err = migrator.Up()
+	assert.ErrorContains(t, err, "15_wallet_name_key_index", "names of user 2 differ only in case")
+
+	var keys []string
+	assert.NoError(t, db.Table("portmonetka.wallets").Order("id").Pluck("name_key", &keys).Error)
+	assert.Equal(t, []string{"кошелёк", "strasse", "strasse", "strasse"}, keys)
+
+	assert.NoError(t, db.Exec(`UPDATE portmonetka.wallets SET deleted_at = CURRENT_TIMESTAMP WHERE name = 'STRASSE'`).Error)
+	_, err = migrator.Up()
+	assert.NoError(t, err)
+	assert.NoError(t, migrator.Check())
+}
+
+// TestMigrator_Up_AutoMigratedSchema_Postgres upgrades the schema AutoMigrate built from the first
+// version of the wallet entity, concurrently like replicas starting in auto mode.
+func TestMigrator_Up_AutoMigratedSchema_Postgres(t *testing.T) {
+	dsn := postgresDsn(t)
+	db, err := openPostgres(dsn)
+	if !assert.NoError(t, err) {
+		return
+	}
+	err = db.Exec(`
+		DROP SCHEMA IF EXISTS portmonetka CASCADE;
+		CREATE SCHEMA portmonetka;
+		CREATE TABLE portmonetka.wallets
+		(
+			id             BIGSERIAL PRIMARY KEY,
+			user_id        BIGINT NOT NULL,
+			name           TEXT   NOT NULL,
+			description    TEXT,
+			currency       TEXT   NOT NULL,
+			initial_amount TEXT   NOT NULL,
+			created_at     TIMESTAMPTZ,
+			updated_at     TIMESTAMPTZ,
+			deleted_at     TIMESTAMPTZ
+		);
+		CREATE UNIQUE INDEX idx_userid_name_deletedat ON portmonetka.wallets (user_id, name, deleted_at);
+		CREATE INDEX idx_portmonetka_wallets_deleted_at ON portmonetka.wallets (deleted_at);
+		INSERT INTO portmonetka.wallets (user_id, name, currency, initial_amount, created_at, updated_at)
+		VALUES (1, 'Cash', 'USD', '10.5', now(), now());
+	`).Error
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, 4)
+	for i := range errs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			replica, err := openPostgres(dsn)
+			if err == nil {
+				err = migration.Run(replica, migration.ModeAuto)
+			}
+			errs[i] = err
+		}()
+	}
+	wg.Wait()
+	for _, err := range errs {
+		assert.NoError(t, err)
+	}
+
+	walletRepository := repo.NewWalletRepository(db)
+	wallet, err := walletRepository.GetWalletById(context.Background(), 1)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, uint64(1), wallet.Version)
+	assert.True(t, decimal.RequireFromString("10.5").Equal(wallet.InitialAmount))
+
+	wallet.Name = "Pocket"
+	updated, err := walletRepository.UpdateWallet(context.Background(), wallet)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, uint64(2), updated.Version)
+	assert.NoError(t, walletRepository.DeleteWalletWithVersion(context.Background(), updated.Id, updated.Version))
+}
+
+func postgresDsn(t *testing.T) string {
+	dsn := os.Getenv(postgresDsnEnv)
+	if dsn == "" {
+		t.Skipf("%s is not set", postgresDsnEnv)
+	}
+	return dsn
+}
+
+func openPostgres(dsn string) (*gorm.DB, error) {
+	return gorm.Open(postgres.New(postgres.Config{DSN: dsn, PreferSimpleProtocol: true}), &gorm.Config{
+		Logger:         logger.Default.LogMode(logger.Silent),
+		TranslateError: true,
+	})
+}
@@ -0,0 +1,103 @@
+package gorm
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/khivuksergey/portmonetka.wallet/config"
+	"github.com/khivuksergey/portmonetka.wallet/internal/adapter/storage/entity"
+	"github.com/khivuksergey/portmonetka.wallet/internal/adapter/storage/gorm"
+	"github.com/khivuksergey/portmonetka.wallet/internal/adapter/storage/gorm/migration"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestOutbox_CreateAndMarkPublished(t *testing.T) {
+	db := gorm.NewDbManager(config.DBConfig{Driver: gorm.DriverSqlite, MigrationMode: migration.ModeAuto})
+	defer db.Close()
+	repositories := db.InitRepositoryManager()
+	ctx := context.Background()
+
+	for _, name := range []string{"Cash", "Card"} {
+		payload, _ := json.Marshal(map[string]any{"wallet": map[string]any{"name": name}})
+		assert.NoError(t, repositories.Outbox.CreateOutboxEvent(ctx, &entity.OutboxEvent{Type: entity.WalletCreatedEvent, AggregateId: 1, Payload: payload}))
+	}
+
+	claimed, err := repositories.Outbox.ClaimOutboxEvents(ctx, 1, time.Now().Add(time.Minute))
+	assert.NoError(t, err)
+	if !assert.Len(t, claimed, 1) {
+		return
+	}
+	assert.Equal(t, entity.WalletCreatedEvent, claimed[0].Type)
+	assert.JSONEq(t, `{"wallet":{"name":"Cash"}}`, string(claimed[0].Payload))
+	assert.Nil(t, claimed[0].PublishedAt)
+
+	assert.NoError(t, repositories.Outbox.MarkOutboxEventPublished(ctx, claimed[0].Id, time.Now()))
+
+	claimed, err = repositories.Outbox.ClaimOutboxEvents(ctx, 10, time.Now().Add(time.Minute))
+	assert.NoError(t, err)
+	if assert.Len(t, claimed, 1) {
+		assert.JSONEq(t, `{"wallet":{"name":"Card"}}`, string(claimed[0].Payload))
+	}
+}
+
+func TestOutbox_ClaimAndRelease(t *testing.T) {
+	db := gorm.NewDbManager(config.DBConfig{Driver: gorm.DriverSqlite, MigrationMode: migration.ModeAuto})
+	defer db.Close()
+	repositories := db.InitRepositoryManager()
+	ctx := context.Background()
+
+	for _, name := range []string{"Cash", "Card"} {
+		payload, _ := json.Marshal(map[string]any{"wallet": map[string]any{"name": name}})
+		assert.NoError(t, repositories.Outbox.CreateOutboxEvent(ctx, &entity.OutboxEvent{Type: entity.WalletCreatedEvent, AggregateId: 1, Payload: payload}))
+	}
+
+	claimed, err := repositories.Outbox.ClaimOutboxEvents(ctx, 10, time.Now().Add(time.Minute))
+	assert.NoError(t, err)
+	if !assert.Len(t, claimed, 2) {
+		return
+	}
+
+	again, err := repositories.Outbox.ClaimOutboxEvents(ctx, 10, time.Now().Add(time.Minute))
+	assert.NoError(t, err)
+	assert.Empty(t, again, "claimed events must not be claimed again before the claim expires")
+
+	assert.NoError(t, repositories.Outbox.ReleaseOutboxEvents(ctx, []uint64{claimed[1].Id}))
+	again, err = repositories.Outbox.ClaimOutboxEvents(ctx, 10, time.Now().Add(-time.Second))
+	assert.NoError(t, err)
+	if assert.Len(t, again, 1) {
+		assert.Equal(t, claimed[1].Id, again[0].Id)
+	}
+
+	again, err = repositories.Outbox.ClaimOutboxEvents(ctx, 10, time.Now().Add(time.Minute))
+	assert.NoError(t, err)
+	if assert.Len(t, again, 1, "expired claims must be claimed again") {
+		assert.Equal(t, claimed[1].Id, again[0].Id)
+	}
+}
+
+func TestOutbox_DeletePublishedOutboxEvents(t *testing.T) {
+	db := gorm.NewDbManager(config.DBConfig{Driver: gorm.DriverSqlite, MigrationMode: migration.ModeAuto})
+	defer db.Close()
+	repositories := db.InitRepositoryManager()
+	ctx := context.Background()
+
+	for range 3 {
+		assert.NoError(t, repositories.Outbox.CreateOutboxEvent(ctx, &entity.OutboxEvent{Type: entity.WalletCreatedEvent, AggregateId: 1, Payload: json.RawMessage(`{}`)}))
+	}
+	claimed, err := repositories.Outbox.ClaimOutboxEvents(ctx, 2, time.Now().Add(time.Minute))
+	assert.NoError(t, err)
+	if !assert.Len(t, claimed, 2) {
+		return
+	}
+	assert.NoError(t, repositories.Outbox.MarkOutboxEventPublished(ctx, claimed[0].Id, time.Now().Add(-48*time.Hour)))
+	assert.NoError(t, repositories.Outbox.MarkOutboxEventPublished(ctx, claimed[1].Id, time.Now()))
+
+	deleted, err := repositories.Outbox.DeletePublishedOutboxEvents(ctx, time.Now().Add(-24*time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+
+	deleted, err = repositories.Outbox.DeletePublishedOutboxEvents(ctx, time.Now().Add(time.Minute))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), deleted, "unpublished events must be kept")
+}
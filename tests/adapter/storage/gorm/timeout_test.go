@@ -0,0 +1,57 @@
+package gorm
+
+import (
+	"context"
+	"github.com/khivuksergey/portmonetka.wallet/config"
+	"github.com/khivuksergey/portmonetka.wallet/internal/adapter/storage/gorm"
+	"github.com/khivuksergey/portmonetka.wallet/internal/adapter/storage/gorm/migration"
+	"github.com/khivuksergey/portmonetka.wallet/internal/adapter/storage/gorm/repo"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+// slowQuery counts to a hundred million, which takes SQLite far longer than the test timeouts.
+// It is executed rather than queried, as the SQLite driver interrupts only running statements,
+// but not reading rows of a query which has already returned.
+const slowQuery = "WITH RECURSIVE c(x) AS (SELECT 1 UNION ALL SELECT x + 1 FROM c WHERE x < 100000000) SELECT count(*) FROM c"
+
+func TestQueryTimeout_AbortsSlowQuery(t *testing.T) {
+	db, err := gorm.Open(config.DBConfig{Driver: gorm.DriverSqlite, QueryTimeout: 50 * time.Millisecond})
+	assert.NoError(t, err)
+
+	started := time.Now()
+	err = db.WithContext(context.Background()).Exec(slowQuery).Error
+
+	assert.Error(t, err)
+	assert.Less(t, time.Since(started), 5*time.Second)
+}
+
+func TestQueryTimeout_AbortsCancelledQuery(t *testing.T) {
+	db, err := gorm.Open(config.DBConfig{Driver: gorm.DriverSqlite, QueryTimeout: time.Minute})
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	started := time.Now()
+	err = db.WithContext(ctx).Exec(slowQuery).Error
+
+	assert.Error(t, err)
+	assert.Less(t, time.Since(started), 5*time.Second)
+}
+
+func TestRepository_CancelledContext(t *testing.T) {
+	db, err := gorm.Open(config.DBConfig{Driver: gorm.DriverSqlite, QueryTimeout: time.Minute})
+	assert.NoError(t, err)
+	assert.NoError(t, migration.Run(db, migration.ModeAuto))
+	walletRepository := repo.NewWalletRepository(db)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = walletRepository.GetAllWalletsByUserId(ctx, 1)
+
+	assert.ErrorIs(t, err, context.Canceled)
+	wallets, err := walletRepository.GetAllWalletsByUserId(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Empty(t, wallets)
+}
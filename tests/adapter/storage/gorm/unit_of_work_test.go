@@ -0,0 +1,86 @@
+package gorm
+
+import (
+	"context"
+	"errors"
+	"github.com/khivuksergey/portmonetka.wallet/config"
+	"github.com/khivuksergey/portmonetka.wallet/internal/adapter/storage/entity"
+	"github.com/khivuksergey/portmonetka.wallet/internal/adapter/storage/gorm"
+	"github.com/khivuksergey/portmonetka.wallet/internal/adapter/storage/gorm/migration"
+	"github.com/khivuksergey/portmonetka.wallet/internal/core/port/repository"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestUnitOfWork_CommitAndRollback(t *testing.T) {
+	db := gorm.NewDbManager(config.DBConfig{Driver: gorm.DriverSqlite, MigrationMode: migration.ModeAuto})
+	defer db.Close()
+	repositories := db.InitRepositoryManager()
+	ctx := context.Background()
+	failure := errors.New("failure")
+
+	createWalletWithDeposit := func(ctx context.Context, scoped *repository.Manager, name string) error {
+		wallet, err := scoped.Wallet.CreateWallet(ctx, &entity.Wallet{UserId: 1, Name: name, Currency: "USD"})
+		if err != nil {
+			return err
+		}
+		_, err = scoped.Transaction.CreateTransaction(ctx, &entity.Transaction{WalletId: wallet.Id, Amount: decimal.NewFromInt(5), Direction: entity.TransactionDirectionIn})
+		return err
+	}
+
+	err := repositories.WithinTransaction(ctx, func(ctx context.Context, scoped *repository.Manager) error {
+		if err := createWalletWithDeposit(ctx, scoped, "Committed"); err != nil {
+			return err
+		}
+		nestedErr := scoped.WithinTransaction(ctx, func(ctx context.Context, nested *repository.Manager) error {
+			assert.NoError(t, createWalletWithDeposit(ctx, nested, "Nested rolled back"))
+			return failure
+		})
+		assert.ErrorIs(t, nestedErr, failure)
+		return nil
+	})
+	assert.NoError(t, err)
+
+	err = repositories.WithinTransaction(ctx, func(ctx context.Context, scoped *repository.Manager) error {
+		assert.NoError(t, createWalletWithDeposit(ctx, scoped, "Rolled back"))
+		wallets, err := scoped.Wallet.GetAllWalletsByUserId(ctx, 1)
+		assert.NoError(t, err)
+		assert.Len(t, wallets, 2)
+		return failure
+	})
+	assert.ErrorIs(t, err, failure)
+
+	wallets, err := repositories.Wallet.GetAllWalletsByUserId(ctx, 1)
+	assert.NoError(t, err)
+	assert.Len(t, wallets, 1)
+	assert.Equal(t, "Committed", wallets[0].Name)
+	assert.Equal(t, "5", wallets[0].CurrentBalance.String())
+}
+
+func TestUnitOfWork_GetWalletForUpdate(t *testing.T) {
+	db := gorm.NewDbManager(config.DBConfig{Driver: gorm.DriverSqlite, MigrationMode: migration.ModeAuto})
+	defer db.Close()
+	repositories := db.InitRepositoryManager()
+	ctx := context.Background()
+
+	wallet, err := repositories.Wallet.CreateWallet(ctx, &entity.Wallet{UserId: 1, Name: "Cash", Currency: "USD", InitialAmount: decimal.NewFromInt(10)})
+	if !assert.NoError(t, err) {
+		return
+	}
+	_, err = repositories.Transaction.CreateTransaction(ctx, &entity.Transaction{WalletId: wallet.Id, Amount: decimal.NewFromInt(3), Direction: entity.TransactionDirectionOut})
+	assert.NoError(t, err)
+
+	err = repositories.WithinTransaction(ctx, func(ctx context.Context, scoped *repository.Manager) error {
+		locked, err := scoped.Wallet.GetWalletForUpdate(ctx, wallet.Id)
+		if !assert.NoError(t, err) {
+			return nil
+		}
+		assert.Equal(t, "7", locked.CurrentBalance.String())
+
+		_, err = scoped.Wallet.GetWalletForUpdate(ctx, wallet.Id+1)
+		assert.ErrorIs(t, err, repository.ErrNotFound)
+		return nil
+	})
+	assert.NoError(t, err)
+}
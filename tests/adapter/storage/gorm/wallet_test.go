@@ -0,0 +1,133 @@
+package gorm
+
+import (
+	"context"
+	"github.com/khivuksergey/portmonetka.wallet/config"
+	serviceerror "github.com/khivuksergey/portmonetka.wallet/error"
+	"github.com/khivuksergey/portmonetka.wallet/internal/adapter/storage/entity"
+	"github.com/khivuksergey/portmonetka.wallet/internal/adapter/storage/gorm"
+	"github.com/khivuksergey/portmonetka.wallet/internal/adapter/storage/gorm/migration"
+	"github.com/khivuksergey/portmonetka.wallet/internal/adapter/storage/gorm/repo"
+	"github.com/khivuksergey/portmonetka.wallet/internal/model"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestPurgeWallet_DetachesTransfers(t *testing.T) {
+	db, err := gorm.Open(config.DBConfig{Driver: gorm.DriverSqlite})
+	if !assert.NoError(t, err) || !assert.NoError(t, migration.Run(db, migration.ModeAuto)) {
+		return
+	}
+	walletRepository := repo.NewWalletRepository(db)
+	transactionRepository := repo.NewTransactionRepository(db)
+	ctx := context.Background()
+
+	cash, err := walletRepository.CreateWallet(ctx, &entity.Wallet{UserId: 1, Name: "Cash", Currency: "USD"})
+	if !assert.NoError(t, err) {
+		return
+	}
+	card, err := walletRepository.CreateWallet(ctx, &entity.Wallet{UserId: 1, Name: "Card", Currency: "USD"})
+	if !assert.NoError(t, err) {
+		return
+	}
+	transfer, err := repo.NewTransferRepository(db).CreateTransfer(ctx, &entity.Transfer{
+		UserId:         1,
+		SourceWalletId: cash.Id,
+		TargetWalletId: card.Id,
+		SourceAmount:   decimal.NewFromInt(5),
+		TargetAmount:   decimal.NewFromInt(5),
+		Rate:           decimal.NewFromInt(1),
+		Timestamp:      time.Now(),
+		Transactions: []entity.Transaction{
+			{WalletId: cash.Id, Amount: decimal.NewFromInt(5), Direction: entity.TransactionDirectionOut, Timestamp: time.Now()},
+			{WalletId: card.Id, Amount: decimal.NewFromInt(5), Direction: entity.TransactionDirectionIn, Timestamp: time.Now()},
+		},
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.NoError(t, walletRepository.DeleteWallet(ctx, cash.Id))
+	purged, err := walletRepository.PurgeWalletsDeletedBefore(ctx, time.Now().Add(time.Minute))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), purged)
+
+	var transfers int64
+	assert.NoError(t, db.Model(&entity.Transfer{}).Where("id = ?", transfer.Id).Count(&transfers).Error)
+	assert.Zero(t, transfers)
+
+	credit, err := transactionRepository.GetTransactionById(ctx, transfer.Transactions[1].Id)
+	if assert.NoError(t, err) {
+		assert.Nil(t, credit.TransferId)
+	}
+	wallet, err := walletRepository.GetWalletById(ctx, card.Id)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "5", wallet.CurrentBalance.String())
+	}
+}
+
+func TestDeleteWallet_ClearsDefaultWallet(t *testing.T) {
+	db, err := gorm.Open(config.DBConfig{Driver: gorm.DriverSqlite})
+	if !assert.NoError(t, err) || !assert.NoError(t, migration.Run(db, migration.ModeAuto)) {
+		return
+	}
+	walletRepository := repo.NewWalletRepository(db)
+	preferencesRepository := repo.NewPreferencesRepository(db)
+	ctx := context.Background()
+
+	cash, err := walletRepository.CreateWallet(ctx, &entity.Wallet{UserId: 1, Name: "Cash", Currency: "USD"})
+	if !assert.NoError(t, err) {
+		return
+	}
+	_, err = preferencesRepository.SavePreferences(ctx, &entity.Preferences{UserId: 1, DefaultWalletId: &cash.Id, Locale: "en"})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.NoError(t, walletRepository.DeleteWalletWithVersion(ctx, cash.Id, cash.Version))
+
+	preferences, err := preferencesRepository.GetPreferences(ctx, 1)
+	if assert.NoError(t, err) && assert.NotNil(t, preferences) {
+		assert.Nil(t, preferences.DefaultWalletId)
+	}
+}
+
+func TestCreateWallet_DuplicateNonASCIIName(t *testing.T) {
+	db, err := gorm.Open(config.DBConfig{Driver: gorm.DriverSqlite})
+	if !assert.NoError(t, err) || !assert.NoError(t, migration.Run(db, migration.ModeAuto)) {
+		return
+	}
+	walletRepository := repo.NewWalletRepository(db)
+	ctx := context.Background()
+
+	wallet, err := walletRepository.CreateWallet(ctx, &entity.Wallet{UserId: 1, Name: "Кошелёк", Currency: "RUB"})
+	if !assert.NoError(t, err) {
+		return
+	}
+	_, err = walletRepository.CreateWallet(ctx, &entity.Wallet{UserId: 1, Name: "КОШЕЛЁК", Currency: "RUB"})
+	assert.ErrorIs(t, err, serviceerror.WalletAlreadyExists)
+
+	card, err := walletRepository.CreateWallet(ctx, &entity.Wallet{UserId: 1, Name: "Card", Currency: "RUB"})
+	if !assert.NoError(t, err) {
+		return
+	}
+	card.Name = "кошелёк"
+	_, err = walletRepository.UpdateWallet(ctx, card)
+	assert.ErrorIs(t, err, serviceerror.WalletAlreadyExists)
+	_, err = walletRepository.CreateWallet(ctx, &entity.Wallet{UserId: 1, Name: "Кошел\u0415\u0308к", Currency: "RUB"})
+	assert.ErrorIs(t, err, serviceerror.WalletAlreadyExists, "decomposed Ё must match the composed one")
+
+	_, err = walletRepository.CreateWallet(ctx, &entity.Wallet{UserId: 1, Name: "Straße", Currency: "EUR"})
+	if !assert.NoError(t, err) {
+		return
+	}
+	_, err = walletRepository.CreateWallet(ctx, &entity.Wallet{UserId: 1, Name: "STRASSE", Currency: "EUR"})
+	assert.ErrorIs(t, err, serviceerror.WalletAlreadyExists)
+
+	wallets, err := walletRepository.GetWalletsByUserId(ctx, 1, model.WalletListQuery{Name: "ШЕЛЁ", Limit: 10}, nil)
+	if assert.NoError(t, err) && assert.Len(t, wallets, 1) {
+		assert.Equal(t, wallet.Id, wallets[0].Id)
+	}
+}
@@ -0,0 +1,299 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	serviceerror "github.com/khivuksergey/portmonetka.wallet/error"
+	"github.com/khivuksergey/portmonetka.wallet/internal/adapter/storage/entity"
+	"github.com/khivuksergey/portmonetka.wallet/internal/adapter/storage/memory"
+	"github.com/khivuksergey/portmonetka.wallet/internal/core/port/repository"
+	"github.com/khivuksergey/portmonetka.wallet/internal/model"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCreateWallet_DuplicateNameAmongActive(t *testing.T) {
+	repositories := memory.NewRepositoryManager()
+
+	first, err := repositories.Wallet.CreateWallet(context.Background(), &entity.Wallet{UserId: 1, Name: "Cash", Currency: "USD"})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), first.Version)
+	assert.False(t, first.CreatedAt.IsZero())
+
+	_, err = repositories.Wallet.CreateWallet(context.Background(), &entity.Wallet{UserId: 1, Name: "Cash", Currency: "USD"})
+	assert.ErrorIs(t, err, serviceerror.WalletAlreadyExists)
+	_, err = repositories.Wallet.CreateWallet(context.Background(), &entity.Wallet{UserId: 1, Name: "CASH", Currency: "USD"})
+	assert.ErrorIs(t, err, serviceerror.WalletAlreadyExists)
+	_, err = repositories.Wallet.CreateWallet(context.Background(), &entity.Wallet{UserId: 1, Name: "Кошелёк", Currency: "RUB"})
+	assert.NoError(t, err)
+	_, err = repositories.Wallet.CreateWallet(context.Background(), &entity.Wallet{UserId: 1, Name: "КОШЕЛЁК", Currency: "RUB"})
+	assert.ErrorIs(t, err, serviceerror.WalletAlreadyExists)
+	_, err = repositories.Wallet.CreateWallet(context.Background(), &entity.Wallet{UserId: 1, Name: "Straße", Currency: "EUR"})
+	assert.NoError(t, err)
+	_, err = repositories.Wallet.CreateWallet(context.Background(), &entity.Wallet{UserId: 1, Name: "STRASSE", Currency: "EUR"})
+	assert.ErrorIs(t, err, serviceerror.WalletAlreadyExists)
+
+	_, err = repositories.Wallet.CreateWallet(context.Background(), &entity.Wallet{UserId: 2, Name: "Cash", Currency: "USD"})
+	assert.NoError(t, err)
+
+	assert.NoError(t, repositories.Wallet.DeleteWallet(context.Background(), first.Id))
+	_, err = repositories.Wallet.CreateWallet(context.Background(), &entity.Wallet{UserId: 1, Name: "Cash", Currency: "USD"})
+	assert.NoError(t, err)
+
+	_, err = repositories.Wallet.RestoreWallet(context.Background(), first.Id)
+	assert.ErrorIs(t, err, serviceerror.WalletAlreadyExists)
+}
+
+func TestDeleteWallet_SoftDelete(t *testing.T) {
+	repositories := memory.NewRepositoryManager()
+	wallet, _ := repositories.Wallet.CreateWallet(context.Background(), &entity.Wallet{UserId: 1, Name: "Cash", Currency: "USD"})
+
+	assert.NoError(t, repositories.Wallet.DeleteWallet(context.Background(), wallet.Id))
+
+	_, err := repositories.Wallet.GetWalletById(context.Background(), wallet.Id)
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+	deleted, err := repositories.Wallet.GetDeletedWalletById(context.Background(), wallet.Id)
+	assert.NoError(t, err)
+	assert.Equal(t, wallet.Id, deleted.Id)
+
+	purged, err := repositories.Wallet.PurgeWalletsDeletedBefore(context.Background(), time.Now().Add(time.Second))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), purged)
+	_, err = repositories.Wallet.GetDeletedWalletById(context.Background(), wallet.Id)
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+}
+
+func TestUpdateWallet_VersionMismatch(t *testing.T) {
+	repositories := memory.NewRepositoryManager()
+	wallet, _ := repositories.Wallet.CreateWallet(context.Background(), &entity.Wallet{UserId: 1, Name: "Cash", Currency: "USD"})
+	first, _ := repositories.Wallet.GetWalletById(context.Background(), wallet.Id)
+	second, _ := repositories.Wallet.GetWalletById(context.Background(), wallet.Id)
+
+	first.Name = "Card"
+	updated, err := repositories.Wallet.UpdateWallet(context.Background(), first)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(2), updated.Version)
+
+	second.Name = "Bank"
+	_, err = repositories.Wallet.UpdateWallet(context.Background(), second)
+	assert.ErrorIs(t, err, serviceerror.WalletVersionMismatch)
+}
+
+func TestGetWalletById_CurrentBalance(t *testing.T) {
+	repositories := memory.NewRepositoryManager()
+	wallet, _ := repositories.Wallet.CreateWallet(context.Background(), &entity.Wallet{
+		UserId: 1, Name: "Cash", Currency: "USD", InitialAmount: decimal.RequireFromString("100.10"),
+	})
+	_, _ = repositories.Transaction.CreateTransaction(context.Background(), &entity.Transaction{
+		WalletId: wallet.Id, Amount: decimal.RequireFromString("0.2"), Direction: entity.TransactionDirectionIn,
+	})
+	out, _ := repositories.Transaction.CreateTransaction(context.Background(), &entity.Transaction{
+		WalletId: wallet.Id, Amount: decimal.RequireFromString("50"), Direction: entity.TransactionDirectionOut,
+	})
+
+	actual, _ := repositories.Wallet.GetWalletById(context.Background(), wallet.Id)
+	assert.Equal(t, "50.3", actual.CurrentBalance.String())
+
+	assert.NoError(t, repositories.Transaction.DeleteTransaction(context.Background(), out.Id))
+	actual, _ = repositories.Wallet.GetWalletById(context.Background(), wallet.Id)
+	assert.Equal(t, "100.3", actual.CurrentBalance.String())
+}
+
+func TestCreateTransaction_UnknownWallet(t *testing.T) {
+	repositories := memory.NewRepositoryManager()
+
+	_, err := repositories.Transaction.CreateTransaction(context.Background(), &entity.Transaction{WalletId: 42, Amount: decimal.NewFromInt(1)})
+
+	assert.ErrorIs(t, err, repository.ErrForeignKeyViolated)
+}
+
+func TestCreateTransfer_RolledBackOnInvalidLeg(t *testing.T) {
+	repositories := memory.NewRepositoryManager()
+	wallet, _ := repositories.Wallet.CreateWallet(context.Background(), &entity.Wallet{UserId: 1, Name: "Cash", Currency: "USD"})
+
+	_, err := repositories.Transfer.CreateTransfer(context.Background(), &entity.Transfer{
+		Transactions: []entity.Transaction{
+			{WalletId: wallet.Id, Amount: decimal.NewFromInt(1), Direction: entity.TransactionDirectionOut},
+			{WalletId: 42, Amount: decimal.NewFromInt(1), Direction: entity.TransactionDirectionIn},
+		},
+	})
+
+	assert.ErrorIs(t, err, repository.ErrForeignKeyViolated)
+	transactions, _ := repositories.Transaction.GetTransactionsByWalletId(context.Background(), wallet.Id)
+	assert.Empty(t, transactions)
+}
+
+func TestGetWalletsByUserId_Pagination(t *testing.T) {
+	repositories := memory.NewRepositoryManager()
+	for _, name := range []string{"Cash", "Bank", "Card"} {
+		_, _ = repositories.Wallet.CreateWallet(context.Background(), &entity.Wallet{UserId: 1, Name: name, Currency: "USD"})
+	}
+	query := model.WalletListQuery{Sort: model.WalletSortName, Limit: 2}
+
+	page, err := repositories.Wallet.GetWalletsByUserId(context.Background(), 1, query, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Bank", "Card"}, walletNames(page))
+
+	page, err = repositories.Wallet.GetWalletsByUserId(context.Background(), 1, query, &model.WalletCursor{Value: page[1].Name, Id: page[1].Id})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Cash"}, walletNames(page))
+}
+
+func TestIdempotencyKey_Unique(t *testing.T) {
+	repositories := memory.NewRepositoryManager()
+
+	assert.NoError(t, repositories.Idempotency.CreateIdempotencyKey(context.Background(), &entity.IdempotencyKey{UserId: 1, Key: "key"}))
+	assert.ErrorIs(t, repositories.Idempotency.CreateIdempotencyKey(context.Background(), &entity.IdempotencyKey{UserId: 1, Key: "key"}), repository.ErrDuplicatedKey)
+	assert.NoError(t, repositories.Idempotency.CreateIdempotencyKey(context.Background(), &entity.IdempotencyKey{UserId: 2, Key: "key"}))
+}
+
+func TestCreateWallet_Concurrent(t *testing.T) {
+	repositories := memory.NewRepositoryManager()
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	created := 0
+
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := repositories.Wallet.CreateWallet(context.Background(), &entity.Wallet{UserId: 1, Name: "Cash", Currency: "USD"}); err == nil {
+				mu.Lock()
+				created++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 1, created)
+}
+
+func TestPreferences_DefaultWalletClearedOnDelete(t *testing.T) {
+	repositories := memory.NewRepositoryManager()
+	wallet, _ := repositories.Wallet.CreateWallet(context.Background(), &entity.Wallet{UserId: 1, Name: "Cash", Currency: "USD"})
+
+	missingWalletId := wallet.Id + 1
+	_, err := repositories.Preferences.SavePreferences(context.Background(), &entity.Preferences{UserId: 1, DefaultWalletId: &missingWalletId})
+	assert.ErrorIs(t, err, repository.ErrForeignKeyViolated)
+
+	_, err = repositories.Preferences.SavePreferences(context.Background(), &entity.Preferences{UserId: 1, DefaultWalletId: &wallet.Id})
+	assert.NoError(t, err)
+
+	assert.NoError(t, repositories.Wallet.DeleteWallet(context.Background(), wallet.Id))
+
+	preferences, err := repositories.Preferences.GetPreferences(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Nil(t, preferences.DefaultWalletId)
+}
+
+func TestWalletMembers_DeletedOnPurge(t *testing.T) {
+	repositories := memory.NewRepositoryManager()
+	wallet, _ := repositories.Wallet.CreateWallet(context.Background(), &entity.Wallet{UserId: 1, Name: "Household", Currency: "USD"})
+
+	_, err := repositories.WalletMember.CreateWalletMember(context.Background(), &entity.WalletMember{WalletId: wallet.Id + 1, UserId: 2, Role: entity.WalletRoleViewer})
+	assert.ErrorIs(t, err, repository.ErrForeignKeyViolated)
+
+	acceptedAt := time.Now()
+	walletMember, err := repositories.WalletMember.CreateWalletMember(context.Background(), &entity.WalletMember{
+		WalletId:   wallet.Id,
+		UserId:     2,
+		Role:       entity.WalletRoleEditor,
+		AcceptedAt: &acceptedAt,
+	})
+	assert.NoError(t, err)
+	_, err = repositories.WalletMember.CreateWalletMember(context.Background(), &entity.WalletMember{WalletId: wallet.Id, UserId: 2, Role: entity.WalletRoleViewer})
+	assert.ErrorIs(t, err, repository.ErrDuplicatedKey)
+
+	wallets, err := repositories.Wallet.GetWalletsByUserId(context.Background(), 2, model.WalletListQuery{}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Household"}, walletNames(wallets))
+
+	assert.NoError(t, repositories.Wallet.DeleteWallet(context.Background(), wallet.Id))
+	assert.NoError(t, repositories.Wallet.PurgeWallet(context.Background(), wallet.Id))
+
+	deletedMember, err := repositories.WalletMember.GetWalletMember(context.Background(), wallet.Id, 2)
+	assert.NoError(t, err)
+	assert.Nil(t, deletedMember)
+	_, err = repositories.WalletMember.GetWalletMemberById(context.Background(), walletMember.Id)
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+}
+
+func walletNames(wallets []entity.Wallet) []string {
+	names := make([]string, len(wallets))
+	for i, wallet := range wallets {
+		names[i] = wallet.Name
+	}
+	return names
+}
+
+func TestUnitOfWork_CommitAndRollback(t *testing.T) {
+	repositories := memory.NewRepositoryManager()
+	ctx := context.Background()
+	failure := errors.New("failure")
+
+	err := repositories.WithinTransaction(ctx, func(ctx context.Context, scoped *repository.Manager) error {
+		wallet, err := scoped.Wallet.CreateWallet(ctx, &entity.Wallet{UserId: 1, Name: "Committed", Currency: "USD"})
+		if err != nil {
+			return err
+		}
+		_, err = scoped.Transaction.CreateTransaction(ctx, &entity.Transaction{WalletId: wallet.Id, Amount: decimal.NewFromInt(5), Direction: entity.TransactionDirectionIn})
+		return err
+	})
+	assert.NoError(t, err)
+
+	err = repositories.WithinTransaction(ctx, func(ctx context.Context, scoped *repository.Manager) error {
+		wallet, err := scoped.Wallet.CreateWallet(ctx, &entity.Wallet{UserId: 1, Name: "Rolled back", Currency: "USD"})
+		if err != nil {
+			return err
+		}
+		wallets, err := scoped.Wallet.GetAllWalletsByUserId(ctx, 1)
+		assert.NoError(t, err)
+		assert.Len(t, wallets, 2)
+		_, err = scoped.Transaction.CreateTransaction(ctx, &entity.Transaction{WalletId: wallet.Id, Amount: decimal.NewFromInt(5), Direction: entity.TransactionDirectionIn})
+		assert.NoError(t, err)
+		return failure
+	})
+	assert.ErrorIs(t, err, failure)
+
+	wallets, err := repositories.Wallet.GetAllWalletsByUserId(ctx, 1)
+	assert.NoError(t, err)
+	assert.Len(t, wallets, 1)
+	assert.Equal(t, "Committed", wallets[0].Name)
+	assert.Equal(t, "5", wallets[0].CurrentBalance.String())
+}
+
+func TestTransfers_DetachedOnPurge(t *testing.T) {
+	repositories := memory.NewRepositoryManager()
+	cash, _ := repositories.Wallet.CreateWallet(context.Background(), &entity.Wallet{UserId: 1, Name: "Cash", Currency: "USD"})
+	card, _ := repositories.Wallet.CreateWallet(context.Background(), &entity.Wallet{UserId: 1, Name: "Card", Currency: "USD"})
+
+	transfer, err := repositories.Transfer.CreateTransfer(context.Background(), &entity.Transfer{
+		UserId:         1,
+		SourceWalletId: cash.Id,
+		TargetWalletId: card.Id,
+		Transactions: []entity.Transaction{
+			{WalletId: cash.Id, Amount: decimal.NewFromInt(5), Direction: entity.TransactionDirectionOut},
+			{WalletId: card.Id, Amount: decimal.NewFromInt(5), Direction: entity.TransactionDirectionIn},
+		},
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.NoError(t, repositories.Wallet.DeleteWallet(context.Background(), cash.Id))
+	assert.NoError(t, repositories.Wallet.PurgeWallet(context.Background(), cash.Id))
+
+	_, err = repositories.Transaction.GetTransactionById(context.Background(), transfer.Transactions[0].Id)
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+	credit, err := repositories.Transaction.GetTransactionById(context.Background(), transfer.Transactions[1].Id)
+	assert.NoError(t, err)
+	assert.Nil(t, credit.TransferId)
+
+	wallet, err := repositories.Wallet.GetWalletById(context.Background(), card.Id)
+	assert.NoError(t, err)
+	assert.Equal(t, "5", wallet.CurrentBalance.String())
+}
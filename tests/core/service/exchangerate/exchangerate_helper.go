@@ -0,0 +1,34 @@
+package exchangerate
+
+const ecbXML = `<?xml version="1.0" encoding="UTF-8"?>
+<gesmes:Envelope xmlns:gesmes="http://www.gesmes.org/xml/2002-08-01" xmlns="http://www.ecb.int/vocabulary/2002-08-01/eurofxref">
+	<gesmes:subject>Reference rates</gesmes:subject>
+	<gesmes:Sender>
+		<gesmes:name>European Central Bank</gesmes:name>
+	</gesmes:Sender>
+	<Cube>
+		<Cube time="2024-05-17">
+			<Cube currency="USD" rate="1.0866"/>
+			<Cube currency="JPY" rate="169.16"/>
+		</Cube>
+		<Cube time="2024-05-16">
+			<Cube currency="USD" rate="1.0858"/>
+			<Cube currency="HRK" rate="7.5345"/>
+		</Cube>
+	</Cube>
+</gesmes:Envelope>`
+
+const ecbCSV = `Date, USD, JPY, RUB, 
+2024-05-17, 1.0866, 169.16, N/A, 
+`
+
+const longCSV = `date,base,quote,rate
+2024-05-17,USD,RUB,90.95
+2024-05-17,eur,usd,1.0866
+`
+
+const duplicateCSV = `date,base,quote,rate
+2024-05-17,USD,RUB,90.95
+2024-05-17,eur,usd,1.0866
+2024-05-17,usd,rub,91.2
+`
@@ -0,0 +1,142 @@
+package exchangerate
+
+import (
+	"context"
+	serviceerror "github.com/khivuksergey/portmonetka.wallet/error"
+	"github.com/khivuksergey/portmonetka.wallet/internal/adapter/storage/entity"
+	"github.com/khivuksergey/portmonetka.wallet/internal/adapter/storage/gorm/repo/mock"
+	"github.com/khivuksergey/portmonetka.wallet/internal/core/port/repository"
+	"github.com/khivuksergey/portmonetka.wallet/internal/core/service/exchangerate"
+	"github.com/khivuksergey/portmonetka.wallet/internal/model"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestImportExchangeRates_ECBXML(t *testing.T) {
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+
+	mockExchangeRateRepository := mock.NewMockExchangeRateRepository(ctl)
+	exchangeRateService := exchangerate.NewExchangeRateService(&repository.Manager{ExchangeRate: mockExchangeRateRepository})
+
+	mockExchangeRateRepository.
+		EXPECT().
+		SaveExchangeRates(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ context.Context, exchangeRates []entity.ExchangeRate) error {
+			assert.Len(t, exchangeRates, 3)
+			assert.Equal(t, time.Date(2024, 5, 17, 0, 0, 0, 0, time.UTC), exchangeRates[0].Date)
+			assert.Equal(t, "EUR", exchangeRates[0].BaseCurrency)
+			assert.Equal(t, "USD", exchangeRates[0].QuoteCurrency)
+			assert.True(t, decimal.RequireFromString("1.0866").Equal(exchangeRates[0].Rate))
+			return nil
+		})
+
+	result, err := exchangeRateService.ImportExchangeRates(context.Background(), model.ExchangeRateFormatXML, strings.NewReader(ecbXML))
+
+	assert.NoError(t, err)
+	assert.Equal(t, &model.ExchangeRateImportResult{Imported: 3, Skipped: 1}, result)
+}
+
+func TestImportExchangeRates_ECBCSV(t *testing.T) {
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+
+	mockExchangeRateRepository := mock.NewMockExchangeRateRepository(ctl)
+	exchangeRateService := exchangerate.NewExchangeRateService(&repository.Manager{ExchangeRate: mockExchangeRateRepository})
+
+	mockExchangeRateRepository.
+		EXPECT().
+		SaveExchangeRates(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ context.Context, exchangeRates []entity.ExchangeRate) error {
+			assert.Len(t, exchangeRates, 2)
+			assert.Equal(t, "JPY", exchangeRates[1].QuoteCurrency)
+			return nil
+		})
+
+	result, err := exchangeRateService.ImportExchangeRates(context.Background(), model.ExchangeRateFormatCSV, strings.NewReader(ecbCSV))
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, result.Imported)
+}
+
+func TestImportExchangeRates_LongCSV(t *testing.T) {
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+
+	mockExchangeRateRepository := mock.NewMockExchangeRateRepository(ctl)
+	exchangeRateService := exchangerate.NewExchangeRateService(&repository.Manager{ExchangeRate: mockExchangeRateRepository})
+
+	mockExchangeRateRepository.
+		EXPECT().
+		SaveExchangeRates(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ context.Context, exchangeRates []entity.ExchangeRate) error {
+			assert.Equal(t, "USD", exchangeRates[0].BaseCurrency)
+			assert.Equal(t, "RUB", exchangeRates[0].QuoteCurrency)
+			assert.Equal(t, "EUR", exchangeRates[1].BaseCurrency)
+			return nil
+		})
+
+	result, err := exchangeRateService.ImportExchangeRates(context.Background(), model.ExchangeRateFormatCSV, strings.NewReader(longCSV))
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, result.Imported)
+}
+
+func TestImportExchangeRates_DuplicatePair_LastWins(t *testing.T) {
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+
+	mockExchangeRateRepository := mock.NewMockExchangeRateRepository(ctl)
+	exchangeRateService := exchangerate.NewExchangeRateService(&repository.Manager{ExchangeRate: mockExchangeRateRepository})
+
+	mockExchangeRateRepository.
+		EXPECT().
+		SaveExchangeRates(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ context.Context, exchangeRates []entity.ExchangeRate) error {
+			if assert.Len(t, exchangeRates, 2) {
+				assert.Equal(t, "RUB", exchangeRates[0].QuoteCurrency)
+				assert.True(t, decimal.RequireFromString("91.2").Equal(exchangeRates[0].Rate))
+				assert.Equal(t, "EUR", exchangeRates[1].BaseCurrency)
+			}
+			return nil
+		})
+
+	result, err := exchangeRateService.ImportExchangeRates(context.Background(), model.ExchangeRateFormatCSV, strings.NewReader(duplicateCSV))
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, result.Imported)
+}
+
+func TestImportExchangeRates_NonPositiveRate_Error(t *testing.T) {
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+
+	mockExchangeRateRepository := mock.NewMockExchangeRateRepository(ctl)
+	exchangeRateService := exchangerate.NewExchangeRateService(&repository.Manager{ExchangeRate: mockExchangeRateRepository})
+
+	_, err := exchangeRateService.ImportExchangeRates(context.Background(), model.ExchangeRateFormatCSV, strings.NewReader("date,base,quote,rate\n2024-05-17,USD,EUR,0\n"))
+
+	assert.ErrorIs(t, err, serviceerror.ExchangeRateError)
+}
+
+func TestImportExchangeRates_InvalidFile_Error(t *testing.T) {
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+
+	mockExchangeRateRepository := mock.NewMockExchangeRateRepository(ctl)
+	exchangeRateService := exchangerate.NewExchangeRateService(&repository.Manager{ExchangeRate: mockExchangeRateRepository})
+
+	_, err := exchangeRateService.ImportExchangeRates(context.Background(), model.ExchangeRateFormatCSV, strings.NewReader("foo,bar\n1,2\n"))
+	assert.ErrorIs(t, err, serviceerror.ExchangeRateParseError)
+
+	_, err = exchangeRateService.ImportExchangeRates(context.Background(), "json", strings.NewReader("{}"))
+	assert.ErrorIs(t, err, serviceerror.ExchangeRateFormatError)
+}
@@ -0,0 +1,306 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	serviceerror "github.com/khivuksergey/portmonetka.wallet/error"
+	"github.com/khivuksergey/portmonetka.wallet/internal/adapter/storage/entity"
+	"github.com/khivuksergey/portmonetka.wallet/internal/adapter/storage/gorm/repo/mock"
+	"github.com/khivuksergey/portmonetka.wallet/internal/core/port/repository"
+	"github.com/khivuksergey/portmonetka.wallet/internal/core/service/idempotency"
+	"github.com/khivuksergey/portmonetka.wallet/internal/model"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"testing"
+	"time"
+)
+
+const (
+	leaseTimeout = time.Minute
+	ttl          = 24 * time.Hour
+)
+
+var idempotencyKeyDTO = model.IdempotencyKeyDTO{
+	UserId:      1,
+	Key:         "6f2b1c0e-5d9a-4c1e-9f3b-2a7e8d4c6b1a",
+	RequestHash: "hash",
+}
+
+func TestBeginRequest_NewKey_Success(t *testing.T) {
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+
+	mockIdempotencyRepository := mock.NewMockIdempotencyRepository(ctl)
+	mockManager := &repository.Manager{
+		Idempotency: mockIdempotencyRepository,
+	}
+
+	idempotencyService := idempotency.NewIdempotencyService(mockManager)
+
+	mockIdempotencyRepository.
+		EXPECT().
+		GetIdempotencyKey(gomock.Any(), idempotencyKeyDTO.UserId, idempotencyKeyDTO.Key).
+		Times(1).
+		Return(nil, repository.ErrNotFound)
+
+	mockIdempotencyRepository.
+		EXPECT().
+		CreateIdempotencyKey(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ context.Context, idempotencyKey *entity.IdempotencyKey) error {
+			assert.Equal(t, idempotencyKeyDTO.UserId, idempotencyKey.UserId)
+			assert.Equal(t, idempotencyKeyDTO.Key, idempotencyKey.Key)
+			assert.Equal(t, idempotencyKeyDTO.RequestHash, idempotencyKey.RequestHash)
+			assert.WithinDuration(t, time.Now(), idempotencyKey.ReservedAt, time.Second)
+			return nil
+		})
+
+	stored, err := idempotencyService.BeginRequest(context.Background(), idempotencyKeyDTO, leaseTimeout, ttl)
+
+	assert.NoError(t, err)
+	assert.Nil(t, stored)
+}
+
+func TestBeginRequest_LookupFailure_Error(t *testing.T) {
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+
+	mockIdempotencyRepository := mock.NewMockIdempotencyRepository(ctl)
+	idempotencyService := idempotency.NewIdempotencyService(&repository.Manager{Idempotency: mockIdempotencyRepository})
+
+	lookupErr := errors.New("connection refused")
+	mockIdempotencyRepository.
+		EXPECT().
+		GetIdempotencyKey(gomock.Any(), idempotencyKeyDTO.UserId, idempotencyKeyDTO.Key).
+		Times(1).
+		Return(nil, lookupErr)
+
+	stored, err := idempotencyService.BeginRequest(context.Background(), idempotencyKeyDTO, leaseTimeout, ttl)
+
+	assert.Nil(t, stored)
+	assert.ErrorIs(t, err, lookupErr)
+}
+
+func TestBeginRequest_CompletedKey_Replay(t *testing.T) {
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+
+	mockIdempotencyRepository := mock.NewMockIdempotencyRepository(ctl)
+	mockManager := &repository.Manager{
+		Idempotency: mockIdempotencyRepository,
+	}
+
+	idempotencyService := idempotency.NewIdempotencyService(mockManager)
+
+	existing := &entity.IdempotencyKey{
+		Id:          1,
+		UserId:      idempotencyKeyDTO.UserId,
+		Key:         idempotencyKeyDTO.Key,
+		RequestHash: idempotencyKeyDTO.RequestHash,
+		StatusCode:  http.StatusCreated,
+		Response:    []byte(`{"message":"Wallet created"}`),
+		UpdatedAt:   time.Now(),
+	}
+
+	mockIdempotencyRepository.
+		EXPECT().
+		GetIdempotencyKey(gomock.Any(), idempotencyKeyDTO.UserId, idempotencyKeyDTO.Key).
+		Times(1).
+		Return(existing, nil)
+
+	stored, err := idempotencyService.BeginRequest(context.Background(), idempotencyKeyDTO, leaseTimeout, ttl)
+
+	assert.NoError(t, err)
+	assert.Equal(t, existing, stored)
+}
+
+func TestBeginRequest_KeyReusedWithDifferentRequest_Error(t *testing.T) {
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+
+	mockIdempotencyRepository := mock.NewMockIdempotencyRepository(ctl)
+	mockManager := &repository.Manager{
+		Idempotency: mockIdempotencyRepository,
+	}
+
+	idempotencyService := idempotency.NewIdempotencyService(mockManager)
+
+	mockIdempotencyRepository.
+		EXPECT().
+		GetIdempotencyKey(gomock.Any(), idempotencyKeyDTO.UserId, idempotencyKeyDTO.Key).
+		Times(1).
+		Return(&entity.IdempotencyKey{
+			UserId:      idempotencyKeyDTO.UserId,
+			Key:         idempotencyKeyDTO.Key,
+			RequestHash: "another hash",
+			StatusCode:  http.StatusCreated,
+			UpdatedAt:   time.Now(),
+		}, nil)
+
+	stored, err := idempotencyService.BeginRequest(context.Background(), idempotencyKeyDTO, leaseTimeout, ttl)
+
+	assert.Error(t, err)
+	assert.Nil(t, stored)
+	assert.Equal(t, serviceerror.IdempotencyKeyReused, err)
+}
+
+func TestBeginRequest_ConcurrentReservation_InProgressError(t *testing.T) {
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+
+	mockIdempotencyRepository := mock.NewMockIdempotencyRepository(ctl)
+	mockManager := &repository.Manager{
+		Idempotency: mockIdempotencyRepository,
+	}
+
+	idempotencyService := idempotency.NewIdempotencyService(mockManager)
+
+	gomock.InOrder(
+		mockIdempotencyRepository.
+			EXPECT().
+			GetIdempotencyKey(gomock.Any(), idempotencyKeyDTO.UserId, idempotencyKeyDTO.Key).
+			Return(nil, repository.ErrNotFound),
+		mockIdempotencyRepository.
+			EXPECT().
+			CreateIdempotencyKey(gomock.Any(), gomock.Any()).
+			Return(repository.ErrDuplicatedKey),
+		mockIdempotencyRepository.
+			EXPECT().
+			GetIdempotencyKey(gomock.Any(), idempotencyKeyDTO.UserId, idempotencyKeyDTO.Key).
+			Return(&entity.IdempotencyKey{
+				UserId:      idempotencyKeyDTO.UserId,
+				Key:         idempotencyKeyDTO.Key,
+				RequestHash: idempotencyKeyDTO.RequestHash,
+				ReservedAt:  time.Now(),
+			}, nil),
+	)
+
+	stored, err := idempotencyService.BeginRequest(context.Background(), idempotencyKeyDTO, leaseTimeout, ttl)
+
+	assert.Error(t, err)
+	assert.Nil(t, stored)
+	assert.Equal(t, serviceerror.IdempotencyKeyInProgress, err)
+}
+
+func TestBeginRequest_StaleKey_TakenOver(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		existing entity.IdempotencyKey
+	}{
+		{"pending longer than the lease", entity.IdempotencyKey{Id: 1, RequestHash: "another hash", ReservedAt: time.Now().Add(-2 * leaseTimeout)}},
+		{"completed longer than the ttl ago", entity.IdempotencyKey{Id: 1, RequestHash: "another hash", StatusCode: http.StatusCreated, UpdatedAt: time.Now().Add(-2 * ttl)}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ctl := gomock.NewController(t)
+			defer ctl.Finish()
+
+			mockIdempotencyRepository := mock.NewMockIdempotencyRepository(ctl)
+			idempotencyService := idempotency.NewIdempotencyService(&repository.Manager{Idempotency: mockIdempotencyRepository})
+
+			existing := tc.existing
+			mockIdempotencyRepository.
+				EXPECT().
+				GetIdempotencyKey(gomock.Any(), idempotencyKeyDTO.UserId, idempotencyKeyDTO.Key).
+				Times(1).
+				Return(&existing, nil)
+
+			mockIdempotencyRepository.
+				EXPECT().
+				ReserveStaleIdempotencyKey(gomock.Any(), uint64(1), idempotencyKeyDTO.RequestHash, gomock.Any(), gomock.Any()).
+				Times(1).
+				Return(true, nil)
+
+			stored, err := idempotencyService.BeginRequest(context.Background(), idempotencyKeyDTO, leaseTimeout, ttl)
+
+			assert.NoError(t, err)
+			assert.Nil(t, stored)
+		})
+	}
+}
+
+func TestBeginRequest_StaleKeyTakenOverConcurrently_InProgressError(t *testing.T) {
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+
+	mockIdempotencyRepository := mock.NewMockIdempotencyRepository(ctl)
+	idempotencyService := idempotency.NewIdempotencyService(&repository.Manager{Idempotency: mockIdempotencyRepository})
+
+	mockIdempotencyRepository.
+		EXPECT().
+		GetIdempotencyKey(gomock.Any(), idempotencyKeyDTO.UserId, idempotencyKeyDTO.Key).
+		Times(1).
+		Return(&entity.IdempotencyKey{Id: 1, RequestHash: idempotencyKeyDTO.RequestHash, ReservedAt: time.Now().Add(-2 * leaseTimeout)}, nil)
+
+	mockIdempotencyRepository.
+		EXPECT().
+		ReserveStaleIdempotencyKey(gomock.Any(), uint64(1), idempotencyKeyDTO.RequestHash, gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(false, nil)
+
+	stored, err := idempotencyService.BeginRequest(context.Background(), idempotencyKeyDTO, leaseTimeout, ttl)
+
+	assert.Nil(t, stored)
+	assert.Equal(t, serviceerror.IdempotencyKeyInProgress, err)
+}
+
+func TestBeginRequest_ZeroTTL_CompletedKeyNeverExpires(t *testing.T) {
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+
+	mockIdempotencyRepository := mock.NewMockIdempotencyRepository(ctl)
+	idempotencyService := idempotency.NewIdempotencyService(&repository.Manager{Idempotency: mockIdempotencyRepository})
+
+	existing := &entity.IdempotencyKey{
+		Id:          1,
+		RequestHash: idempotencyKeyDTO.RequestHash,
+		StatusCode:  http.StatusCreated,
+		UpdatedAt:   time.Now().AddDate(-1, 0, 0),
+	}
+	mockIdempotencyRepository.
+		EXPECT().
+		GetIdempotencyKey(gomock.Any(), idempotencyKeyDTO.UserId, idempotencyKeyDTO.Key).
+		Times(1).
+		Return(existing, nil)
+
+	stored, err := idempotencyService.BeginRequest(context.Background(), idempotencyKeyDTO, leaseTimeout, 0)
+
+	assert.NoError(t, err)
+	assert.Equal(t, existing, stored)
+}
+
+func TestCompleteRequest_Success(t *testing.T) {
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+
+	mockIdempotencyRepository := mock.NewMockIdempotencyRepository(ctl)
+	mockManager := &repository.Manager{
+		Idempotency: mockIdempotencyRepository,
+	}
+
+	idempotencyService := idempotency.NewIdempotencyService(mockManager)
+
+	response := []byte(`{"message":"Wallet created"}`)
+
+	mockIdempotencyRepository.
+		EXPECT().
+		GetIdempotencyKey(gomock.Any(), idempotencyKeyDTO.UserId, idempotencyKeyDTO.Key).
+		Times(1).
+		Return(&entity.IdempotencyKey{Id: 1, UserId: 1, Key: idempotencyKeyDTO.Key}, nil)
+
+	mockIdempotencyRepository.
+		EXPECT().
+		UpdateIdempotencyKey(gomock.Any(), &entity.IdempotencyKey{
+			Id:         1,
+			UserId:     1,
+			Key:        idempotencyKeyDTO.Key,
+			StatusCode: http.StatusCreated,
+			Response:   response,
+		}).
+		Times(1).
+		Return(nil)
+
+	err := idempotencyService.CompleteRequest(context.Background(), idempotencyKeyDTO, http.StatusCreated, response)
+
+	assert.NoError(t, err)
+}
@@ -0,0 +1,5 @@
+package member
+
+func ptr[T any](t T) *T {
+	return &t
+}
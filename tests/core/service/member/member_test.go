@@ -0,0 +1,299 @@
+package member
+
+import (
+	"context"
+	"errors"
+	serviceerror "github.com/khivuksergey/portmonetka.wallet/error"
+	"github.com/khivuksergey/portmonetka.wallet/internal/adapter/storage/entity"
+	"github.com/khivuksergey/portmonetka.wallet/internal/adapter/storage/gorm/repo/mock"
+	"github.com/khivuksergey/portmonetka.wallet/internal/adapter/storage/memory"
+	"github.com/khivuksergey/portmonetka.wallet/internal/core/port/repository"
+	"github.com/khivuksergey/portmonetka.wallet/internal/core/service/member"
+	"github.com/khivuksergey/portmonetka.wallet/internal/core/service/wallet"
+	"github.com/khivuksergey/portmonetka.wallet/internal/model"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+)
+
+func TestInviteWalletMember_Success(t *testing.T) {
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+
+	mockWalletRepository := mock.NewMockWalletRepository(ctl)
+	mockWalletMemberRepository := mock.NewMockWalletMemberRepository(ctl)
+	walletMemberService := member.NewWalletMemberService(&repository.Manager{
+		Wallet:       mockWalletRepository,
+		WalletMember: mockWalletMemberRepository,
+	})
+
+	walletMemberInviteDTO := model.WalletMemberInviteDTO{
+		UserId:    1,
+		WalletId:  2,
+		InviteeId: 3,
+		Role:      entity.WalletRoleEditor,
+	}
+	expectedWalletMember := &entity.WalletMember{
+		WalletId:  2,
+		UserId:    3,
+		Role:      entity.WalletRoleEditor,
+		InvitedBy: 1,
+	}
+
+	mockWalletRepository.
+		EXPECT().
+		GetWalletById(gomock.Any(), walletMemberInviteDTO.WalletId).
+		Times(1).
+		Return(&entity.Wallet{Id: 2, UserId: 1}, nil)
+
+	mockWalletMemberRepository.
+		EXPECT().
+		GetWalletMember(gomock.Any(), walletMemberInviteDTO.WalletId, walletMemberInviteDTO.InviteeId).
+		Times(1).
+		Return(nil, nil)
+
+	mockWalletMemberRepository.
+		EXPECT().
+		CreateWalletMember(gomock.Any(), expectedWalletMember).
+		Times(1).
+		Return(expectedWalletMember, nil)
+
+	walletMember, err := walletMemberService.InviteWalletMember(context.Background(), walletMemberInviteDTO)
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedWalletMember, walletMember)
+}
+
+func TestInviteWalletMember_EditorInvites_Error(t *testing.T) {
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+
+	mockWalletRepository := mock.NewMockWalletRepository(ctl)
+	mockWalletMemberRepository := mock.NewMockWalletMemberRepository(ctl)
+	walletMemberService := member.NewWalletMemberService(&repository.Manager{
+		Wallet:       mockWalletRepository,
+		WalletMember: mockWalletMemberRepository,
+	})
+
+	mockWalletRepository.
+		EXPECT().
+		GetWalletById(gomock.Any(), uint64(2)).
+		Times(1).
+		Return(&entity.Wallet{Id: 2, UserId: 1}, nil)
+
+	mockWalletMemberRepository.
+		EXPECT().
+		GetWalletMember(gomock.Any(), uint64(2), uint64(3)).
+		Times(1).
+		Return(&entity.WalletMember{
+			WalletId:   2,
+			UserId:     3,
+			Role:       entity.WalletRoleEditor,
+			AcceptedAt: ptr(time.Now()),
+		}, nil)
+
+	walletMember, err := walletMemberService.InviteWalletMember(context.Background(), model.WalletMemberInviteDTO{
+		UserId:    3,
+		WalletId:  2,
+		InviteeId: 4,
+		Role:      entity.WalletRoleViewer,
+	})
+
+	assert.Nil(t, walletMember)
+	assert.Equal(t, serviceerror.WalletPermissionDenied, err)
+}
+
+func TestInviteWalletMember_AlreadyInvited_Error(t *testing.T) {
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+
+	mockWalletRepository := mock.NewMockWalletRepository(ctl)
+	mockWalletMemberRepository := mock.NewMockWalletMemberRepository(ctl)
+	walletMemberService := member.NewWalletMemberService(&repository.Manager{
+		Wallet:       mockWalletRepository,
+		WalletMember: mockWalletMemberRepository,
+	})
+
+	mockWalletRepository.
+		EXPECT().
+		GetWalletById(gomock.Any(), uint64(2)).
+		Times(1).
+		Return(&entity.Wallet{Id: 2, UserId: 1}, nil)
+
+	mockWalletMemberRepository.
+		EXPECT().
+		GetWalletMember(gomock.Any(), uint64(2), uint64(3)).
+		Times(1).
+		Return(&entity.WalletMember{Id: 7, WalletId: 2, UserId: 3, Role: entity.WalletRoleViewer}, nil)
+
+	walletMember, err := walletMemberService.InviteWalletMember(context.Background(), model.WalletMemberInviteDTO{
+		UserId:    1,
+		WalletId:  2,
+		InviteeId: 3,
+		Role:      entity.WalletRoleEditor,
+	})
+
+	assert.Nil(t, walletMember)
+	assert.Equal(t, serviceerror.WalletMemberAlreadyExists, err)
+}
+
+func TestInviteWalletMember_InvitedConcurrently_Error(t *testing.T) {
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+
+	mockWalletRepository := mock.NewMockWalletRepository(ctl)
+	mockWalletMemberRepository := mock.NewMockWalletMemberRepository(ctl)
+	walletMemberService := member.NewWalletMemberService(&repository.Manager{
+		Wallet:       mockWalletRepository,
+		WalletMember: mockWalletMemberRepository,
+	})
+
+	mockWalletRepository.
+		EXPECT().
+		GetWalletById(gomock.Any(), uint64(2)).
+		Times(1).
+		Return(&entity.Wallet{Id: 2, UserId: 1}, nil)
+
+	mockWalletMemberRepository.
+		EXPECT().
+		GetWalletMember(gomock.Any(), uint64(2), uint64(3)).
+		Times(1).
+		Return(nil, nil)
+
+	mockWalletMemberRepository.
+		EXPECT().
+		CreateWalletMember(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(nil, repository.ErrDuplicatedKey)
+
+	walletMember, err := walletMemberService.InviteWalletMember(context.Background(), model.WalletMemberInviteDTO{
+		UserId:    1,
+		WalletId:  2,
+		InviteeId: 3,
+		Role:      entity.WalletRoleViewer,
+	})
+
+	assert.Nil(t, walletMember)
+	assert.Equal(t, serviceerror.WalletMemberAlreadyExists, err)
+}
+
+func TestInviteWalletMember_WalletLookupFailure_Error(t *testing.T) {
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+
+	mockWalletRepository := mock.NewMockWalletRepository(ctl)
+	walletMemberService := member.NewWalletMemberService(&repository.Manager{
+		Wallet:       mockWalletRepository,
+		WalletMember: mock.NewMockWalletMemberRepository(ctl),
+	})
+
+	lookupErr := errors.New("connection refused")
+	mockWalletRepository.
+		EXPECT().
+		GetWalletById(gomock.Any(), uint64(2)).
+		Times(1).
+		Return(nil, lookupErr)
+
+	_, err := walletMemberService.InviteWalletMember(context.Background(), model.WalletMemberInviteDTO{
+		UserId:    1,
+		WalletId:  2,
+		InviteeId: 3,
+		Role:      entity.WalletRoleViewer,
+	})
+
+	assert.ErrorIs(t, err, lookupErr)
+}
+
+func TestInviteWalletMember_Owner_Error(t *testing.T) {
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+
+	mockWalletRepository := mock.NewMockWalletRepository(ctl)
+	walletMemberService := member.NewWalletMemberService(&repository.Manager{
+		Wallet: mockWalletRepository,
+	})
+
+	mockWalletRepository.
+		EXPECT().
+		GetWalletById(gomock.Any(), uint64(2)).
+		Times(1).
+		Return(&entity.Wallet{Id: 2, UserId: 1}, nil)
+
+	walletMember, err := walletMemberService.InviteWalletMember(context.Background(), model.WalletMemberInviteDTO{
+		UserId:    1,
+		WalletId:  2,
+		InviteeId: 1,
+		Role:      entity.WalletRoleEditor,
+	})
+
+	assert.Nil(t, walletMember)
+	assert.Equal(t, serviceerror.WalletOwnerInvited, err)
+}
+
+func TestAcceptInvitation_AnotherUser_Error(t *testing.T) {
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+
+	mockWalletMemberRepository := mock.NewMockWalletMemberRepository(ctl)
+	walletMemberService := member.NewWalletMemberService(&repository.Manager{
+		WalletMember: mockWalletMemberRepository,
+	})
+
+	mockWalletMemberRepository.
+		EXPECT().
+		GetWalletMemberById(gomock.Any(), uint64(7)).
+		Times(1).
+		Return(&entity.WalletMember{Id: 7, WalletId: 2, UserId: 3, Role: entity.WalletRoleViewer}, nil)
+
+	walletMember, err := walletMemberService.AcceptInvitation(context.Background(), model.InvitationDTO{Id: 7, UserId: 4})
+
+	assert.Nil(t, walletMember)
+	assert.Equal(t, serviceerror.InvitationDoesntExist, err)
+}
+
+func TestSharedWalletLifecycle_InMemory(t *testing.T) {
+	repositories := memory.NewRepositoryManager()
+	walletService := wallet.NewWalletService(repositories)
+	walletMemberService := member.NewWalletMemberService(repositories)
+	ownerId, memberId := uint64(1), uint64(2)
+
+	shared, err := walletService.CreateWallet(context.Background(), model.WalletCreateDTO{UserId: ownerId, Name: "Household", Currency: "EUR"})
+	assert.NoError(t, err)
+
+	invitation, err := walletMemberService.InviteWalletMember(context.Background(), model.WalletMemberInviteDTO{
+		UserId:    ownerId,
+		WalletId:  shared.Id,
+		InviteeId: memberId,
+		Role:      entity.WalletRoleViewer,
+	})
+	assert.NoError(t, err)
+
+	_, err = walletService.GetWalletById(context.Background(), memberId, shared.Id)
+	assert.ErrorIs(t, err, serviceerror.WalletDoesntBelongToUser, "pending invitation grants no access")
+
+	invitations, err := walletMemberService.GetInvitations(context.Background(), memberId)
+	assert.NoError(t, err)
+	assert.Len(t, invitations, 1)
+
+	accepted, err := walletMemberService.AcceptInvitation(context.Background(), model.InvitationDTO{Id: invitation.Id, UserId: memberId})
+	assert.NoError(t, err)
+	assert.True(t, accepted.IsAccepted())
+
+	_, err = walletService.GetWalletById(context.Background(), memberId, shared.Id)
+	assert.NoError(t, err)
+	wallets, _, err := walletService.GetWalletsByUserId(context.Background(), memberId, model.WalletListQuery{})
+	assert.NoError(t, err)
+	assert.Len(t, wallets, 1)
+
+	_, err = walletService.UpdateWallet(context.Background(), model.WalletUpdateDTO{Id: shared.Id, UserId: memberId, Name: ptr("Ours")})
+	assert.ErrorIs(t, err, serviceerror.WalletPermissionDenied)
+	err = walletService.DeleteWallet(context.Background(), model.WalletDeleteDTO{Id: shared.Id, UserId: memberId})
+	assert.ErrorIs(t, err, serviceerror.WalletPermissionDenied)
+
+	err = walletMemberService.RemoveWalletMember(context.Background(), model.WalletMemberRemoveDTO{Id: accepted.Id, UserId: memberId, WalletId: shared.Id})
+	assert.NoError(t, err, "members can leave the wallet")
+
+	_, err = walletService.GetWalletById(context.Background(), memberId, shared.Id)
+	assert.ErrorIs(t, err, serviceerror.WalletDoesntBelongToUser)
+}
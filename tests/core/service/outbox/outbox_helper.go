@@ -0,0 +1,38 @@
+package outbox
+
+import (
+	"context"
+	"github.com/khivuksergey/portmonetka.wallet/internal/adapter/storage/entity"
+)
+
+// recordingPublisher keeps published events and fails publishing the event with failOnId, if set.
+// onPublish, if set, is called before publishing every event.
+type recordingPublisher struct {
+	published []entity.OutboxEvent
+	failOnId  uint64
+	err       error
+	onPublish func()
+}
+
+func (p *recordingPublisher) Publish(_ context.Context, outboxEvent entity.OutboxEvent) error {
+	if p.onPublish != nil {
+		p.onPublish()
+	}
+	if outboxEvent.Id == p.failOnId {
+		return p.err
+	}
+	p.published = append(p.published, outboxEvent)
+	return nil
+}
+
+func (p *recordingPublisher) Close() error {
+	return nil
+}
+
+func eventTypes(outboxEvents []entity.OutboxEvent) []string {
+	types := make([]string, len(outboxEvents))
+	for i, outboxEvent := range outboxEvents {
+		types[i] = outboxEvent.Type
+	}
+	return types
+}
@@ -0,0 +1,137 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/khivuksergey/portmonetka.wallet/internal/adapter/storage/entity"
+	"github.com/khivuksergey/portmonetka.wallet/internal/adapter/storage/memory"
+	"github.com/khivuksergey/portmonetka.wallet/internal/core/service"
+	"github.com/khivuksergey/portmonetka.wallet/internal/model"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestRelayEvents_PublishesWalletEventsInOrder(t *testing.T) {
+	repositories := memory.NewRepositoryManager()
+	services := service.NewServiceManager(repositories)
+	ctx := model.ContextWithRequestInfo(context.Background(), model.RequestInfo{ActorId: 1, RequestUuid: "request-1"})
+
+	created, err := services.Wallet.CreateWallet(ctx, model.WalletCreateDTO{UserId: 1, Name: "Cash", Currency: "USD", InitialAmount: decimal.NewFromInt(10)})
+	assert.NoError(t, err)
+	name := "Pocket"
+	_, err = services.Wallet.UpdateWallet(ctx, model.WalletUpdateDTO{Id: created.Id, UserId: 1, Name: &name})
+	assert.NoError(t, err)
+	assert.NoError(t, services.Wallet.DeleteWallet(ctx, model.WalletDeleteDTO{Id: created.Id, UserId: 1}))
+	_, err = services.Wallet.RestoreWallet(ctx, model.WalletTrashDTO{Id: created.Id, UserId: 1})
+	assert.NoError(t, err)
+
+	publisher := &recordingPublisher{}
+	relayed, err := services.Outbox.RelayEvents(context.Background(), publisher, 3, time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, relayed)
+	relayed, err = services.Outbox.RelayEvents(context.Background(), publisher, 3, time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, relayed)
+
+	assert.Equal(t, []string{
+		entity.WalletCreatedEvent,
+		entity.WalletUpdatedEvent,
+		entity.WalletDeletedEvent,
+		entity.WalletRestoredEvent,
+	}, eventTypes(publisher.published))
+
+	var updated model.WalletEvent
+	assert.NoError(t, json.Unmarshal(publisher.published[1].Payload, &updated))
+	assert.Equal(t, created.Id, publisher.published[1].AggregateId)
+	assert.Equal(t, "Pocket", updated.Wallet.Name)
+	assert.Equal(t, map[string]entity.FieldChange{"name": {Before: "Cash", After: "Pocket"}}, updated.Changes)
+	assert.Equal(t, uint64(1), updated.ActorId)
+	assert.Equal(t, "request-1", updated.RequestUuid)
+
+	var deleted model.WalletEvent
+	assert.NoError(t, json.Unmarshal(publisher.published[2].Payload, &deleted))
+	assert.Equal(t, "Pocket", deleted.Wallet.Name)
+	assert.Empty(t, deleted.Changes)
+
+	pending, err := repositories.Outbox.ClaimOutboxEvents(context.Background(), 10, time.Now().Add(time.Minute))
+	assert.NoError(t, err)
+	assert.Empty(t, pending)
+}
+
+func TestRelayEvents_RetriesFromFailedEvent(t *testing.T) {
+	repositories := memory.NewRepositoryManager()
+	services := service.NewServiceManager(repositories)
+
+	for _, name := range []string{"Cash", "Card", "Savings"} {
+		_, err := services.Wallet.CreateWallet(context.Background(), model.WalletCreateDTO{UserId: 1, Name: name, Currency: "USD"})
+		assert.NoError(t, err)
+	}
+
+	failure := errors.New("broker unavailable")
+	publisher := &recordingPublisher{failOnId: 2, err: failure}
+	relayed, err := services.Outbox.RelayEvents(context.Background(), publisher, 10, time.Minute)
+	assert.ErrorIs(t, err, failure)
+	assert.Equal(t, 1, relayed)
+
+	publisher.failOnId = 0
+	relayed, err = services.Outbox.RelayEvents(context.Background(), publisher, 10, time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, relayed)
+
+	ids := make([]uint64, len(publisher.published))
+	for i, outboxEvent := range publisher.published {
+		ids[i] = outboxEvent.Id
+	}
+	assert.Equal(t, []uint64{1, 2, 3}, ids)
+}
+
+func TestRelayEvents_ClaimedEventsAreSkippedWhilePublishing(t *testing.T) {
+	repositories := memory.NewRepositoryManager()
+	services := service.NewServiceManager(repositories)
+
+	for _, name := range []string{"Cash", "Card"} {
+		_, err := services.Wallet.CreateWallet(context.Background(), model.WalletCreateDTO{UserId: 1, Name: name, Currency: "USD"})
+		assert.NoError(t, err)
+	}
+
+	// another relay running while the first one publishes must find nothing to claim
+	concurrent := &recordingPublisher{}
+	publisher := &recordingPublisher{onPublish: func() {
+		relayed, err := services.Outbox.RelayEvents(context.Background(), concurrent, 10, time.Minute)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, relayed)
+	}}
+	relayed, err := services.Outbox.RelayEvents(context.Background(), publisher, 10, time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, relayed)
+	assert.Empty(t, concurrent.published)
+}
+
+func TestDeletePublishedEvents(t *testing.T) {
+	repositories := memory.NewRepositoryManager()
+	services := service.NewServiceManager(repositories)
+
+	for _, name := range []string{"Cash", "Card"} {
+		_, err := services.Wallet.CreateWallet(context.Background(), model.WalletCreateDTO{UserId: 1, Name: name, Currency: "USD"})
+		assert.NoError(t, err)
+	}
+	relayed, err := services.Outbox.RelayEvents(context.Background(), &recordingPublisher{}, 1, time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, relayed)
+
+	deleted, err := services.Outbox.DeletePublishedEvents(context.Background(), time.Now().Add(-time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), deleted)
+
+	deleted, err = services.Outbox.DeletePublishedEvents(context.Background(), time.Now().Add(time.Second))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+
+	publisher := &recordingPublisher{}
+	relayed, err = services.Outbox.RelayEvents(context.Background(), publisher, 10, time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, relayed, "unpublished events must survive the cleanup")
+}
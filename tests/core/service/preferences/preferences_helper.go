@@ -0,0 +1,5 @@
+package preferences
+
+func ptr[T any](t T) *T {
+	return &t
+}
@@ -0,0 +1,159 @@
+package preferences
+
+import (
+	"context"
+	"errors"
+	serviceerror "github.com/khivuksergey/portmonetka.wallet/error"
+	"github.com/khivuksergey/portmonetka.wallet/internal/adapter/storage/entity"
+	"github.com/khivuksergey/portmonetka.wallet/internal/adapter/storage/gorm/repo/mock"
+	"github.com/khivuksergey/portmonetka.wallet/internal/core/port/repository"
+	"github.com/khivuksergey/portmonetka.wallet/internal/core/service/preferences"
+	"github.com/khivuksergey/portmonetka.wallet/internal/model"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"testing"
+)
+
+func TestGetPreferences_Defaults(t *testing.T) {
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+
+	mockPreferencesRepository := mock.NewMockPreferencesRepository(ctl)
+	preferencesService := preferences.NewPreferencesService(&repository.Manager{Preferences: mockPreferencesRepository})
+
+	mockPreferencesRepository.
+		EXPECT().
+		GetPreferences(gomock.Any(), uint64(1)).
+		Times(1).
+		Return(nil, nil)
+
+	result, err := preferencesService.GetPreferences(context.Background(), 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &entity.Preferences{
+		UserId:        1,
+		Locale:        model.DefaultLocale,
+		MonthStartDay: model.DefaultMonthStartDay,
+	}, result)
+}
+
+func TestUpdatePreferences_Success(t *testing.T) {
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+
+	mockPreferencesRepository := mock.NewMockPreferencesRepository(ctl)
+	mockWalletRepository := mock.NewMockWalletRepository(ctl)
+	preferencesService := preferences.NewPreferencesService(&repository.Manager{
+		Preferences: mockPreferencesRepository,
+		Wallet:      mockWalletRepository,
+	})
+
+	mockWalletRepository.
+		EXPECT().
+		GetWalletById(gomock.Any(), uint64(10)).
+		Times(1).
+		Return(&entity.Wallet{Id: 10, UserId: 1}, nil)
+
+	expected := &entity.Preferences{
+		UserId:          1,
+		BaseCurrency:    "EUR",
+		DefaultWalletId: ptr(uint64(10)),
+		Locale:          model.DefaultLocale,
+		MonthStartDay:   25,
+	}
+	mockPreferencesRepository.
+		EXPECT().
+		SavePreferences(gomock.Any(), expected).
+		Times(1).
+		Return(expected, nil)
+
+	result, err := preferencesService.UpdatePreferences(context.Background(), model.PreferencesUpdateDTO{
+		UserId:          1,
+		BaseCurrency:    "eur",
+		DefaultWalletId: ptr(uint64(10)),
+		MonthStartDay:   25,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, expected, result)
+}
+
+func TestUpdatePreferences_ForeignDefaultWallet_Error(t *testing.T) {
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+
+	mockPreferencesRepository := mock.NewMockPreferencesRepository(ctl)
+	mockWalletRepository := mock.NewMockWalletRepository(ctl)
+	mockWalletMemberRepository := mock.NewMockWalletMemberRepository(ctl)
+	preferencesService := preferences.NewPreferencesService(&repository.Manager{
+		Preferences:  mockPreferencesRepository,
+		Wallet:       mockWalletRepository,
+		WalletMember: mockWalletMemberRepository,
+	})
+
+	mockWalletRepository.
+		EXPECT().
+		GetWalletById(gomock.Any(), uint64(10)).
+		Times(1).
+		Return(&entity.Wallet{Id: 10, UserId: 2}, nil)
+
+	mockWalletMemberRepository.
+		EXPECT().
+		GetWalletMember(gomock.Any(), uint64(10), uint64(1)).
+		Times(1).
+		Return(nil, nil)
+
+	result, err := preferencesService.UpdatePreferences(context.Background(), model.PreferencesUpdateDTO{
+		UserId:          1,
+		DefaultWalletId: ptr(uint64(10)),
+	})
+
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, serviceerror.WalletDoesntBelongToUser)
+}
+
+func TestUpdatePreferences_DefaultWalletDoesntExist_Error(t *testing.T) {
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+
+	mockWalletRepository := mock.NewMockWalletRepository(ctl)
+	preferencesService := preferences.NewPreferencesService(&repository.Manager{Wallet: mockWalletRepository})
+
+	mockWalletRepository.
+		EXPECT().
+		GetWalletById(gomock.Any(), uint64(10)).
+		Times(1).
+		Return(nil, repository.ErrNotFound)
+
+	result, err := preferencesService.UpdatePreferences(context.Background(), model.PreferencesUpdateDTO{
+		UserId:          1,
+		DefaultWalletId: ptr(uint64(10)),
+	})
+
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, serviceerror.WalletDoesntExist)
+}
+
+func TestUpdatePreferences_WalletLookupFailure_Error(t *testing.T) {
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+
+	mockWalletRepository := mock.NewMockWalletRepository(ctl)
+	preferencesService := preferences.NewPreferencesService(&repository.Manager{Wallet: mockWalletRepository})
+
+	failure := errors.New("connection refused")
+	mockWalletRepository.
+		EXPECT().
+		GetWalletById(gomock.Any(), uint64(10)).
+		Times(1).
+		Return(nil, failure)
+
+	result, err := preferencesService.UpdatePreferences(context.Background(), model.PreferencesUpdateDTO{
+		UserId:          1,
+		DefaultWalletId: ptr(uint64(10)),
+	})
+
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, failure)
+	assert.NotErrorIs(t, err, serviceerror.WalletDoesntExist)
+}
@@ -0,0 +1,5 @@
+package transaction
+
+func ptr[T any](t T) *T {
+	return &t
+}
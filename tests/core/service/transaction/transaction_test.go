@@ -0,0 +1,442 @@
+package transaction
+
+import (
+	"context"
+	serviceerror "github.com/khivuksergey/portmonetka.wallet/error"
+	"github.com/khivuksergey/portmonetka.wallet/internal/adapter/storage/entity"
+	"github.com/khivuksergey/portmonetka.wallet/internal/adapter/storage/gorm/repo/mock"
+	"github.com/khivuksergey/portmonetka.wallet/internal/core/port/repository"
+	"github.com/khivuksergey/portmonetka.wallet/internal/core/service/transaction"
+	"github.com/khivuksergey/portmonetka.wallet/internal/model"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+)
+
+func TestGetTransactionsByWalletId_Success(t *testing.T) {
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+
+	mockWalletRepository := mock.NewMockWalletRepository(ctl)
+	mockTransactionRepository := mock.NewMockTransactionRepository(ctl)
+	mockManager := mock.WithPassThroughUnitOfWork(&repository.Manager{
+		Wallet:      mockWalletRepository,
+		Transaction: mockTransactionRepository,
+	})
+
+	transactionService := transaction.NewTransactionService(mockManager)
+
+	userId, walletId := uint64(1), uint64(2)
+	expectedTransactions := []entity.Transaction{
+		{
+			Id:        1,
+			WalletId:  walletId,
+			Amount:    decimal.NewFromFloat(10.50),
+			Direction: entity.TransactionDirectionOut,
+			Category:  "Groceries",
+		},
+		{
+			Id:        2,
+			WalletId:  walletId,
+			Amount:    decimal.NewFromFloat(1000.00),
+			Direction: entity.TransactionDirectionIn,
+			Category:  "Salary",
+		},
+	}
+
+	mockWalletRepository.
+		EXPECT().
+		GetWalletById(gomock.Any(), walletId).
+		Times(1).
+		Return(&entity.Wallet{Id: walletId, UserId: userId}, nil)
+
+	mockTransactionRepository.
+		EXPECT().
+		GetTransactionsByWalletId(gomock.Any(), walletId).
+		Times(1).
+		Return(expectedTransactions, nil)
+
+	actualTransactions, err := transactionService.GetTransactionsByWalletId(context.Background(), userId, walletId)
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedTransactions, actualTransactions)
+}
+
+func TestCreateTransaction_Success(t *testing.T) {
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+
+	mockWalletRepository := mock.NewMockWalletRepository(ctl)
+	mockTransactionRepository := mock.NewMockTransactionRepository(ctl)
+	mockManager := mock.WithPassThroughUnitOfWork(&repository.Manager{
+		Wallet:      mockWalletRepository,
+		Transaction: mockTransactionRepository,
+	})
+
+	transactionService := transaction.NewTransactionService(mockManager)
+
+	transactionCreateDTO := &model.TransactionCreateDTO{
+		UserId:    1,
+		WalletId:  2,
+		Amount:    decimal.NewFromFloat(12.34),
+		Direction: entity.TransactionDirectionOut,
+		Timestamp: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+		Note:      "Lunch",
+		Category:  "Food",
+	}
+
+	expectedTransaction := &entity.Transaction{
+		WalletId:  transactionCreateDTO.WalletId,
+		Amount:    transactionCreateDTO.Amount,
+		Direction: transactionCreateDTO.Direction,
+		Timestamp: transactionCreateDTO.Timestamp,
+		Note:      transactionCreateDTO.Note,
+		Category:  transactionCreateDTO.Category,
+	}
+
+	mockWalletRepository.
+		EXPECT().
+		GetWalletForUpdate(gomock.Any(), transactionCreateDTO.WalletId).
+		Times(1).
+		Return(&entity.Wallet{Id: 2, UserId: 1, CurrentBalance: decimal.NewFromInt(100)}, nil)
+
+	mockTransactionRepository.
+		EXPECT().
+		CreateTransaction(gomock.Any(), expectedTransaction).
+		Times(1).
+		Return(expectedTransaction, nil)
+
+	createdTransaction, err := transactionService.CreateTransaction(context.Background(), *transactionCreateDTO)
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedTransaction, createdTransaction)
+}
+
+func TestCreateTransaction_WalletDoesntBelongToUser_Error(t *testing.T) {
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+
+	mockWalletRepository := mock.NewMockWalletRepository(ctl)
+	mockTransactionRepository := mock.NewMockTransactionRepository(ctl)
+	mockWalletMemberRepository := mock.NewMockWalletMemberRepository(ctl)
+	mockManager := mock.WithPassThroughUnitOfWork(&repository.Manager{
+		Wallet:       mockWalletRepository,
+		Transaction:  mockTransactionRepository,
+		WalletMember: mockWalletMemberRepository,
+	})
+
+	transactionService := transaction.NewTransactionService(mockManager)
+
+	transactionCreateDTO := &model.TransactionCreateDTO{
+		UserId:    1,
+		WalletId:  3,
+		Amount:    decimal.NewFromFloat(12.34),
+		Direction: entity.TransactionDirectionIn,
+	}
+
+	mockWalletRepository.
+		EXPECT().
+		GetWalletForUpdate(gomock.Any(), transactionCreateDTO.WalletId).
+		Times(1).
+		Return(&entity.Wallet{Id: 3, UserId: 5}, nil)
+
+	mockWalletMemberRepository.
+		EXPECT().
+		GetWalletMember(gomock.Any(), transactionCreateDTO.WalletId, transactionCreateDTO.UserId).
+		Times(1).
+		Return(nil, nil)
+
+	createdTransaction, err := transactionService.CreateTransaction(context.Background(), *transactionCreateDTO)
+
+	assert.Error(t, err)
+	assert.Nil(t, createdTransaction)
+	assert.Equal(t, serviceerror.WalletDoesntBelongToUser, err)
+}
+
+func TestCreateTransaction_ViewerMember_Error(t *testing.T) {
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+
+	mockWalletRepository := mock.NewMockWalletRepository(ctl)
+	mockTransactionRepository := mock.NewMockTransactionRepository(ctl)
+	mockWalletMemberRepository := mock.NewMockWalletMemberRepository(ctl)
+	mockManager := mock.WithPassThroughUnitOfWork(&repository.Manager{
+		Wallet:       mockWalletRepository,
+		Transaction:  mockTransactionRepository,
+		WalletMember: mockWalletMemberRepository,
+	})
+
+	transactionService := transaction.NewTransactionService(mockManager)
+
+	transactionCreateDTO := &model.TransactionCreateDTO{
+		UserId:    1,
+		WalletId:  3,
+		Amount:    decimal.NewFromFloat(12.34),
+		Direction: entity.TransactionDirectionIn,
+	}
+
+	mockWalletRepository.
+		EXPECT().
+		GetWalletForUpdate(gomock.Any(), transactionCreateDTO.WalletId).
+		Times(1).
+		Return(&entity.Wallet{Id: 3, UserId: 5}, nil)
+
+	mockWalletMemberRepository.
+		EXPECT().
+		GetWalletMember(gomock.Any(), transactionCreateDTO.WalletId, transactionCreateDTO.UserId).
+		Times(1).
+		Return(&entity.WalletMember{
+			WalletId:   3,
+			UserId:     1,
+			Role:       entity.WalletRoleViewer,
+			AcceptedAt: ptr(time.Now()),
+		}, nil)
+
+	createdTransaction, err := transactionService.CreateTransaction(context.Background(), *transactionCreateDTO)
+
+	assert.Nil(t, createdTransaction)
+	assert.Equal(t, serviceerror.WalletPermissionDenied, err)
+}
+
+func TestCreateTransaction_BelowBalanceFloor_Error(t *testing.T) {
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+
+	mockWalletRepository := mock.NewMockWalletRepository(ctl)
+	mockTransactionRepository := mock.NewMockTransactionRepository(ctl)
+	mockManager := mock.WithPassThroughUnitOfWork(&repository.Manager{
+		Wallet:      mockWalletRepository,
+		Transaction: mockTransactionRepository,
+	})
+
+	transactionService := transaction.NewTransactionService(mockManager)
+
+	transactionCreateDTO := &model.TransactionCreateDTO{
+		UserId:    1,
+		WalletId:  2,
+		Amount:    decimal.NewFromInt(150),
+		Direction: entity.TransactionDirectionOut,
+	}
+
+	mockWalletRepository.
+		EXPECT().
+		GetWalletForUpdate(gomock.Any(), transactionCreateDTO.WalletId).
+		Times(1).
+		Return(&entity.Wallet{
+			Id:             2,
+			UserId:         1,
+			CreditLimit:    ptr(decimal.NewFromInt(40)),
+			CurrentBalance: decimal.NewFromInt(100),
+		}, nil)
+
+	createdTransaction, err := transactionService.CreateTransaction(context.Background(), *transactionCreateDTO)
+
+	assert.Nil(t, createdTransaction)
+	assert.ErrorIs(t, err, serviceerror.InsufficientFunds)
+	assert.Equal(t, serviceerror.NewInsufficientFundsError(2, decimal.NewFromInt(-50), decimal.NewFromInt(-40)), err)
+}
+
+func TestCreateTransaction_NonPositiveAmount_Error(t *testing.T) {
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+
+	mockWalletRepository := mock.NewMockWalletRepository(ctl)
+	mockTransactionRepository := mock.NewMockTransactionRepository(ctl)
+	mockManager := mock.WithPassThroughUnitOfWork(&repository.Manager{
+		Wallet:      mockWalletRepository,
+		Transaction: mockTransactionRepository,
+	})
+
+	transactionService := transaction.NewTransactionService(mockManager)
+
+	transactionCreateDTO := &model.TransactionCreateDTO{
+		UserId:    1,
+		WalletId:  2,
+		Amount:    decimal.NewFromFloat(-5),
+		Direction: entity.TransactionDirectionOut,
+	}
+
+	mockWalletRepository.
+		EXPECT().
+		GetWalletForUpdate(gomock.Any(), transactionCreateDTO.WalletId).
+		Times(1).
+		Return(&entity.Wallet{Id: 2, UserId: 1}, nil)
+
+	createdTransaction, err := transactionService.CreateTransaction(context.Background(), *transactionCreateDTO)
+
+	assert.Error(t, err)
+	assert.Nil(t, createdTransaction)
+	assert.Equal(t, serviceerror.TransactionAmountError, err)
+}
+
+func TestCreateTransaction_AmountPrecision_Error(t *testing.T) {
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+
+	mockWalletRepository := mock.NewMockWalletRepository(ctl)
+	mockTransactionRepository := mock.NewMockTransactionRepository(ctl)
+	mockManager := mock.WithPassThroughUnitOfWork(&repository.Manager{
+		Wallet:      mockWalletRepository,
+		Transaction: mockTransactionRepository,
+	})
+
+	transactionService := transaction.NewTransactionService(mockManager)
+
+	transactionCreateDTO := &model.TransactionCreateDTO{
+		UserId:    1,
+		WalletId:  2,
+		Amount:    decimal.RequireFromString("0.001"),
+		Direction: entity.TransactionDirectionIn,
+	}
+
+	mockWalletRepository.
+		EXPECT().
+		GetWalletForUpdate(gomock.Any(), transactionCreateDTO.WalletId).
+		Times(1).
+		Return(&entity.Wallet{Id: 2, UserId: 1, Currency: "JPY"}, nil)
+
+	createdTransaction, err := transactionService.CreateTransaction(context.Background(), *transactionCreateDTO)
+
+	assert.Nil(t, createdTransaction)
+	assert.Equal(t, serviceerror.TransactionAmountPrecisionError, err)
+}
+
+func TestUpdateTransaction_Success(t *testing.T) {
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+
+	mockWalletRepository := mock.NewMockWalletRepository(ctl)
+	mockTransactionRepository := mock.NewMockTransactionRepository(ctl)
+	mockManager := mock.WithPassThroughUnitOfWork(&repository.Manager{
+		Wallet:      mockWalletRepository,
+		Transaction: mockTransactionRepository,
+	})
+
+	transactionService := transaction.NewTransactionService(mockManager)
+
+	transactionUpdateDTO := &model.TransactionUpdateDTO{
+		Id:       5,
+		UserId:   1,
+		WalletId: 2,
+		Amount:   ptr[decimal.Decimal](decimal.NewFromFloat(20.00)),
+		Note:     ptr[string]("Dinner"),
+	}
+
+	existingTransaction := &entity.Transaction{
+		Id:        5,
+		WalletId:  2,
+		Amount:    decimal.NewFromFloat(12.34),
+		Direction: entity.TransactionDirectionOut,
+		Note:      "Lunch",
+	}
+
+	updatedTransaction := &entity.Transaction{
+		Id:        5,
+		WalletId:  2,
+		Amount:    decimal.NewFromFloat(20.00),
+		Direction: entity.TransactionDirectionOut,
+		Note:      "Dinner",
+	}
+
+	mockTransactionRepository.
+		EXPECT().
+		GetTransactionById(gomock.Any(), transactionUpdateDTO.Id).
+		Times(1).
+		Return(existingTransaction, nil)
+
+	mockWalletRepository.
+		EXPECT().
+		GetWalletForUpdate(gomock.Any(), transactionUpdateDTO.WalletId).
+		Times(1).
+		Return(&entity.Wallet{Id: 2, UserId: 1, CurrentBalance: decimal.NewFromInt(100)}, nil)
+
+	mockTransactionRepository.
+		EXPECT().
+		UpdateTransaction(gomock.Any(), existingTransaction).
+		Times(1).
+		DoAndReturn(func(_ context.Context, transaction *entity.Transaction) (*entity.Transaction, error) {
+			return transaction, nil
+		})
+
+	updatedTransactionFromService, err := transactionService.UpdateTransaction(context.Background(), *transactionUpdateDTO)
+
+	assert.NoError(t, err)
+	assert.Equal(t, updatedTransaction, updatedTransactionFromService)
+}
+
+func TestUpdateTransaction_AmountPrecision_Error(t *testing.T) {
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+
+	mockWalletRepository := mock.NewMockWalletRepository(ctl)
+	mockTransactionRepository := mock.NewMockTransactionRepository(ctl)
+	mockManager := mock.WithPassThroughUnitOfWork(&repository.Manager{
+		Wallet:      mockWalletRepository,
+		Transaction: mockTransactionRepository,
+	})
+
+	transactionService := transaction.NewTransactionService(mockManager)
+
+	transactionUpdateDTO := &model.TransactionUpdateDTO{
+		Id:       5,
+		UserId:   1,
+		WalletId: 2,
+		Amount:   ptr(decimal.RequireFromString("0.123")),
+	}
+
+	mockWalletRepository.
+		EXPECT().
+		GetWalletForUpdate(gomock.Any(), transactionUpdateDTO.WalletId).
+		Times(1).
+		Return(&entity.Wallet{Id: 2, UserId: 1, Currency: "USD", CurrentBalance: decimal.NewFromInt(100)}, nil)
+
+	mockTransactionRepository.
+		EXPECT().
+		GetTransactionById(gomock.Any(), transactionUpdateDTO.Id).
+		Times(1).
+		Return(&entity.Transaction{Id: 5, WalletId: 2, Amount: decimal.NewFromInt(1), Direction: entity.TransactionDirectionOut}, nil)
+
+	updatedTransaction, err := transactionService.UpdateTransaction(context.Background(), *transactionUpdateDTO)
+
+	assert.Nil(t, updatedTransaction)
+	assert.Equal(t, serviceerror.TransactionAmountPrecisionError, err)
+}
+
+func TestDeleteTransaction_TransactionFromAnotherWallet_Error(t *testing.T) {
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+
+	mockWalletRepository := mock.NewMockWalletRepository(ctl)
+	mockTransactionRepository := mock.NewMockTransactionRepository(ctl)
+	mockManager := mock.WithPassThroughUnitOfWork(&repository.Manager{
+		Wallet:      mockWalletRepository,
+		Transaction: mockTransactionRepository,
+	})
+
+	transactionService := transaction.NewTransactionService(mockManager)
+
+	transactionDeleteDTO := &model.TransactionDeleteDTO{
+		Id:       5,
+		UserId:   1,
+		WalletId: 2,
+	}
+
+	mockWalletRepository.
+		EXPECT().
+		GetWalletForUpdate(gomock.Any(), transactionDeleteDTO.WalletId).
+		Times(1).
+		Return(&entity.Wallet{Id: 2, UserId: 1}, nil)
+
+	mockTransactionRepository.
+		EXPECT().
+		GetTransactionById(gomock.Any(), transactionDeleteDTO.Id).
+		Times(1).
+		Return(&entity.Transaction{Id: 5, WalletId: 9}, nil)
+
+	err := transactionService.DeleteTransaction(context.Background(), *transactionDeleteDTO)
+
+	assert.Error(t, err)
+	assert.Equal(t, serviceerror.TransactionDoesntExist, err)
+}
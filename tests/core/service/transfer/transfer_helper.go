@@ -0,0 +1,5 @@
+package transfer
+
+func ptr[T any](t T) *T {
+	return &t
+}
@@ -0,0 +1,511 @@
+package transfer
+
+import (
+	"context"
+	"errors"
+	serviceerror "github.com/khivuksergey/portmonetka.wallet/error"
+	"github.com/khivuksergey/portmonetka.wallet/internal/adapter/storage/entity"
+	"github.com/khivuksergey/portmonetka.wallet/internal/adapter/storage/gorm/repo/mock"
+	"github.com/khivuksergey/portmonetka.wallet/internal/core/port/repository"
+	"github.com/khivuksergey/portmonetka.wallet/internal/core/service/transfer"
+	"github.com/khivuksergey/portmonetka.wallet/internal/model"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+)
+
+var timestamp = time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+func TestCreateTransfer_SameCurrency_Success(t *testing.T) {
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+
+	mockWalletRepository := mock.NewMockWalletRepository(ctl)
+	mockTransferRepository := mock.NewMockTransferRepository(ctl)
+	mockManager := mock.WithPassThroughUnitOfWork(&repository.Manager{
+		Wallet:   mockWalletRepository,
+		Transfer: mockTransferRepository,
+	})
+
+	transferService := transfer.NewTransferService(mockManager)
+
+	transferCreateDTO := &model.TransferCreateDTO{
+		UserId:         1,
+		SourceWalletId: 1,
+		TargetWalletId: 2,
+		Amount:         decimal.NewFromFloat(50),
+		Timestamp:      timestamp,
+	}
+
+	mockWalletRepository.
+		EXPECT().
+		GetWalletForUpdate(gomock.Any(), transferCreateDTO.SourceWalletId).
+		Times(1).
+		Return(&entity.Wallet{Id: 1, UserId: 1, Currency: "USD", CurrentBalance: decimal.NewFromInt(100)}, nil)
+
+	mockWalletRepository.
+		EXPECT().
+		GetWalletById(gomock.Any(), transferCreateDTO.TargetWalletId).
+		Times(1).
+		Return(&entity.Wallet{Id: 2, UserId: 1, Currency: "USD"}, nil)
+
+	mockTransferRepository.
+		EXPECT().
+		CreateTransfer(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ context.Context, transfer *entity.Transfer) (*entity.Transfer, error) {
+			return transfer, nil
+		})
+
+	createdTransfer, err := transferService.CreateTransfer(context.Background(), *transferCreateDTO)
+
+	assert.NoError(t, err)
+	assert.True(t, createdTransfer.TargetAmount.Equal(transferCreateDTO.Amount))
+	assert.True(t, createdTransfer.Rate.Equal(decimal.NewFromInt(1)))
+	assert.Len(t, createdTransfer.Transactions, 2)
+	assert.Equal(t, entity.Transaction{
+		WalletId:  1,
+		Amount:    transferCreateDTO.Amount,
+		Direction: entity.TransactionDirectionOut,
+		Timestamp: timestamp,
+		Category:  entity.TransferCategory,
+	}, createdTransfer.Transactions[0])
+	assert.Equal(t, entity.Transaction{
+		WalletId:  2,
+		Amount:    transferCreateDTO.Amount,
+		Direction: entity.TransactionDirectionIn,
+		Timestamp: timestamp,
+		Category:  entity.TransferCategory,
+	}, createdTransfer.Transactions[1])
+}
+
+func TestCreateTransfer_CrossCurrencyTargetAmount_Success(t *testing.T) {
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+
+	mockWalletRepository := mock.NewMockWalletRepository(ctl)
+	mockTransferRepository := mock.NewMockTransferRepository(ctl)
+	mockManager := mock.WithPassThroughUnitOfWork(&repository.Manager{
+		Wallet:   mockWalletRepository,
+		Transfer: mockTransferRepository,
+	})
+
+	transferService := transfer.NewTransferService(mockManager)
+
+	transferCreateDTO := &model.TransferCreateDTO{
+		UserId:         1,
+		SourceWalletId: 1,
+		TargetWalletId: 2,
+		Amount:         decimal.NewFromFloat(100),
+		TargetAmount:   ptr[decimal.Decimal](decimal.NewFromFloat(92.5)),
+	}
+
+	mockWalletRepository.
+		EXPECT().
+		GetWalletForUpdate(gomock.Any(), transferCreateDTO.SourceWalletId).
+		Times(1).
+		Return(&entity.Wallet{Id: 1, UserId: 1, Currency: "USD", CurrentBalance: decimal.NewFromInt(100)}, nil)
+
+	mockWalletRepository.
+		EXPECT().
+		GetWalletById(gomock.Any(), transferCreateDTO.TargetWalletId).
+		Times(1).
+		Return(&entity.Wallet{Id: 2, UserId: 1, Currency: "EUR"}, nil)
+
+	mockTransferRepository.
+		EXPECT().
+		CreateTransfer(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ context.Context, transfer *entity.Transfer) (*entity.Transfer, error) {
+			return transfer, nil
+		})
+
+	createdTransfer, err := transferService.CreateTransfer(context.Background(), *transferCreateDTO)
+
+	assert.NoError(t, err)
+	assert.True(t, createdTransfer.SourceAmount.Equal(decimal.NewFromFloat(100)))
+	assert.True(t, createdTransfer.TargetAmount.Equal(decimal.NewFromFloat(92.5)))
+	assert.True(t, createdTransfer.Rate.Equal(decimal.NewFromFloat(0.925)))
+	assert.True(t, createdTransfer.Transactions[1].Amount.Equal(decimal.NewFromFloat(92.5)))
+}
+
+func TestCreateTransfer_CrossCurrencyRate_RoundsTargetAmount(t *testing.T) {
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+
+	mockWalletRepository := mock.NewMockWalletRepository(ctl)
+	mockTransferRepository := mock.NewMockTransferRepository(ctl)
+	mockManager := mock.WithPassThroughUnitOfWork(&repository.Manager{
+		Wallet:   mockWalletRepository,
+		Transfer: mockTransferRepository,
+	})
+
+	transferService := transfer.NewTransferService(mockManager)
+
+	transferCreateDTO := &model.TransferCreateDTO{
+		UserId:         1,
+		SourceWalletId: 1,
+		TargetWalletId: 2,
+		Amount:         decimal.RequireFromString("10.01"),
+		Rate:           ptr[decimal.Decimal](decimal.RequireFromString("92.345")),
+	}
+
+	mockWalletRepository.
+		EXPECT().
+		GetWalletForUpdate(gomock.Any(), transferCreateDTO.SourceWalletId).
+		Times(1).
+		Return(&entity.Wallet{Id: 1, UserId: 1, Currency: "USD", CurrentBalance: decimal.NewFromInt(100)}, nil)
+
+	mockWalletRepository.
+		EXPECT().
+		GetWalletById(gomock.Any(), transferCreateDTO.TargetWalletId).
+		Times(1).
+		Return(&entity.Wallet{Id: 2, UserId: 1, Currency: "JPY"}, nil)
+
+	mockTransferRepository.
+		EXPECT().
+		CreateTransfer(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ context.Context, transfer *entity.Transfer) (*entity.Transfer, error) {
+			return transfer, nil
+		})
+
+	createdTransfer, err := transferService.CreateTransfer(context.Background(), *transferCreateDTO)
+
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, "924", createdTransfer.TargetAmount.String())
+	assert.Equal(t, "924", createdTransfer.Transactions[1].Amount.String())
+	assert.True(t, createdTransfer.Rate.Equal(*transferCreateDTO.Rate))
+}
+
+func TestCreateTransfer_AmountPrecision_Error(t *testing.T) {
+	tests := []struct {
+		name          string
+		amount        decimal.Decimal
+		targetAmount  decimal.Decimal
+		expectedError error
+	}{
+		{"source amount", decimal.RequireFromString("10.001"), decimal.NewFromInt(924), serviceerror.TransferAmountPrecisionError},
+		{"target amount", decimal.RequireFromString("10.01"), decimal.RequireFromString("924.5"), serviceerror.TransferTargetAmountPrecisionError},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctl := gomock.NewController(t)
+			defer ctl.Finish()
+
+			mockWalletRepository := mock.NewMockWalletRepository(ctl)
+			mockManager := mock.WithPassThroughUnitOfWork(&repository.Manager{Wallet: mockWalletRepository})
+
+			transferService := transfer.NewTransferService(mockManager)
+
+			transferCreateDTO := model.TransferCreateDTO{
+				UserId:         1,
+				SourceWalletId: 1,
+				TargetWalletId: 2,
+				Amount:         test.amount,
+				TargetAmount:   &test.targetAmount,
+			}
+
+			mockWalletRepository.
+				EXPECT().
+				GetWalletForUpdate(gomock.Any(), transferCreateDTO.SourceWalletId).
+				Return(&entity.Wallet{Id: 1, UserId: 1, Currency: "USD", CurrentBalance: decimal.NewFromInt(100)}, nil)
+
+			mockWalletRepository.
+				EXPECT().
+				GetWalletById(gomock.Any(), transferCreateDTO.TargetWalletId).
+				Return(&entity.Wallet{Id: 2, UserId: 1, Currency: "JPY"}, nil)
+
+			createdTransfer, err := transferService.CreateTransfer(context.Background(), transferCreateDTO)
+
+			assert.Nil(t, createdTransfer)
+			assert.Equal(t, test.expectedError, err)
+		})
+	}
+}
+
+func TestCreateTransfer_CrossCurrencyWithoutRate_Error(t *testing.T) {
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+
+	mockWalletRepository := mock.NewMockWalletRepository(ctl)
+	mockTransferRepository := mock.NewMockTransferRepository(ctl)
+	mockManager := mock.WithPassThroughUnitOfWork(&repository.Manager{
+		Wallet:   mockWalletRepository,
+		Transfer: mockTransferRepository,
+	})
+
+	transferService := transfer.NewTransferService(mockManager)
+
+	transferCreateDTO := &model.TransferCreateDTO{
+		UserId:         1,
+		SourceWalletId: 1,
+		TargetWalletId: 2,
+		Amount:         decimal.NewFromFloat(100),
+	}
+
+	mockWalletRepository.
+		EXPECT().
+		GetWalletForUpdate(gomock.Any(), transferCreateDTO.SourceWalletId).
+		Times(1).
+		Return(&entity.Wallet{Id: 1, UserId: 1, Currency: "USD"}, nil)
+
+	mockWalletRepository.
+		EXPECT().
+		GetWalletById(gomock.Any(), transferCreateDTO.TargetWalletId).
+		Times(1).
+		Return(&entity.Wallet{Id: 2, UserId: 1, Currency: "RUB"}, nil)
+
+	createdTransfer, err := transferService.CreateTransfer(context.Background(), *transferCreateDTO)
+
+	assert.Error(t, err)
+	assert.Nil(t, createdTransfer)
+	assert.Equal(t, serviceerror.TransferRateRequired, err)
+}
+
+func TestCreateTransfer_TargetWalletDoesntBelongToUser_Error(t *testing.T) {
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+
+	mockWalletRepository := mock.NewMockWalletRepository(ctl)
+	mockTransferRepository := mock.NewMockTransferRepository(ctl)
+	mockWalletMemberRepository := mock.NewMockWalletMemberRepository(ctl)
+	mockManager := mock.WithPassThroughUnitOfWork(&repository.Manager{
+		Wallet:       mockWalletRepository,
+		Transfer:     mockTransferRepository,
+		WalletMember: mockWalletMemberRepository,
+	})
+
+	transferService := transfer.NewTransferService(mockManager)
+
+	transferCreateDTO := &model.TransferCreateDTO{
+		UserId:         1,
+		SourceWalletId: 1,
+		TargetWalletId: 2,
+		Amount:         decimal.NewFromFloat(100),
+	}
+
+	mockWalletRepository.
+		EXPECT().
+		GetWalletForUpdate(gomock.Any(), transferCreateDTO.SourceWalletId).
+		Times(1).
+		Return(&entity.Wallet{Id: 1, UserId: 1, Currency: "USD"}, nil)
+
+	mockWalletRepository.
+		EXPECT().
+		GetWalletById(gomock.Any(), transferCreateDTO.TargetWalletId).
+		Times(1).
+		Return(&entity.Wallet{Id: 2, UserId: 2, Currency: "USD"}, nil)
+
+	mockWalletMemberRepository.
+		EXPECT().
+		GetWalletMember(gomock.Any(), transferCreateDTO.TargetWalletId, transferCreateDTO.UserId).
+		Times(1).
+		Return(nil, nil)
+
+	createdTransfer, err := transferService.CreateTransfer(context.Background(), *transferCreateDTO)
+
+	assert.Error(t, err)
+	assert.Nil(t, createdTransfer)
+	assert.Equal(t, serviceerror.WalletDoesntBelongToUser, err)
+}
+
+func TestCreateTransfer_InsufficientFunds_Error(t *testing.T) {
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+
+	mockWalletRepository := mock.NewMockWalletRepository(ctl)
+	mockTransferRepository := mock.NewMockTransferRepository(ctl)
+	mockManager := mock.WithPassThroughUnitOfWork(&repository.Manager{
+		Wallet:   mockWalletRepository,
+		Transfer: mockTransferRepository,
+	})
+
+	transferService := transfer.NewTransferService(mockManager)
+
+	transferCreateDTO := &model.TransferCreateDTO{
+		UserId:         1,
+		SourceWalletId: 1,
+		TargetWalletId: 2,
+		Amount:         decimal.NewFromFloat(100.01),
+	}
+
+	mockWalletRepository.
+		EXPECT().
+		GetWalletForUpdate(gomock.Any(), transferCreateDTO.SourceWalletId).
+		Times(1).
+		Return(&entity.Wallet{Id: 1, UserId: 1, Currency: "USD", CurrentBalance: decimal.NewFromInt(100)}, nil)
+
+	mockWalletRepository.
+		EXPECT().
+		GetWalletById(gomock.Any(), transferCreateDTO.TargetWalletId).
+		Times(1).
+		Return(&entity.Wallet{Id: 2, UserId: 1, Currency: "USD"}, nil)
+
+	createdTransfer, err := transferService.CreateTransfer(context.Background(), *transferCreateDTO)
+
+	assert.Nil(t, createdTransfer)
+	assert.ErrorIs(t, err, serviceerror.InsufficientFunds)
+}
+
+func TestCreateTransfer_RunsInUnitOfWork(t *testing.T) {
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+
+	mockWalletRepository := mock.NewMockWalletRepository(ctl)
+	mockTransferRepository := mock.NewMockTransferRepository(ctl)
+	mockUnitOfWork := mock.NewMockUnitOfWork(ctl)
+	scopedManager := &repository.Manager{
+		Wallet:   mockWalletRepository,
+		Transfer: mockTransferRepository,
+	}
+	mockManager := &repository.Manager{
+		UnitOfWork: mockUnitOfWork,
+	}
+
+	transferService := transfer.NewTransferService(mockManager)
+	failure := errors.New("insert failed")
+
+	transferCreateDTO := &model.TransferCreateDTO{
+		UserId:         1,
+		SourceWalletId: 1,
+		TargetWalletId: 2,
+		Amount:         decimal.NewFromFloat(50),
+		Timestamp:      timestamp,
+	}
+
+	mockUnitOfWork.
+		EXPECT().
+		Do(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(ctx context.Context, fn func(context.Context, *repository.Manager) error) error {
+			return fn(ctx, scopedManager)
+		})
+
+	mockWalletRepository.
+		EXPECT().
+		GetWalletForUpdate(gomock.Any(), transferCreateDTO.SourceWalletId).
+		Times(1).
+		Return(&entity.Wallet{Id: 1, UserId: 1, Currency: "USD", CurrentBalance: decimal.NewFromInt(100)}, nil)
+
+	mockWalletRepository.
+		EXPECT().
+		GetWalletById(gomock.Any(), transferCreateDTO.TargetWalletId).
+		Times(1).
+		Return(&entity.Wallet{Id: 2, UserId: 1, Currency: "USD"}, nil)
+
+	mockTransferRepository.
+		EXPECT().
+		CreateTransfer(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(nil, failure)
+
+	createdTransfer, err := transferService.CreateTransfer(context.Background(), *transferCreateDTO)
+
+	assert.ErrorIs(t, err, failure)
+	assert.Nil(t, createdTransfer)
+}
+
+func TestCreateTransfer_WithoutUnitOfWork_Error(t *testing.T) {
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+
+	mockManager := &repository.Manager{
+		Wallet:   mock.NewMockWalletRepository(ctl),
+		Transfer: mock.NewMockTransferRepository(ctl),
+	}
+
+	transferService := transfer.NewTransferService(mockManager)
+
+	createdTransfer, err := transferService.CreateTransfer(context.Background(), model.TransferCreateDTO{
+		UserId:         1,
+		SourceWalletId: 1,
+		TargetWalletId: 2,
+		Amount:         decimal.NewFromFloat(50),
+	})
+
+	assert.Nil(t, createdTransfer)
+	assert.ErrorIs(t, err, repository.ErrNoUnitOfWork)
+}
+
+func TestCreateTransfer_DefaultSourceWallet_Success(t *testing.T) {
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+
+	mockWalletRepository := mock.NewMockWalletRepository(ctl)
+	mockTransferRepository := mock.NewMockTransferRepository(ctl)
+	mockPreferencesRepository := mock.NewMockPreferencesRepository(ctl)
+	mockManager := mock.WithPassThroughUnitOfWork(&repository.Manager{
+		Wallet:      mockWalletRepository,
+		Transfer:    mockTransferRepository,
+		Preferences: mockPreferencesRepository,
+	})
+
+	transferService := transfer.NewTransferService(mockManager)
+
+	mockPreferencesRepository.
+		EXPECT().
+		GetPreferences(gomock.Any(), uint64(1)).
+		Times(1).
+		Return(&entity.Preferences{UserId: 1, DefaultWalletId: ptr(uint64(1))}, nil)
+
+	mockWalletRepository.
+		EXPECT().
+		GetWalletForUpdate(gomock.Any(), uint64(1)).
+		Times(1).
+		Return(&entity.Wallet{Id: 1, UserId: 1, Currency: "USD", CurrentBalance: decimal.NewFromInt(100)}, nil)
+
+	mockWalletRepository.
+		EXPECT().
+		GetWalletById(gomock.Any(), uint64(2)).
+		Times(1).
+		Return(&entity.Wallet{Id: 2, UserId: 1, Currency: "USD"}, nil)
+
+	mockTransferRepository.
+		EXPECT().
+		CreateTransfer(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ context.Context, transfer *entity.Transfer) (*entity.Transfer, error) {
+			return transfer, nil
+		})
+
+	createdTransfer, err := transferService.CreateTransfer(context.Background(), model.TransferCreateDTO{
+		UserId:         1,
+		TargetWalletId: 2,
+		Amount:         decimal.NewFromInt(5),
+	})
+
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, uint64(1), createdTransfer.SourceWalletId)
+}
+
+func TestCreateTransfer_NoDefaultSourceWallet_Error(t *testing.T) {
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+
+	mockPreferencesRepository := mock.NewMockPreferencesRepository(ctl)
+	mockManager := mock.WithPassThroughUnitOfWork(&repository.Manager{Preferences: mockPreferencesRepository})
+
+	transferService := transfer.NewTransferService(mockManager)
+
+	mockPreferencesRepository.
+		EXPECT().
+		GetPreferences(gomock.Any(), uint64(1)).
+		Times(1).
+		Return(nil, nil)
+
+	createdTransfer, err := transferService.CreateTransfer(context.Background(), model.TransferCreateDTO{
+		UserId:         1,
+		TargetWalletId: 2,
+		Amount:         decimal.NewFromInt(5),
+	})
+
+	assert.Nil(t, createdTransfer)
+	assert.ErrorIs(t, err, serviceerror.TransferSourceWalletRequired)
+}
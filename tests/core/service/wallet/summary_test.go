@@ -0,0 +1,146 @@
+package wallet
+
+import (
+	"context"
+	serviceerror "github.com/khivuksergey/portmonetka.wallet/error"
+	"github.com/khivuksergey/portmonetka.wallet/internal/adapter/storage/entity"
+	"github.com/khivuksergey/portmonetka.wallet/internal/adapter/storage/gorm/repo/mock"
+	"github.com/khivuksergey/portmonetka.wallet/internal/core/port/repository"
+	"github.com/khivuksergey/portmonetka.wallet/internal/core/service/wallet"
+	"github.com/khivuksergey/portmonetka.wallet/internal/model"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+)
+
+func TestGetWalletsSummary_Success(t *testing.T) {
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+
+	mockWalletRepository := mock.NewMockWalletRepository(ctl)
+	mockExchangeRateRepository := mock.NewMockExchangeRateRepository(ctl)
+	walletService := wallet.NewWalletService(&repository.Manager{
+		Wallet:       mockWalletRepository,
+		ExchangeRate: mockExchangeRateRepository,
+	})
+
+	userId := uint64(1)
+	date := time.Date(2024, 5, 17, 0, 0, 0, 0, time.UTC)
+
+	mockWalletRepository.
+		EXPECT().
+		GetAllWalletsByUserId(gomock.Any(), userId).
+		Times(1).
+		Return([]entity.Wallet{
+			{Id: 1, UserId: userId, Name: "Dollars", Currency: "USD", CurrentBalance: decimal.RequireFromString("108.66")},
+			{Id: 2, UserId: userId, Name: "Euros", Currency: "EUR", CurrentBalance: decimal.RequireFromString("50")},
+			{Id: 3, UserId: userId, Name: "Rubles", Currency: "RUB", CurrentBalance: decimal.RequireFromString("9880")},
+		}, nil)
+
+	mockExchangeRateRepository.
+		EXPECT().
+		GetEffectiveExchangeRates(gomock.Any(), date).
+		Times(1).
+		Return([]entity.ExchangeRate{
+			{Date: date, BaseCurrency: "EUR", QuoteCurrency: "USD", Rate: decimal.RequireFromString("1.0866")},
+			{Date: date, BaseCurrency: "EUR", QuoteCurrency: "RUB", Rate: decimal.RequireFromString("98.8")},
+		}, nil)
+
+	summary, err := walletService.GetWalletsSummary(context.Background(), userId, model.WalletSummaryQuery{BaseCurrency: "eur", Date: "2024-05-17"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "EUR", summary.BaseCurrency)
+	assert.Equal(t, "2024-05-17", summary.Date)
+	assert.Equal(t, "100", summary.Wallets[0].ConvertedBalance.String())
+	assert.Equal(t, "50", summary.Wallets[1].ConvertedBalance.String())
+	assert.Equal(t, "100", summary.Wallets[2].ConvertedBalance.String())
+	assert.Equal(t, "250", summary.Total.String())
+}
+
+func TestGetWalletsSummary_MissingRate_Error(t *testing.T) {
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+
+	mockWalletRepository := mock.NewMockWalletRepository(ctl)
+	mockExchangeRateRepository := mock.NewMockExchangeRateRepository(ctl)
+	walletService := wallet.NewWalletService(&repository.Manager{
+		Wallet:       mockWalletRepository,
+		ExchangeRate: mockExchangeRateRepository,
+	})
+
+	mockWalletRepository.
+		EXPECT().
+		GetAllWalletsByUserId(gomock.Any(), uint64(1)).
+		Times(1).
+		Return([]entity.Wallet{{Id: 1, UserId: 1, Currency: "JPY", CurrentBalance: decimal.NewFromInt(1000)}}, nil)
+
+	mockExchangeRateRepository.
+		EXPECT().
+		GetEffectiveExchangeRates(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(nil, nil)
+
+	summary, err := walletService.GetWalletsSummary(context.Background(), 1, model.WalletSummaryQuery{BaseCurrency: "USD"})
+
+	assert.Nil(t, summary)
+	assert.ErrorIs(t, err, serviceerror.ExchangeRateNotFound)
+}
+
+func TestGetWalletsSummary_PreferredBaseCurrency(t *testing.T) {
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+
+	mockWalletRepository := mock.NewMockWalletRepository(ctl)
+	mockExchangeRateRepository := mock.NewMockExchangeRateRepository(ctl)
+	mockPreferencesRepository := mock.NewMockPreferencesRepository(ctl)
+	walletService := wallet.NewWalletService(&repository.Manager{
+		Wallet:       mockWalletRepository,
+		ExchangeRate: mockExchangeRateRepository,
+		Preferences:  mockPreferencesRepository,
+	})
+
+	mockPreferencesRepository.
+		EXPECT().
+		GetPreferences(gomock.Any(), uint64(1)).
+		Times(1).
+		Return(&entity.Preferences{UserId: 1, BaseCurrency: "USD"}, nil)
+
+	mockWalletRepository.
+		EXPECT().
+		GetAllWalletsByUserId(gomock.Any(), uint64(1)).
+		Times(1).
+		Return([]entity.Wallet{{Id: 1, UserId: 1, Currency: "USD", CurrentBalance: decimal.NewFromInt(10)}}, nil)
+
+	mockExchangeRateRepository.
+		EXPECT().
+		GetEffectiveExchangeRates(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(nil, nil)
+
+	summary, err := walletService.GetWalletsSummary(context.Background(), 1, model.WalletSummaryQuery{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "USD", summary.BaseCurrency)
+	assert.Equal(t, "10", summary.Total.String())
+}
+
+func TestGetWalletsSummary_NoBaseCurrency_Error(t *testing.T) {
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+
+	mockPreferencesRepository := mock.NewMockPreferencesRepository(ctl)
+	walletService := wallet.NewWalletService(&repository.Manager{Preferences: mockPreferencesRepository})
+
+	mockPreferencesRepository.
+		EXPECT().
+		GetPreferences(gomock.Any(), uint64(1)).
+		Times(1).
+		Return(nil, nil)
+
+	summary, err := walletService.GetWalletsSummary(context.Background(), 1, model.WalletSummaryQuery{})
+
+	assert.Nil(t, summary)
+	assert.ErrorIs(t, err, serviceerror.BaseCurrencyRequired)
+}
@@ -0,0 +1,74 @@
+package wallet
+
+import (
+	"context"
+	"fmt"
+	"github.com/khivuksergey/portmonetka.wallet/config"
+	serviceerror "github.com/khivuksergey/portmonetka.wallet/error"
+	"github.com/khivuksergey/portmonetka.wallet/internal/adapter/storage/entity"
+	"github.com/khivuksergey/portmonetka.wallet/internal/adapter/storage/gorm"
+	"github.com/khivuksergey/portmonetka.wallet/internal/adapter/storage/gorm/migration"
+	"github.com/khivuksergey/portmonetka.wallet/internal/adapter/storage/memory"
+	"github.com/khivuksergey/portmonetka.wallet/internal/core/port/repository"
+	"github.com/khivuksergey/portmonetka.wallet/internal/core/service/wallet"
+	"github.com/khivuksergey/portmonetka.wallet/internal/model"
+	"github.com/stretchr/testify/assert"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestCreateWallet_ConcurrentSameName(t *testing.T) {
+	for name, newRepositoryManager := range map[string]func(t *testing.T) *repository.Manager{
+		"memory": func(t *testing.T) *repository.Manager {
+			return memory.NewRepositoryManager()
+		},
+		"sqlite": func(t *testing.T) *repository.Manager {
+			// a database file, so the creates run on separate connections
+			db := gorm.NewDbManager(config.DBConfig{
+				Driver:           gorm.DriverSqlite,
+				ConnectionString: filepath.Join(t.TempDir(), "wallet.db"),
+				MigrationMode:    migration.ModeAuto,
+			})
+			t.Cleanup(func() { _ = db.Close() })
+			return db.InitRepositoryManager()
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			repositoryManager := newRepositoryManager(t)
+			walletService := wallet.NewWalletService(repositoryManager)
+			names := []string{"Cash", "cash", "CASH", "Cash", "cAsH", "Cash"}
+
+			var wg sync.WaitGroup
+			start := make(chan struct{})
+			created := make([]*entity.Wallet, len(names))
+			errs := make([]error, len(names))
+			for i, name := range names {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					<-start
+					created[i], errs[i] = walletService.CreateWallet(context.Background(), model.WalletCreateDTO{UserId: 1, Name: name, Currency: "USD"})
+				}()
+			}
+			close(start)
+			wg.Wait()
+
+			succeeded := 0
+			for i, err := range errs {
+				if err == nil {
+					succeeded++
+					assert.NotNil(t, created[i])
+					continue
+				}
+				assert.ErrorIs(t, err, serviceerror.WalletAlreadyExists, fmt.Sprintf("create %q", names[i]))
+				assert.Nil(t, created[i])
+			}
+			assert.Equal(t, 1, succeeded)
+
+			wallets, err := repositoryManager.Wallet.GetAllWalletsByUserId(context.Background(), 1)
+			assert.NoError(t, err)
+			assert.Len(t, wallets, 1)
+		})
+	}
+}
@@ -0,0 +1,303 @@
+package wallet
+
+import (
+	"context"
+	serviceerror "github.com/khivuksergey/portmonetka.wallet/error"
+	"github.com/khivuksergey/portmonetka.wallet/internal/adapter/storage/entity"
+	"github.com/khivuksergey/portmonetka.wallet/internal/adapter/storage/memory"
+	"github.com/khivuksergey/portmonetka.wallet/internal/core/service/transaction"
+	"github.com/khivuksergey/portmonetka.wallet/internal/core/service/wallet"
+	"github.com/khivuksergey/portmonetka.wallet/internal/model"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestWalletTrashLifecycle_InMemory(t *testing.T) {
+	walletService := wallet.NewWalletService(memory.NewRepositoryManager())
+	userId := uint64(1)
+
+	created, err := walletService.CreateWallet(context.Background(), model.WalletCreateDTO{
+		UserId:        userId,
+		Name:          "Cash",
+		Currency:      "usd",
+		InitialAmount: decimal.NewFromInt(10),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "USD", created.Currency)
+
+	_, err = walletService.CreateWallet(context.Background(), model.WalletCreateDTO{UserId: userId, Name: "Cash", Currency: "USD"})
+	assert.ErrorIs(t, err, serviceerror.WalletAlreadyExists)
+
+	err = walletService.DeleteWallet(context.Background(), model.WalletDeleteDTO{Id: created.Id, UserId: userId, Versions: []uint64{created.Version}})
+	assert.NoError(t, err)
+
+	replacement, err := walletService.CreateWallet(context.Background(), model.WalletCreateDTO{UserId: userId, Name: "Cash", Currency: "USD"})
+	assert.NoError(t, err)
+
+	_, err = walletService.RestoreWallet(context.Background(), model.WalletTrashDTO{Id: created.Id, UserId: userId})
+	assert.ErrorIs(t, err, serviceerror.WalletAlreadyExists)
+
+	_, err = walletService.UpdateWallet(context.Background(), model.WalletUpdateDTO{Id: replacement.Id, UserId: userId, Name: ptr("Pocket")})
+	assert.NoError(t, err)
+
+	restored, err := walletService.RestoreWallet(context.Background(), model.WalletTrashDTO{Id: created.Id, UserId: userId})
+	assert.NoError(t, err)
+	assert.Equal(t, created.Version+1, restored.Version)
+
+	wallets, _, err := walletService.GetWalletsByUserId(context.Background(), userId, model.WalletListQuery{Sort: model.WalletSortName})
+	assert.NoError(t, err)
+	assert.Len(t, wallets, 2)
+	assert.Equal(t, "Cash", wallets[0].Name)
+	assert.Equal(t, "Pocket", wallets[1].Name)
+}
+
+func TestUpdateWallet_StaleVersion_InMemory(t *testing.T) {
+	walletService := wallet.NewWalletService(memory.NewRepositoryManager())
+	created, _ := walletService.CreateWallet(context.Background(), model.WalletCreateDTO{UserId: 1, Name: "Cash", Currency: "USD"})
+
+	_, err := walletService.UpdateWallet(context.Background(), model.WalletUpdateDTO{Id: created.Id, UserId: 1, Name: ptr("Card"), Versions: []uint64{created.Version}})
+	assert.NoError(t, err)
+
+	_, err = walletService.UpdateWallet(context.Background(), model.WalletUpdateDTO{Id: created.Id, UserId: 1, Name: ptr("Bank"), Versions: []uint64{created.Version}})
+	assert.ErrorIs(t, err, serviceerror.WalletVersionMismatch)
+}
+
+func TestUpdateWallet_BalanceFloor_InMemory(t *testing.T) {
+	repositoryManager := memory.NewRepositoryManager()
+	walletService := wallet.NewWalletService(repositoryManager)
+	transactionService := transaction.NewTransactionService(repositoryManager)
+	userId := uint64(1)
+
+	card, err := walletService.CreateWallet(context.Background(), model.WalletCreateDTO{
+		UserId:      userId,
+		Name:        "Card",
+		Currency:    "USD",
+		Type:        entity.WalletTypeDebitCard,
+		CreditLimit: ptr(decimal.NewFromInt(100)),
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+	_, err = transactionService.CreateTransaction(context.Background(), model.TransactionCreateDTO{
+		UserId:    userId,
+		WalletId:  card.Id,
+		Amount:    decimal.NewFromInt(80),
+		Direction: entity.TransactionDirectionOut,
+	})
+	assert.NoError(t, err)
+
+	_, err = walletService.UpdateWallet(context.Background(), model.WalletUpdateDTO{Id: card.Id, UserId: userId, CreditLimit: ptr(decimal.NewFromInt(50))})
+	assert.ErrorIs(t, err, serviceerror.InsufficientFunds)
+
+	_, err = walletService.UpdateWallet(context.Background(), model.WalletUpdateDTO{Id: card.Id, UserId: userId, InitialAmount: ptr(decimal.NewFromInt(-30))})
+	assert.ErrorIs(t, err, serviceerror.InsufficientFunds)
+
+	updated, err := walletService.UpdateWallet(context.Background(), model.WalletUpdateDTO{
+		Id:            card.Id,
+		UserId:        userId,
+		InitialAmount: ptr(decimal.NewFromInt(40)),
+		CreditLimit:   ptr(decimal.NewFromInt(50)),
+	})
+	assert.NoError(t, err)
+	assert.True(t, decimal.NewFromInt(-40).Equal(updated.CurrentBalance))
+
+	cash, err := walletService.CreateWallet(context.Background(), model.WalletCreateDTO{UserId: userId, Name: "Cash", Currency: "USD", AllowNegative: true})
+	if !assert.NoError(t, err) {
+		return
+	}
+	_, err = transactionService.CreateTransaction(context.Background(), model.TransactionCreateDTO{
+		UserId:    userId,
+		WalletId:  cash.Id,
+		Amount:    decimal.NewFromInt(10),
+		Direction: entity.TransactionDirectionOut,
+	})
+	assert.NoError(t, err)
+
+	_, err = walletService.UpdateWallet(context.Background(), model.WalletUpdateDTO{Id: cash.Id, UserId: userId, AllowNegative: ptr(false)})
+	assert.ErrorIs(t, err, serviceerror.InsufficientFunds)
+}
+
+func TestWalletTypes_InMemory(t *testing.T) {
+	walletService := wallet.NewWalletService(memory.NewRepositoryManager())
+	userId := uint64(1)
+
+	cash, err := walletService.CreateWallet(context.Background(), model.WalletCreateDTO{UserId: userId, Name: "Cash", Currency: "USD"})
+	assert.NoError(t, err)
+	assert.Equal(t, entity.WalletTypeCash, cash.Type)
+
+	_, err = walletService.CreateWallet(context.Background(), model.WalletCreateDTO{
+		UserId:   userId,
+		Name:     "Card",
+		Currency: "USD",
+		Type:     entity.WalletTypeCreditCard,
+	})
+	assert.ErrorIs(t, err, serviceerror.WalletCreditCardError)
+
+	_, err = walletService.CreateWallet(context.Background(), model.WalletCreateDTO{
+		UserId:       userId,
+		Name:         "Card",
+		Currency:     "JPY",
+		Type:         entity.WalletTypeCreditCard,
+		CreditLimit:  ptr(decimal.RequireFromString("1000.5")),
+		StatementDay: ptr(15),
+	})
+	assert.ErrorIs(t, err, serviceerror.WalletCreditLimitError)
+
+	card, err := walletService.CreateWallet(context.Background(), model.WalletCreateDTO{
+		UserId:       userId,
+		Name:         "Card",
+		Currency:     "USD",
+		Type:         entity.WalletTypeCreditCard,
+		CreditLimit:  ptr(decimal.NewFromInt(1000)),
+		StatementDay: ptr(15),
+	})
+	assert.NoError(t, err)
+
+	_, err = walletService.CreateWallet(context.Background(), model.WalletCreateDTO{
+		UserId:       userId,
+		Name:         "Deposit",
+		Currency:     "USD",
+		Type:         entity.WalletTypeSavings,
+		InterestRate: ptr(decimal.NewFromInt(5)),
+		StatementDay: ptr(1),
+	})
+	assert.ErrorIs(t, err, serviceerror.WalletTypeAttributeError)
+
+	_, err = walletService.UpdateWallet(context.Background(), model.WalletUpdateDTO{
+		Id:           card.Id,
+		UserId:       userId,
+		InterestRate: ptr(decimal.NewFromInt(20)),
+	})
+	assert.ErrorIs(t, err, serviceerror.WalletTypeAttributeError)
+
+	savings, err := walletService.UpdateWallet(context.Background(), model.WalletUpdateDTO{
+		Id:           card.Id,
+		UserId:       userId,
+		Type:         ptr(entity.WalletTypeSavings),
+		InterestRate: ptr(decimal.RequireFromString("4.5")),
+	})
+	assert.NoError(t, err)
+	assert.Nil(t, savings.CreditLimit)
+	assert.Nil(t, savings.StatementDay)
+	assert.Equal(t, "4.5", savings.InterestRate.String())
+
+	crypto, err := walletService.CreateWallet(context.Background(), model.WalletCreateDTO{
+		UserId:        userId,
+		Name:          "Cold storage",
+		Currency:      "btc",
+		Type:          entity.WalletTypeCrypto,
+		InitialAmount: decimal.RequireFromString("0.00000001"),
+	})
+	if assert.NoError(t, err) {
+		assert.Equal(t, "BTC", crypto.Currency)
+	}
+	_, err = walletService.CreateWallet(context.Background(), model.WalletCreateDTO{
+		UserId:        userId,
+		Name:          "Hot wallet",
+		Currency:      "BTC",
+		Type:          entity.WalletTypeCrypto,
+		InitialAmount: decimal.RequireFromString("0.000000001"),
+	})
+	assert.ErrorIs(t, err, serviceerror.WalletAmountPrecisionError)
+
+	wallets, _, err := walletService.GetWalletsByUserId(context.Background(), userId, model.WalletListQuery{Type: entity.WalletTypeSavings})
+	assert.NoError(t, err)
+	assert.Len(t, wallets, 1)
+	assert.Equal(t, card.Id, wallets[0].Id)
+}
+
+func TestUpdateWallet_NoChanges_NotRecorded_InMemory(t *testing.T) {
+	repositories := memory.NewRepositoryManager()
+	walletService := wallet.NewWalletService(repositories)
+	const userId = uint64(1)
+	ctx := context.Background()
+
+	created, err := walletService.CreateWallet(ctx, model.WalletCreateDTO{UserId: userId, Name: "Cash", Currency: "USD"})
+	if !assert.NoError(t, err) {
+		return
+	}
+	updated, err := walletService.UpdateWallet(ctx, model.WalletUpdateDTO{Id: created.Id, UserId: userId, Name: ptr("Cash")})
+	if assert.NoError(t, err) {
+		assert.Equal(t, created.Version, updated.Version)
+		assert.Equal(t, created.UpdatedAt, updated.UpdatedAt)
+	}
+	stored, err := walletService.GetWalletById(ctx, userId, created.Id)
+	if assert.NoError(t, err) {
+		assert.Equal(t, created.Version, stored.Version)
+		assert.Equal(t, created.UpdatedAt, stored.UpdatedAt)
+	}
+
+	history, err := walletService.GetWalletHistory(ctx, userId, created.Id)
+	assert.NoError(t, err)
+	if assert.Len(t, history, 1) {
+		assert.Equal(t, entity.WalletAuditActionCreate, history[0].Action)
+	}
+	outboxEvents, err := repositories.Outbox.ClaimOutboxEvents(ctx, 10, time.Now().Add(time.Minute))
+	assert.NoError(t, err)
+	if assert.Len(t, outboxEvents, 1) {
+		assert.Equal(t, entity.WalletCreatedEvent, outboxEvents[0].Type)
+	}
+}
+
+func TestWalletHistory_InMemory(t *testing.T) {
+	walletService := wallet.NewWalletService(memory.NewRepositoryManager())
+	const userId, adminId = uint64(1), uint64(7)
+	ctx := context.Background()
+
+	created, err := walletService.CreateWallet(ctx, model.WalletCreateDTO{
+		UserId:        userId,
+		Name:          "Cash",
+		Currency:      "USD",
+		InitialAmount: decimal.NewFromInt(10),
+	})
+	assert.NoError(t, err)
+
+	adminCtx := model.ContextWithRequestInfo(ctx, model.RequestInfo{ActorId: adminId, RequestUuid: "request-2"})
+	_, err = walletService.UpdateWallet(adminCtx, model.WalletUpdateDTO{
+		Id:            created.Id,
+		UserId:        userId,
+		Name:          ptr("Pocket"),
+		InitialAmount: ptr(decimal.NewFromInt(10)),
+	})
+	assert.NoError(t, err)
+
+	_, err = walletService.UpdateWallet(ctx, model.WalletUpdateDTO{Id: created.Id, UserId: userId, Versions: []uint64{1}, Name: ptr("Stale")})
+	assert.ErrorIs(t, err, serviceerror.WalletVersionMismatch)
+
+	err = walletService.DeleteWallet(ctx, model.WalletDeleteDTO{Id: created.Id, UserId: userId})
+	assert.NoError(t, err)
+	_, err = walletService.RestoreWallet(ctx, model.WalletTrashDTO{Id: created.Id, UserId: userId})
+	assert.NoError(t, err)
+
+	history, err := walletService.GetWalletHistory(ctx, userId, created.Id)
+	assert.NoError(t, err)
+	if !assert.Len(t, history, 4) {
+		return
+	}
+
+	assert.Equal(t, entity.WalletAuditActionCreate, history[0].Action)
+	assert.Equal(t, userId, history[0].ActorId)
+	assert.Equal(t, entity.FieldChange{After: "Cash"}, history[0].Changes["name"])
+	assert.Equal(t, entity.FieldChange{After: "10"}, history[0].Changes["initialAmount"])
+	assert.NotContains(t, history[0].Changes, "creditLimit")
+
+	assert.Equal(t, entity.WalletAuditActionUpdate, history[1].Action)
+	assert.Equal(t, adminId, history[1].ActorId)
+	assert.Equal(t, "request-2", history[1].RequestUuid)
+	assert.Equal(t, map[string]entity.FieldChange{"name": {Before: "Cash", After: "Pocket"}}, history[1].Changes)
+
+	assert.Equal(t, entity.WalletAuditActionDelete, history[2].Action)
+	assert.Equal(t, entity.FieldChange{Before: "Pocket"}, history[2].Changes["name"])
+
+	assert.Equal(t, entity.WalletAuditActionRestore, history[3].Action)
+	assert.NotContains(t, history[3].Changes, "name")
+	if assert.Contains(t, history[3].Changes, "deletedAt") {
+		assert.NotNil(t, history[3].Changes["deletedAt"].Before)
+		assert.Nil(t, history[3].Changes["deletedAt"].After)
+	}
+
+	_, err = walletService.GetWalletHistory(ctx, userId+1, created.Id)
+	assert.ErrorIs(t, err, serviceerror.WalletDoesntBelongToUser)
+}
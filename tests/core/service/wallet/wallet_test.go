@@ -1,6 +1,8 @@
 package wallet
 
 import (
+	"context"
+	"encoding/json"
 	serviceerror "github.com/khivuksergey/portmonetka.wallet/error"
 	"github.com/khivuksergey/portmonetka.wallet/internal/adapter/storage/entity"
 	"github.com/khivuksergey/portmonetka.wallet/internal/adapter/storage/gorm/repo/mock"
@@ -19,9 +21,9 @@ func TestGetWalletsByUserId_Success(t *testing.T) {
 	defer ctl.Finish()
 
 	mockWalletRepository := mock.NewMockWalletRepository(ctl)
-	mockManager := &repository.Manager{
+	mockManager := mock.WithPassThroughUnitOfWork(&repository.Manager{
 		Wallet: mockWalletRepository,
-	}
+	})
 
 	walletService := wallet.NewWalletService(mockManager)
 
@@ -47,26 +49,172 @@ func TestGetWalletsByUserId_Success(t *testing.T) {
 
 	mockWalletRepository.
 		EXPECT().
-		GetWalletsByUserId(userId).
+		GetWalletsByUserId(gomock.Any(), userId, model.WalletListQuery{
+			Sort:  model.DefaultWalletSort,
+			Limit: model.DefaultWalletListLimit + 1,
+		}, nil).
 		Times(1).
 		Return(expectedWallets, nil)
 
-	actualWallets, err := walletService.GetWalletsByUserId(userId)
+	actualWallets, nextCursor, err := walletService.GetWalletsByUserId(context.Background(), userId, model.WalletListQuery{})
 
 	assert.NoError(t, err)
 	assert.NotNil(t, actualWallets)
 	assert.Equal(t, expectedWallets, actualWallets)
+	assert.Empty(t, nextCursor)
 }
 
-func TestCreateWallet_Success(t *testing.T) {
+func TestGetWalletsByUserId_NextPage_Success(t *testing.T) {
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+
+	mockWalletRepository := mock.NewMockWalletRepository(ctl)
+	mockManager := mock.WithPassThroughUnitOfWork(&repository.Manager{
+		Wallet: mockWalletRepository,
+	})
+
+	walletService := wallet.NewWalletService(mockManager)
+
+	userId := uint64(1)
+	walletListQuery := model.WalletListQuery{
+		Sort:  model.WalletSortName,
+		Limit: 2,
+	}
+	repositoryWallets := []entity.Wallet{
+		{Id: 3, UserId: userId, Name: "Bank"},
+		{Id: 1, UserId: userId, Name: "Cash"},
+		{Id: 2, UserId: userId, Name: "Savings"},
+	}
+
+	mockWalletRepository.
+		EXPECT().
+		GetWalletsByUserId(gomock.Any(), userId, model.WalletListQuery{Sort: model.WalletSortName, Limit: 3}, nil).
+		Times(1).
+		Return(repositoryWallets, nil)
+
+	firstPage, nextCursor, err := walletService.GetWalletsByUserId(context.Background(), userId, walletListQuery)
+
+	assert.NoError(t, err)
+	assert.Equal(t, repositoryWallets[:2], firstPage)
+	assert.NotEmpty(t, nextCursor)
+
+	walletListQuery.Cursor = nextCursor
+
+	mockWalletRepository.
+		EXPECT().
+		GetWalletsByUserId(gomock.Any(), userId, model.WalletListQuery{Sort: model.WalletSortName, Limit: 3, Cursor: nextCursor},
+			&model.WalletCursor{Sort: model.WalletSortName, Value: "Cash", Id: 1}).
+		Times(1).
+		Return(repositoryWallets[2:], nil)
+
+	secondPage, nextCursor, err := walletService.GetWalletsByUserId(context.Background(), userId, walletListQuery)
+
+	assert.NoError(t, err)
+	assert.Equal(t, repositoryWallets[2:], secondPage)
+	assert.Empty(t, nextCursor)
+}
+
+func TestGetWalletsByUserId_CursorForAnotherSort_Error(t *testing.T) {
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+
+	mockWalletRepository := mock.NewMockWalletRepository(ctl)
+	mockManager := mock.WithPassThroughUnitOfWork(&repository.Manager{
+		Wallet: mockWalletRepository,
+	})
+
+	walletService := wallet.NewWalletService(mockManager)
+
+	walletListQuery := model.WalletListQuery{
+		Sort:   "-" + model.WalletSortBalance,
+		Cursor: model.WalletCursor{Sort: model.WalletSortName, Value: "Cash", Id: 1}.Encode(),
+	}
+
+	wallets, nextCursor, err := walletService.GetWalletsByUserId(context.Background(), 1, walletListQuery)
+
+	assert.Error(t, err)
+	assert.Nil(t, wallets)
+	assert.Empty(t, nextCursor)
+	assert.Equal(t, serviceerror.InvalidWalletCursor, err)
+}
+
+func TestGetWalletById_Success(t *testing.T) {
 	ctl := gomock.NewController(t)
 	defer ctl.Finish()
 
 	mockWalletRepository := mock.NewMockWalletRepository(ctl)
-	mockManager := &repository.Manager{
+	mockManager := mock.WithPassThroughUnitOfWork(&repository.Manager{
 		Wallet: mockWalletRepository,
+	})
+
+	walletService := wallet.NewWalletService(mockManager)
+
+	expectedWallet := &entity.Wallet{
+		Id:             1,
+		UserId:         1,
+		Name:           "Test Wallet",
+		Currency:       "USD",
+		InitialAmount:  decimal.NewFromFloat(100.00),
+		CurrentBalance: decimal.NewFromFloat(75.50),
 	}
 
+	mockWalletRepository.
+		EXPECT().
+		GetWalletById(gomock.Any(), expectedWallet.Id).
+		Times(1).
+		Return(expectedWallet, nil)
+
+	actualWallet, err := walletService.GetWalletById(context.Background(), expectedWallet.UserId, expectedWallet.Id)
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedWallet, actualWallet)
+}
+
+func TestGetWalletById_WalletDoesntBelongToUser_Error(t *testing.T) {
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+
+	mockWalletRepository := mock.NewMockWalletRepository(ctl)
+	mockWalletMemberRepository := mock.NewMockWalletMemberRepository(ctl)
+	mockManager := mock.WithPassThroughUnitOfWork(&repository.Manager{
+		Wallet:       mockWalletRepository,
+		WalletMember: mockWalletMemberRepository,
+	})
+
+	walletService := wallet.NewWalletService(mockManager)
+
+	mockWalletRepository.
+		EXPECT().
+		GetWalletById(gomock.Any(), uint64(1)).
+		Times(1).
+		Return(&entity.Wallet{Id: 1, UserId: 2}, nil)
+
+	mockWalletMemberRepository.
+		EXPECT().
+		GetWalletMember(gomock.Any(), uint64(1), uint64(1)).
+		Times(1).
+		Return(nil, nil)
+
+	actualWallet, err := walletService.GetWalletById(context.Background(), 1, 1)
+
+	assert.Error(t, err)
+	assert.Nil(t, actualWallet)
+	assert.Equal(t, serviceerror.WalletDoesntBelongToUser, err)
+}
+
+func TestCreateWallet_Success(t *testing.T) {
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+
+	mockWalletRepository := mock.NewMockWalletRepository(ctl)
+	mockWalletAuditRepository := mock.NewMockWalletAuditRepository(ctl)
+	mockOutboxRepository := mock.NewMockOutboxRepository(ctl)
+	mockManager := mock.WithPassThroughUnitOfWork(&repository.Manager{
+		Wallet:      mockWalletRepository,
+		WalletAudit: mockWalletAuditRepository,
+		Outbox:      mockOutboxRepository,
+	})
+
 	walletService := wallet.NewWalletService(mockManager)
 
 	walletCreateDTO := &model.WalletCreateDTO{
@@ -83,21 +231,31 @@ func TestCreateWallet_Success(t *testing.T) {
 		Description:   walletCreateDTO.Description,
 		Currency:      strings.ToUpper(walletCreateDTO.Currency),
 		InitialAmount: walletCreateDTO.InitialAmount,
+		Type:          entity.WalletTypeCash,
 	}
 
 	mockWalletRepository.
 		EXPECT().
-		ExistsWithName(walletCreateDTO.UserId, walletCreateDTO.Name).
+		CreateWallet(gomock.Any(), expectedWallet).
 		Times(1).
-		Return(false)
+		Return(expectedWallet, nil)
 
-	mockWalletRepository.
+	mockWalletAuditRepository.
 		EXPECT().
-		CreateWallet(expectedWallet).
+		CreateWalletAudit(gomock.Any(), gomock.Any()).
 		Times(1).
-		Return(expectedWallet, nil)
+		Return(nil)
+
+	mockOutboxRepository.
+		EXPECT().
+		CreateOutboxEvent(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ context.Context, outboxEvent *entity.OutboxEvent) error {
+			assert.Equal(t, entity.WalletCreatedEvent, outboxEvent.Type)
+			return nil
+		})
 
-	createdWallet, err := walletService.CreateWallet(*walletCreateDTO)
+	createdWallet, err := walletService.CreateWallet(context.Background(), *walletCreateDTO)
 
 	assert.NoError(t, err)
 	assert.NotNil(t, createdWallet)
@@ -109,9 +267,9 @@ func TestCreateWallet_DuplicateName_Error(t *testing.T) {
 	defer ctl.Finish()
 
 	mockWalletRepository := mock.NewMockWalletRepository(ctl)
-	mockManager := &repository.Manager{
+	mockManager := mock.WithPassThroughUnitOfWork(&repository.Manager{
 		Wallet: mockWalletRepository,
-	}
+	})
 
 	walletService := wallet.NewWalletService(mockManager)
 
@@ -125,26 +283,112 @@ func TestCreateWallet_DuplicateName_Error(t *testing.T) {
 
 	mockWalletRepository.
 		EXPECT().
-		ExistsWithName(walletCreateDTO.UserId, walletCreateDTO.Name).
+		CreateWallet(gomock.Any(), gomock.Any()).
 		Times(1).
-		Return(true)
+		Return(nil, serviceerror.WalletAlreadyExists)
 
-	createdWallet, err := walletService.CreateWallet(*walletCreateDTO)
+	createdWallet, err := walletService.CreateWallet(context.Background(), *walletCreateDTO)
 
 	assert.Error(t, err)
 	assert.Nil(t, createdWallet)
 	assert.Equal(t, serviceerror.WalletAlreadyExists, err)
 }
 
-func TestUpdateWallet_Success(t *testing.T) {
+func TestCreateWallet_UnknownCurrency_Error(t *testing.T) {
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+
+	mockWalletRepository := mock.NewMockWalletRepository(ctl)
+	mockManager := mock.WithPassThroughUnitOfWork(&repository.Manager{
+		Wallet: mockWalletRepository,
+	})
+
+	walletService := wallet.NewWalletService(mockManager)
+
+	walletCreateDTO := model.WalletCreateDTO{
+		UserId:   1,
+		Name:     "Wallet",
+		Currency: "ABC",
+	}
+
+	createdWallet, err := walletService.CreateWallet(context.Background(), walletCreateDTO)
+
+	assert.Nil(t, createdWallet)
+	assert.Equal(t, serviceerror.WalletCurrencyError, err)
+}
+
+func TestCreateWallet_AmountPrecision_Error(t *testing.T) {
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+
+	mockWalletRepository := mock.NewMockWalletRepository(ctl)
+	mockManager := mock.WithPassThroughUnitOfWork(&repository.Manager{
+		Wallet: mockWalletRepository,
+	})
+
+	walletService := wallet.NewWalletService(mockManager)
+
+	walletCreateDTO := model.WalletCreateDTO{
+		UserId:        1,
+		Name:          "Yen wallet",
+		Currency:      "jpy",
+		InitialAmount: decimal.RequireFromString("100.5"),
+	}
+
+	createdWallet, err := walletService.CreateWallet(context.Background(), walletCreateDTO)
+
+	assert.Nil(t, createdWallet)
+	assert.Equal(t, serviceerror.WalletAmountPrecisionError, err)
+}
+
+func TestUpdateWallet_CurrencyWithFewerMinorUnits_Error(t *testing.T) {
 	ctl := gomock.NewController(t)
 	defer ctl.Finish()
 
 	mockWalletRepository := mock.NewMockWalletRepository(ctl)
-	mockManager := &repository.Manager{
+	mockManager := mock.WithPassThroughUnitOfWork(&repository.Manager{
 		Wallet: mockWalletRepository,
+	})
+
+	walletService := wallet.NewWalletService(mockManager)
+
+	walletUpdateDTO := model.WalletUpdateDTO{
+		Id:       1,
+		UserId:   1,
+		Currency: ptr[string]("jpy"),
 	}
 
+	mockWalletRepository.
+		EXPECT().
+		GetWalletForUpdate(gomock.Any(), walletUpdateDTO.Id).
+		Times(1).
+		Return(&entity.Wallet{
+			Id:            1,
+			UserId:        1,
+			Name:          "Wallet",
+			Currency:      "USD",
+			InitialAmount: decimal.RequireFromString("10.25"),
+		}, nil)
+
+	updatedWallet, err := walletService.UpdateWallet(context.Background(), walletUpdateDTO)
+
+	assert.Nil(t, updatedWallet)
+	assert.Equal(t, serviceerror.WalletAmountPrecisionError, err)
+}
+
+func TestUpdateWallet_Success(t *testing.T) {
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+
+	mockWalletRepository := mock.NewMockWalletRepository(ctl)
+	mockWalletAuditRepository := mock.NewMockWalletAuditRepository(ctl)
+	mockOutboxRepository := mock.NewMockOutboxRepository(ctl)
+	mockManager := mock.WithPassThroughUnitOfWork(&repository.Manager{
+		Wallet:      mockWalletRepository,
+		WalletAudit: mockWalletAuditRepository,
+		Outbox:      mockOutboxRepository,
+	})
+
 	walletService := wallet.NewWalletService(mockManager)
 
 	walletUpdateDTO := &model.WalletUpdateDTO{
@@ -163,6 +407,7 @@ func TestUpdateWallet_Success(t *testing.T) {
 		Description:   "Old description",
 		Currency:      "USD",
 		InitialAmount: decimal.NewFromFloat(100.00),
+		Type:          entity.WalletTypeCash,
 	}
 
 	updatedWallet := &entity.Wallet{
@@ -172,25 +417,20 @@ func TestUpdateWallet_Success(t *testing.T) {
 		Description:   "Updated description",
 		Currency:      "EUR",
 		InitialAmount: decimal.NewFromFloat(150.00),
+		Type:          entity.WalletTypeCash,
 	}
 
 	mockWalletRepository.
 		EXPECT().
-		GetWalletById(walletUpdateDTO.Id).
+		GetWalletForUpdate(gomock.Any(), walletUpdateDTO.Id).
 		Times(1).
 		Return(existingWallet, nil)
 
 	mockWalletRepository.
 		EXPECT().
-		ExistsWithName(existingWallet.UserId, *walletUpdateDTO.Name).
-		Times(1).
-		Return(false)
-
-	mockWalletRepository.
-		EXPECT().
-		UpdateWallet(existingWallet).
+		UpdateWallet(gomock.Any(), existingWallet).
 		Times(1).
-		DoAndReturn(func(wallet *entity.Wallet) (*entity.Wallet, error) {
+		DoAndReturn(func(_ context.Context, wallet *entity.Wallet) (*entity.Wallet, error) {
 			wallet.Name = *walletUpdateDTO.Name
 			wallet.Description = *walletUpdateDTO.Description
 			wallet.Currency = strings.ToUpper(*walletUpdateDTO.Currency)
@@ -198,7 +438,37 @@ func TestUpdateWallet_Success(t *testing.T) {
 			return wallet, nil
 		})
 
-	updatedWalletFromService, err := walletService.UpdateWallet(*walletUpdateDTO)
+	mockWalletAuditRepository.
+		EXPECT().
+		CreateWalletAudit(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ context.Context, walletAudit *entity.WalletAudit) error {
+			assert.Equal(t, entity.WalletAuditActionUpdate, walletAudit.Action)
+			assert.Equal(t, walletUpdateDTO.UserId, walletAudit.ActorId)
+			assert.Equal(t, map[string]entity.FieldChange{
+				"name":          {Before: "Old wallet name", After: "Updated wallet name"},
+				"description":   {Before: "Old description", After: "Updated description"},
+				"currency":      {Before: "USD", After: "EUR"},
+				"initialAmount": {Before: "100", After: "150"},
+			}, walletAudit.Changes)
+			return nil
+		})
+
+	mockOutboxRepository.
+		EXPECT().
+		CreateOutboxEvent(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ context.Context, outboxEvent *entity.OutboxEvent) error {
+			assert.Equal(t, entity.WalletUpdatedEvent, outboxEvent.Type)
+			assert.Equal(t, walletUpdateDTO.Id, outboxEvent.AggregateId)
+			var walletEvent model.WalletEvent
+			assert.NoError(t, json.Unmarshal(outboxEvent.Payload, &walletEvent))
+			assert.Equal(t, "Updated wallet name", walletEvent.Wallet.Name)
+			assert.Equal(t, entity.FieldChange{Before: "USD", After: "EUR"}, walletEvent.Changes["currency"])
+			return nil
+		})
+
+	updatedWalletFromService, err := walletService.UpdateWallet(context.Background(), *walletUpdateDTO)
 
 	assert.NoError(t, err)
 	assert.NotNil(t, updatedWalletFromService)
@@ -210,9 +480,9 @@ func TestUpdateWallet_WalletNotFound_Error(t *testing.T) {
 	defer ctl.Finish()
 
 	mockWalletRepository := mock.NewMockWalletRepository(ctl)
-	mockManager := &repository.Manager{
+	mockManager := mock.WithPassThroughUnitOfWork(&repository.Manager{
 		Wallet: mockWalletRepository,
-	}
+	})
 
 	walletService := wallet.NewWalletService(mockManager)
 
@@ -226,26 +496,61 @@ func TestUpdateWallet_WalletNotFound_Error(t *testing.T) {
 
 	mockWalletRepository.
 		EXPECT().
-		GetWalletById(walletUpdateDTO.Id).
+		GetWalletForUpdate(gomock.Any(), walletUpdateDTO.Id).
 		Times(1).
 		Return(nil, serviceerror.WalletDoesntExist)
 
-	updatedWallet, err := walletService.UpdateWallet(*walletUpdateDTO)
+	updatedWallet, err := walletService.UpdateWallet(context.Background(), *walletUpdateDTO)
 
 	assert.Error(t, err)
 	assert.Nil(t, updatedWallet)
 	assert.Equal(t, serviceerror.WalletDoesntExist, err)
 }
 
-func TestDeleteWallet_Success(t *testing.T) {
+func TestUpdateWallet_StaleVersion_Error(t *testing.T) {
 	ctl := gomock.NewController(t)
 	defer ctl.Finish()
 
 	mockWalletRepository := mock.NewMockWalletRepository(ctl)
-	mockManager := &repository.Manager{
+	mockManager := mock.WithPassThroughUnitOfWork(&repository.Manager{
 		Wallet: mockWalletRepository,
+	})
+
+	walletService := wallet.NewWalletService(mockManager)
+
+	walletUpdateDTO := &model.WalletUpdateDTO{
+		Id:       1,
+		UserId:   1,
+		Name:     ptr[string]("Updated wallet name"),
+		Versions: []uint64{1, 2},
 	}
 
+	mockWalletRepository.
+		EXPECT().
+		GetWalletForUpdate(gomock.Any(), walletUpdateDTO.Id).
+		Times(1).
+		Return(&entity.Wallet{Id: 1, UserId: 1, Name: "Old wallet name", Version: 3}, nil)
+
+	updatedWallet, err := walletService.UpdateWallet(context.Background(), *walletUpdateDTO)
+
+	assert.Error(t, err)
+	assert.Nil(t, updatedWallet)
+	assert.Equal(t, serviceerror.WalletVersionMismatch, err)
+}
+
+func TestDeleteWallet_Success(t *testing.T) {
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+
+	mockWalletRepository := mock.NewMockWalletRepository(ctl)
+	mockWalletAuditRepository := mock.NewMockWalletAuditRepository(ctl)
+	mockOutboxRepository := mock.NewMockOutboxRepository(ctl)
+	mockManager := mock.WithPassThroughUnitOfWork(&repository.Manager{
+		Wallet:      mockWalletRepository,
+		WalletAudit: mockWalletAuditRepository,
+		Outbox:      mockOutboxRepository,
+	})
+
 	walletService := wallet.NewWalletService(mockManager)
 
 	walletDeleteDTO := &model.WalletDeleteDTO{
@@ -254,30 +559,127 @@ func TestDeleteWallet_Success(t *testing.T) {
 
 	mockWalletRepository.
 		EXPECT().
-		WalletBelongsToUser(walletDeleteDTO.Id, walletDeleteDTO.UserId).
+		GetWalletById(gomock.Any(), walletDeleteDTO.Id).
 		Times(1).
-		Return(true)
+		Return(&entity.Wallet{Id: walletDeleteDTO.Id, UserId: walletDeleteDTO.UserId}, nil)
 
 	mockWalletRepository.
 		EXPECT().
-		DeleteWallet(walletDeleteDTO.Id).
+		DeleteWallet(gomock.Any(), walletDeleteDTO.Id).
 		Times(1).
 		Return(nil)
 
-	err := walletService.DeleteWallet(*walletDeleteDTO)
+	mockWalletAuditRepository.
+		EXPECT().
+		CreateWalletAudit(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(nil)
+
+	mockOutboxRepository.
+		EXPECT().
+		CreateOutboxEvent(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ context.Context, outboxEvent *entity.OutboxEvent) error {
+			assert.Equal(t, entity.WalletDeletedEvent, outboxEvent.Type)
+			return nil
+		})
+
+	err := walletService.DeleteWallet(context.Background(), *walletDeleteDTO)
 
 	assert.NoError(t, err)
 }
 
-func TestDeleteWallet_WalletDoesntBelongToUser_Error(t *testing.T) {
+func TestDeleteWallet_WithVersion_Success(t *testing.T) {
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+
+	mockWalletRepository := mock.NewMockWalletRepository(ctl)
+	mockWalletAuditRepository := mock.NewMockWalletAuditRepository(ctl)
+	mockOutboxRepository := mock.NewMockOutboxRepository(ctl)
+	mockManager := mock.WithPassThroughUnitOfWork(&repository.Manager{
+		Wallet:      mockWalletRepository,
+		WalletAudit: mockWalletAuditRepository,
+		Outbox:      mockOutboxRepository,
+	})
+
+	walletService := wallet.NewWalletService(mockManager)
+
+	walletDeleteDTO := &model.WalletDeleteDTO{
+		Id:       1,
+		UserId:   1,
+		Versions: []uint64{3, 4},
+	}
+
+	mockWalletRepository.
+		EXPECT().
+		GetWalletById(gomock.Any(), walletDeleteDTO.Id).
+		Times(1).
+		Return(&entity.Wallet{Id: walletDeleteDTO.Id, UserId: walletDeleteDTO.UserId, Version: 4}, nil)
+
+	mockWalletRepository.
+		EXPECT().
+		DeleteWalletWithVersion(gomock.Any(), walletDeleteDTO.Id, uint64(4)).
+		Times(1).
+		Return(nil)
+
+	mockWalletAuditRepository.
+		EXPECT().
+		CreateWalletAudit(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(nil)
+
+	mockOutboxRepository.
+		EXPECT().
+		CreateOutboxEvent(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ context.Context, outboxEvent *entity.OutboxEvent) error {
+			assert.Equal(t, entity.WalletDeletedEvent, outboxEvent.Type)
+			return nil
+		})
+
+	err := walletService.DeleteWallet(context.Background(), *walletDeleteDTO)
+
+	assert.NoError(t, err)
+}
+
+func TestDeleteWallet_StaleVersion_Error(t *testing.T) {
 	ctl := gomock.NewController(t)
 	defer ctl.Finish()
 
 	mockWalletRepository := mock.NewMockWalletRepository(ctl)
-	mockManager := &repository.Manager{
+	mockManager := mock.WithPassThroughUnitOfWork(&repository.Manager{
 		Wallet: mockWalletRepository,
+	})
+
+	walletService := wallet.NewWalletService(mockManager)
+
+	walletDeleteDTO := &model.WalletDeleteDTO{
+		Id:       1,
+		UserId:   1,
+		Versions: []uint64{2, 3},
 	}
 
+	mockWalletRepository.
+		EXPECT().
+		GetWalletById(gomock.Any(), walletDeleteDTO.Id).
+		Times(1).
+		Return(&entity.Wallet{Id: walletDeleteDTO.Id, UserId: walletDeleteDTO.UserId, Version: 4}, nil)
+
+	err := walletService.DeleteWallet(context.Background(), *walletDeleteDTO)
+
+	assert.Equal(t, serviceerror.WalletVersionMismatch, err)
+}
+func TestDeleteWallet_WalletDoesntBelongToUser_Error(t *testing.T) {
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+
+	mockWalletRepository := mock.NewMockWalletRepository(ctl)
+	mockWalletMemberRepository := mock.NewMockWalletMemberRepository(ctl)
+	mockManager := mock.WithPassThroughUnitOfWork(&repository.Manager{
+		Wallet:       mockWalletRepository,
+		WalletMember: mockWalletMemberRepository,
+	})
+
 	walletService := wallet.NewWalletService(mockManager)
 
 	walletDeleteDTO := &model.WalletDeleteDTO{
@@ -287,11 +689,138 @@ func TestDeleteWallet_WalletDoesntBelongToUser_Error(t *testing.T) {
 
 	mockWalletRepository.
 		EXPECT().
-		WalletBelongsToUser(walletDeleteDTO.Id, walletDeleteDTO.UserId).
+		GetWalletById(gomock.Any(), walletDeleteDTO.Id).
+		Times(1).
+		Return(&entity.Wallet{Id: 1, UserId: 2}, nil)
+
+	mockWalletMemberRepository.
+		EXPECT().
+		GetWalletMember(gomock.Any(), walletDeleteDTO.Id, walletDeleteDTO.UserId).
+		Times(1).
+		Return(nil, nil)
+
+	err := walletService.DeleteWallet(context.Background(), *walletDeleteDTO)
+
+	assert.Error(t, err)
+	assert.Equal(t, serviceerror.WalletDoesntBelongToUser, err)
+}
+
+func TestRestoreWallet_Success(t *testing.T) {
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+
+	mockWalletRepository := mock.NewMockWalletRepository(ctl)
+	mockWalletAuditRepository := mock.NewMockWalletAuditRepository(ctl)
+	mockOutboxRepository := mock.NewMockOutboxRepository(ctl)
+	mockManager := mock.WithPassThroughUnitOfWork(&repository.Manager{
+		Wallet:      mockWalletRepository,
+		WalletAudit: mockWalletAuditRepository,
+		Outbox:      mockOutboxRepository,
+	})
+
+	walletService := wallet.NewWalletService(mockManager)
+
+	walletTrashDTO := &model.WalletTrashDTO{
+		Id:     1,
+		UserId: 1,
+	}
+
+	deletedWallet := &entity.Wallet{Id: 1, UserId: 1, Name: "Old wallet", Version: 2}
+	restoredWallet := &entity.Wallet{Id: 1, UserId: 1, Name: "Old wallet", Version: 3}
+
+	mockWalletRepository.
+		EXPECT().
+		GetDeletedWalletById(gomock.Any(), walletTrashDTO.Id).
+		Times(1).
+		Return(deletedWallet, nil)
+
+	mockWalletRepository.
+		EXPECT().
+		RestoreWallet(gomock.Any(), walletTrashDTO.Id).
+		Times(1).
+		Return(restoredWallet, nil)
+
+	mockWalletAuditRepository.
+		EXPECT().
+		CreateWalletAudit(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(nil)
+
+	mockOutboxRepository.
+		EXPECT().
+		CreateOutboxEvent(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ context.Context, outboxEvent *entity.OutboxEvent) error {
+			assert.Equal(t, entity.WalletRestoredEvent, outboxEvent.Type)
+			return nil
+		})
+
+	actualWallet, err := walletService.RestoreWallet(context.Background(), *walletTrashDTO)
+
+	assert.NoError(t, err)
+	assert.Equal(t, restoredWallet, actualWallet)
+}
+
+func TestRestoreWallet_NameConflict_Error(t *testing.T) {
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+
+	mockWalletRepository := mock.NewMockWalletRepository(ctl)
+	mockManager := mock.WithPassThroughUnitOfWork(&repository.Manager{
+		Wallet: mockWalletRepository,
+	})
+
+	walletService := wallet.NewWalletService(mockManager)
+
+	walletTrashDTO := &model.WalletTrashDTO{
+		Id:     1,
+		UserId: 1,
+	}
+
+	deletedWallet := &entity.Wallet{Id: 1, UserId: 1, Name: "Cash"}
+
+	mockWalletRepository.
+		EXPECT().
+		GetDeletedWalletById(gomock.Any(), walletTrashDTO.Id).
+		Times(1).
+		Return(deletedWallet, nil)
+
+	mockWalletRepository.
+		EXPECT().
+		RestoreWallet(gomock.Any(), walletTrashDTO.Id).
+		Times(1).
+		Return(nil, serviceerror.WalletAlreadyExists)
+
+	actualWallet, err := walletService.RestoreWallet(context.Background(), *walletTrashDTO)
+
+	assert.Error(t, err)
+	assert.Nil(t, actualWallet)
+	assert.Equal(t, serviceerror.WalletAlreadyExists, err)
+}
+
+func TestPurgeWallet_WalletDoesntBelongToUser_Error(t *testing.T) {
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+
+	mockWalletRepository := mock.NewMockWalletRepository(ctl)
+	mockManager := mock.WithPassThroughUnitOfWork(&repository.Manager{
+		Wallet: mockWalletRepository,
+	})
+
+	walletService := wallet.NewWalletService(mockManager)
+
+	walletTrashDTO := &model.WalletTrashDTO{
+		Id:     1,
+		UserId: 1,
+	}
+
+	mockWalletRepository.
+		EXPECT().
+		GetDeletedWalletById(gomock.Any(), walletTrashDTO.Id).
 		Times(1).
-		Return(false)
+		Return(&entity.Wallet{Id: 1, UserId: 2}, nil)
 
-	err := walletService.DeleteWallet(*walletDeleteDTO)
+	err := walletService.PurgeWallet(context.Background(), *walletTrashDTO)
 
 	assert.Error(t, err)
 	assert.Equal(t, serviceerror.WalletDoesntBelongToUser, err)
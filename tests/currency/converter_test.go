@@ -0,0 +1,33 @@
+package currency
+
+import (
+	"github.com/khivuksergey/portmonetka.wallet/internal/currency"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestConverter_Rate(t *testing.T) {
+	converter := currency.NewConverter()
+	converter.AddRate("EUR", "USD", decimal.RequireFromString("1.25"))
+	converter.AddRate("EUR", "RUB", decimal.RequireFromString("100"))
+
+	direct, ok := converter.Rate("EUR", "USD")
+	assert.True(t, ok)
+	assert.Equal(t, "1.25", direct.String())
+
+	inverse, ok := converter.Rate("usd", "eur")
+	assert.True(t, ok)
+	assert.Equal(t, "0.8", inverse.String())
+
+	cross, ok := converter.Rate("USD", "RUB")
+	assert.True(t, ok)
+	assert.Equal(t, "80", cross.String())
+
+	same, ok := converter.Rate("JPY", "JPY")
+	assert.True(t, ok)
+	assert.Equal(t, "1", same.String())
+
+	_, ok = converter.Rate("USD", "JPY")
+	assert.False(t, ok)
+}
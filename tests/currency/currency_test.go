@@ -0,0 +1,47 @@
+package currency
+
+import (
+	"github.com/khivuksergey/portmonetka.wallet/internal/currency"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestGet_CaseInsensitive(t *testing.T) {
+	usd, ok := currency.Get("usd")
+
+	assert.True(t, ok)
+	assert.Equal(t, currency.Currency{Code: "USD", NumericCode: "840", Name: "US Dollar", MinorUnits: 2}, usd)
+	assert.False(t, currency.IsValid("ABC"))
+	assert.False(t, currency.IsValid("usd1"))
+}
+
+func TestFitsMinorUnits(t *testing.T) {
+	usd, _ := currency.Get("USD")
+	jpy, _ := currency.Get("JPY")
+	bhd, _ := currency.Get("BHD")
+
+	assert.True(t, usd.FitsMinorUnits(decimal.RequireFromString("10.25")))
+	assert.True(t, usd.FitsMinorUnits(decimal.RequireFromString("10.250")))
+	assert.False(t, usd.FitsMinorUnits(decimal.RequireFromString("10.255")))
+	assert.True(t, jpy.FitsMinorUnits(decimal.RequireFromString("1000")))
+	assert.False(t, jpy.FitsMinorUnits(decimal.RequireFromString("1000.5")))
+	assert.True(t, bhd.FitsMinorUnits(decimal.RequireFromString("1.125")))
+}
+
+func TestGet_Cryptocurrency(t *testing.T) {
+	btc, ok := currency.Get("btc")
+
+	assert.True(t, ok)
+	assert.Equal(t, currency.Currency{Code: "BTC", Name: "Bitcoin", MinorUnits: 8}, btc)
+	assert.True(t, btc.FitsMinorUnits(decimal.RequireFromString("0.00000001")))
+	assert.False(t, btc.FitsMinorUnits(decimal.RequireFromString("0.000000001")))
+}
+
+func TestAll_OrderedAndUnique(t *testing.T) {
+	all := currency.All()
+
+	for i := 1; i < len(all); i++ {
+		assert.Less(t, all[i-1].Code, all[i].Code)
+	}
+}
@@ -0,0 +1,126 @@
+package http
+
+import (
+	"fmt"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+func TestCrossUserAccessIsRejected(t *testing.T) {
+	const ownerId, otherId = 14, 15
+
+	rec, response := doRequest(router, ownerId, http.MethodPost, "/wallets", map[string]any{
+		"name":          "Private",
+		"currency":      "USD",
+		"initialAmount": "50",
+	})
+	assert.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	walletId := uint64(data(response)["id"].(float64))
+	rec, response = doRequest(router, ownerId, http.MethodPost, fmt.Sprintf("/wallets/%d/transactions", walletId), map[string]any{
+		"amount":    "5",
+		"direction": "out",
+	})
+	assert.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	transactionId := uint64(data(response)["id"].(float64))
+
+	wallet := fmt.Sprintf("/users/%d/wallets/%d", ownerId, walletId)
+	transaction := fmt.Sprintf("%s/transactions/%d", wallet, transactionId)
+	routes := []struct {
+		method string
+		path   string
+		body   any
+	}{
+		{http.MethodGet, fmt.Sprintf("/users/%d/wallets", ownerId), nil},
+		{http.MethodPost, fmt.Sprintf("/users/%d/wallets", ownerId), map[string]any{"name": "Intruder", "currency": "USD"}},
+		{http.MethodGet, fmt.Sprintf("/users/%d/wallets/summary?base=USD", ownerId), nil},
+		{http.MethodGet, wallet, nil},
+		{http.MethodGet, wallet + "/history", nil},
+		{http.MethodPatch, wallet, map[string]any{"name": "Stolen"}},
+		{http.MethodDelete, wallet, nil},
+		{http.MethodGet, fmt.Sprintf("/users/%d/wallets/trash", ownerId), nil},
+		{http.MethodPost, fmt.Sprintf("/users/%d/wallets/trash/%d/restore", ownerId, walletId), nil},
+		{http.MethodDelete, fmt.Sprintf("/users/%d/wallets/trash/%d", ownerId, walletId), nil},
+		{http.MethodGet, wallet + "/transactions", nil},
+		{http.MethodPost, wallet + "/transactions", map[string]any{"amount": "45", "direction": "out"}},
+		{http.MethodGet, transaction, nil},
+		{http.MethodPatch, transaction, map[string]any{"amount": "50"}},
+		{http.MethodDelete, transaction, nil},
+		{http.MethodGet, wallet + "/members", nil},
+		{http.MethodPost, wallet + "/members", map[string]any{"inviteeId": otherId, "role": "editor"}},
+		{http.MethodDelete, wallet + "/members/1", nil},
+		{http.MethodGet, fmt.Sprintf("/users/%d/invitations", ownerId), nil},
+		{http.MethodPost, fmt.Sprintf("/users/%d/invitations/1/accept", ownerId), nil},
+		{http.MethodDelete, fmt.Sprintf("/users/%d/invitations/1", ownerId), nil},
+		{http.MethodPost, fmt.Sprintf("/users/%d/transfers", ownerId), map[string]any{"sourceWalletId": walletId, "targetWalletId": walletId + 1, "amount": "1"}},
+		{http.MethodGet, fmt.Sprintf("/users/%d/preferences", ownerId), nil},
+		{http.MethodPut, fmt.Sprintf("/users/%d/preferences", ownerId), map[string]any{"baseCurrency": "EUR"}},
+	}
+	forgedToken, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"sub": ownerId}).SignedString([]byte("another-secret"))
+	attempts := []struct {
+		name   string
+		token  string
+		status int
+	}{
+		{"other user", token(otherId), http.StatusForbidden},
+		{"other user with string subject", tokenWithClaims(jwt.MapClaims{"sub": "15"}), http.StatusForbidden},
+		{"other user with non-acting role", tokenWithClaims(jwt.MapClaims{"sub": otherId, "role": "user"}), http.StatusForbidden},
+		{"no subject", tokenWithClaims(jwt.MapClaims{"name": "anonymous"}), http.StatusUnauthorized},
+		{"invalid subject", tokenWithClaims(jwt.MapClaims{"sub": "owner"}), http.StatusUnauthorized},
+		{"forged token", forgedToken, http.StatusUnauthorized},
+		{"no token", "", http.StatusUnauthorized},
+	}
+
+	for _, attempt := range attempts {
+		for _, route := range routes {
+			t.Run(fmt.Sprintf("%s %s %s", attempt.name, route.method, route.path), func(t *testing.T) {
+				rec, _ := doRequestWithToken(router, attempt.token, route.method, route.path, route.body)
+				assert.Equal(t, attempt.status, rec.Code, rec.Body.String())
+			})
+		}
+	}
+
+	rec, response = doRequest(router, ownerId, http.MethodGet, fmt.Sprintf("/wallets/%d", walletId), nil)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, "Private", data(response)["name"])
+	assert.Equal(t, "45", data(response)["currentBalance"])
+	rec, response = doRequest(router, ownerId, http.MethodGet, "/wallets", nil)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Len(t, response["data"].([]any), 1)
+}
+
+func TestActingRolesMayAccessAnyUser(t *testing.T) {
+	const ownerId = 16
+
+	rec, response := doRequest(router, ownerId, http.MethodPost, "/wallets", map[string]any{
+		"name":     "Savings",
+		"currency": "EUR",
+	})
+	assert.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	wallet := fmt.Sprintf("/users/%d/wallets/%d", ownerId, uint64(data(response)["id"].(float64)))
+
+	for name, claims := range map[string]jwt.MapClaims{
+		"admin":                   {"sub": 1, "role": "admin"},
+		"service without sub":     {"role": "service"},
+		"owner with string sub":   {"sub": "16"},
+		"owner with unknown role": {"sub": ownerId, "role": "user"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			rec, response := doRequestWithToken(router, tokenWithClaims(claims), http.MethodGet, wallet, nil)
+			assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+			assert.Equal(t, "Savings", data(response)["name"])
+		})
+	}
+
+	rec, response = doRequestWithToken(router, tokenWithClaims(jwt.MapClaims{"role": "service"}), http.MethodPatch, wallet, map[string]any{"name": "Rainy day"})
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, "Rainy day", data(response)["name"])
+	assert.Equal(t, float64(ownerId), data(response)["userId"])
+
+	rec, _ = doRequestWithToken(router, tokenWithClaims(jwt.MapClaims{"role": "admin"}), http.MethodGet, "/users/abc/wallets", nil)
+	assert.Equal(t, http.StatusBadRequest, rec.Code, rec.Body.String())
+
+	rec, _ = doRequestWithToken(router, tokenWithClaims(jwt.MapClaims{"role": "service"}), http.MethodPost, "/admin/exchange-rates", nil)
+	assert.Equal(t, http.StatusForbidden, rec.Code, rec.Body.String())
+}
@@ -0,0 +1,96 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/khivuksergey/portmonetka.wallet/config"
+	"github.com/khivuksergey/portmonetka.wallet/internal/adapter/storage/gorm"
+	"github.com/khivuksergey/portmonetka.wallet/internal/adapter/storage/gorm/migration"
+	"github.com/khivuksergey/portmonetka.wallet/internal/core/service"
+	walletHttp "github.com/khivuksergey/portmonetka.wallet/internal/http"
+	"github.com/khivuksergey/webserver"
+	"github.com/khivuksergey/webserver/logger"
+	"github.com/spf13/viper"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"time"
+)
+
+const jwtSecret = "e2e-test-secret"
+
+// newTestRouter builds the full HTTP stack on top of a migrated in-memory SQLite database.
+func newTestRouter() (http.Handler, func() error) {
+	viper.Set("JWT_SECRET", jwtSecret)
+
+	dbConfig := config.DBConfig{
+		Driver:           gorm.DriverSqlite,
+		ConnectionString: ":memory:",
+		MigrationMode:    migration.ModeAuto,
+		QueryTimeout:     5 * time.Second,
+	}
+	db := gorm.NewDbManager(dbConfig)
+
+	cfg := &config.Configuration{
+		Router: webserver.DefaultRouterConfig,
+		DB:     dbConfig,
+	}
+	services := service.NewServiceManager(db.InitRepositoryManager())
+	log := logger.Default.SetLevel(logger.GetLogLevelFromString("ERROR"))
+
+	return walletHttp.NewRouter(cfg, services, log), db.Close
+}
+
+func doRequest(router http.Handler, userId uint64, method, path string, body any) (*httptest.ResponseRecorder, map[string]any) {
+	return doRequestWithToken(router, token(userId), method, fmt.Sprintf("/users/%d%s", userId, path), body)
+}
+
+// doRequestWithToken sends the request to the full target path, without the Authorization header if the token is empty.
+func doRequestWithToken(router http.Handler, bearer, method, target string, body any) (*httptest.ResponseRecorder, map[string]any) {
+	return doRequestWithHeaders(router, bearer, method, target, body, nil)
+}
+
+// doRequestWithHeaders is doRequestWithToken setting the additional request headers.
+func doRequestWithHeaders(router http.Handler, bearer, method, target string, body any, headers map[string]string) (*httptest.ResponseRecorder, map[string]any) {
+	var reader io.Reader
+	if body != nil {
+		payload, _ := json.Marshal(body)
+		reader = bytes.NewReader(payload)
+	}
+	req := httptest.NewRequest(method, target, reader)
+	req.Header.Set("Content-Type", "application/json")
+	if bearer != "" {
+		req.Header.Set("Authorization", "Bearer "+bearer)
+	}
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	var response map[string]any
+	_ = json.Unmarshal(rec.Body.Bytes(), &response)
+	return rec, response
+}
+
+func token(userId uint64) string {
+	return tokenWithClaims(jwt.MapClaims{"sub": userId})
+}
+
+func tokenWithClaims(claims jwt.MapClaims) string {
+	signed, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(jwtSecret))
+	return signed
+}
+
+func data(response map[string]any) map[string]any {
+	d, _ := response["data"].(map[string]any)
+	return d
+}
+
+func stringOrEmpty(value any) string {
+	s, _ := value.(string)
+	return s
+}
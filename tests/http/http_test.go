@@ -0,0 +1,671 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/khivuksergey/portmonetka.common"
+	"github.com/khivuksergey/portmonetka.wallet/internal/adapter/storage/gorm/repo/mock"
+	"github.com/khivuksergey/portmonetka.wallet/internal/core/port/repository"
+	"github.com/khivuksergey/portmonetka.wallet/internal/core/service"
+	"github.com/khivuksergey/portmonetka.wallet/internal/handler"
+	"github.com/khivuksergey/webserver/logger"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+var router http.Handler
+
+func TestMain(m *testing.M) {
+	var closeDb func() error
+	router, closeDb = newTestRouter()
+	code := m.Run()
+	_ = closeDb()
+	os.Exit(code)
+}
+
+func TestWalletLifecycle(t *testing.T) {
+	const userId = 1
+
+	rec, response := doRequest(router, userId, http.MethodPost, "/wallets", map[string]any{
+		"name":          "Cash",
+		"currency":      "USD",
+		"initialAmount": "100.10",
+	})
+	assert.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	walletId := uint64(data(response)["id"].(float64))
+
+	rec, _ = doRequest(router, userId, http.MethodPost, fmt.Sprintf("/wallets/%d/transactions", walletId), map[string]any{
+		"amount":    "0.2",
+		"direction": "in",
+	})
+	assert.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+
+	rec, _ = doRequest(router, userId, http.MethodPost, fmt.Sprintf("/wallets/%d/transactions", walletId), map[string]any{
+		"amount":    "50",
+		"direction": "out",
+	})
+	assert.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+
+	rec, response = doRequest(router, userId, http.MethodGet, fmt.Sprintf("/wallets/%d", walletId), nil)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, "50.3", data(response)["currentBalance"])
+
+	rec, _ = doRequest(router, userId, http.MethodPatch, fmt.Sprintf("/wallets/%d", walletId), map[string]any{
+		"name": "Pocket",
+	})
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	rec, _ = doRequest(router, userId, http.MethodDelete, fmt.Sprintf("/wallets/%d", walletId), nil)
+	assert.Equal(t, http.StatusNoContent, rec.Code, rec.Body.String())
+
+	rec, response = doRequest(router, userId, http.MethodGet, "/wallets/trash", nil)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Len(t, response["data"], 1)
+
+	rec, _ = doRequest(router, userId, http.MethodPost, fmt.Sprintf("/wallets/trash/%d/restore", walletId), nil)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+}
+
+func TestWalletList_SortedByBalance(t *testing.T) {
+	const userId = 2
+
+	for i, amount := range []string{"10.5", "2.25", "7"} {
+		rec, _ := doRequest(router, userId, http.MethodPost, "/wallets", map[string]any{
+			"name":          fmt.Sprintf("Wallet %d", i),
+			"currency":      "EUR",
+			"initialAmount": amount,
+		})
+		assert.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	}
+
+	rec, response := doRequest(router, userId, http.MethodGet, "/wallets?sort=balance&limit=2", nil)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	page := response["data"].([]any)
+	assert.Len(t, page, 2)
+	assert.Equal(t, "2.25", page[0].(map[string]any)["currentBalance"])
+	assert.Equal(t, "7", page[1].(map[string]any)["currentBalance"])
+
+	rec, response = doRequest(router, userId, http.MethodGet, "/wallets?sort=balance&limit=2&cursor="+response["nextCursor"].(string), nil)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	page = response["data"].([]any)
+	assert.Len(t, page, 1)
+	assert.Equal(t, "10.5", page[0].(map[string]any)["currentBalance"])
+}
+
+func TestTransfer(t *testing.T) {
+	const userId = 3
+
+	var ids []uint64
+	for _, currency := range []string{"USD", "EUR"} {
+		rec, response := doRequest(router, userId, http.MethodPost, "/wallets", map[string]any{
+			"name":          currency + " wallet",
+			"currency":      currency,
+			"initialAmount": "100",
+		})
+		assert.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+		ids = append(ids, uint64(data(response)["id"].(float64)))
+	}
+
+	rec, _ := doRequest(router, userId, http.MethodPost, "/transfers", map[string]any{
+		"sourceWalletId": ids[0],
+		"targetWalletId": ids[1],
+		"amount":         "10",
+		"rate":           "0.9",
+	})
+	assert.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+
+	_, response := doRequest(router, userId, http.MethodGet, fmt.Sprintf("/wallets/%d", ids[1]), nil)
+	assert.Equal(t, "109", data(response)["currentBalance"])
+}
+
+func TestForeignUserIsRejected(t *testing.T) {
+	rec, _ := doRequest(router, 4, http.MethodPost, "/wallets", map[string]any{
+		"name":          "Mine",
+		"currency":      "USD",
+		"initialAmount": "1",
+	})
+	assert.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+
+	rec, _ = doRequest(router, 5, http.MethodGet, "/wallets/1", nil)
+	assert.NotEqual(t, http.StatusOK, rec.Code)
+}
+
+func TestGetCurrencies(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/currencies", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `{"code":"JPY","numericCode":"392","name":"Yen","minorUnits":0}`)
+	assert.Contains(t, rec.Body.String(), `{"code":"BTC","name":"Bitcoin","minorUnits":8}`)
+}
+
+func TestCreateWallet_InvalidCurrency(t *testing.T) {
+	for _, currency := range []string{"ABC", "usd1"} {
+		rec, _ := doRequest(router, 6, http.MethodPost, "/wallets", map[string]any{
+			"name":          "Wallet " + currency,
+			"currency":      currency,
+			"initialAmount": "1",
+		})
+		assert.Equal(t, http.StatusBadRequest, rec.Code, rec.Body.String())
+	}
+}
+
+func TestWalletsSummary(t *testing.T) {
+	const userId = 7
+	rates := "date,base,quote,rate\n2024-05-17,EUR,USD,1.25\n2024-05-17,EUR,RUB,100\n"
+
+	importRates := func(claims jwt.MapClaims) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/admin/exchange-rates", strings.NewReader(rates))
+		req.Header.Set("Content-Type", "text/csv")
+		req.Header.Set("Authorization", "Bearer "+tokenWithClaims(claims))
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+	rec := importRates(jwt.MapClaims{"sub": userId})
+	assert.Equal(t, http.StatusForbidden, rec.Code, rec.Body.String())
+	rec = importRates(jwt.MapClaims{"sub": userId, "role": "admin"})
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	for _, wallet := range []map[string]any{
+		{"name": "Dollars", "currency": "USD", "initialAmount": "125"},
+		{"name": "Rubles", "currency": "RUB", "initialAmount": "5000"},
+	} {
+		rec, _ = doRequest(router, userId, http.MethodPost, "/wallets", wallet)
+		assert.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	}
+
+	rec, response := doRequest(router, userId, http.MethodGet, "/wallets/summary?base=EUR&date=2024-05-20", nil)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, "150", data(response)["total"])
+
+	rec, _ = doRequest(router, userId, http.MethodGet, "/wallets/summary?base=EUR&date=2024-05-01", nil)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code, rec.Body.String())
+}
+
+func TestPreferences(t *testing.T) {
+	const userId = 8
+
+	rec, response := doRequest(router, userId, http.MethodGet, "/preferences", nil)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, "en", data(response)["locale"])
+	assert.Equal(t, float64(1), data(response)["monthStartDay"])
+
+	rec, _ = doRequest(router, userId, http.MethodGet, "/wallets/summary", nil)
+	assert.Equal(t, http.StatusBadRequest, rec.Code, rec.Body.String())
+
+	rec, response = doRequest(router, userId, http.MethodPost, "/wallets", map[string]any{
+		"name":          "Salary",
+		"currency":      "USD",
+		"initialAmount": "10",
+	})
+	assert.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	walletId := data(response)["id"]
+
+	rec, _ = doRequest(router, userId, http.MethodPut, "/preferences", map[string]any{"baseCurrency": "XYZ"})
+	assert.Equal(t, http.StatusBadRequest, rec.Code, rec.Body.String())
+	rec, _ = doRequest(router, userId, http.MethodPut, "/preferences", map[string]any{"monthStartDay": 31})
+	assert.Equal(t, http.StatusBadRequest, rec.Code, rec.Body.String())
+
+	rec, response = doRequest(router, userId, http.MethodPut, "/preferences", map[string]any{
+		"baseCurrency":    "usd",
+		"defaultWalletId": walletId,
+		"locale":          "ru-RU",
+		"monthStartDay":   10,
+	})
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, "USD", data(response)["baseCurrency"])
+	assert.Equal(t, float64(10), data(response)["monthStartDay"])
+	assert.Equal(t, walletId, data(response)["defaultWalletId"])
+
+	rec, response = doRequest(router, userId, http.MethodGet, "/wallets/summary", nil)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, "USD", data(response)["baseCurrency"])
+	assert.Equal(t, "10", data(response)["total"])
+
+	rec, response = doRequest(router, userId, http.MethodPost, "/wallets", map[string]any{
+		"name":     "Deposit",
+		"currency": "USD",
+	})
+	assert.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	depositId := data(response)["id"]
+
+	rec, response = doRequest(router, userId, http.MethodPost, "/transfers", map[string]any{
+		"targetWalletId": depositId,
+		"amount":         "4",
+	})
+	assert.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	assert.Equal(t, walletId, data(response)["sourceWalletId"])
+
+	rec, _ = doRequest(router, userId, http.MethodPatch, fmt.Sprintf("/wallets/%v", walletId), map[string]any{})
+	assert.Equal(t, http.StatusBadRequest, rec.Code, rec.Body.String())
+	assert.Contains(t, rec.Body.String(), "необходимо указать хотя бы одно поле", "the preferred locale is the Accept-Language fallback")
+
+	rec, _ = doRequest(router, userId, http.MethodDelete, fmt.Sprintf("/wallets/%v", walletId), nil)
+	assert.Equal(t, http.StatusNoContent, rec.Code, rec.Body.String())
+	rec, response = doRequest(router, userId, http.MethodGet, "/preferences", nil)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Nil(t, data(response)["defaultWalletId"])
+}
+
+func TestWalletTypes(t *testing.T) {
+	const userId = 9
+
+	rec, _ := doRequest(router, userId, http.MethodPost, "/wallets", map[string]any{
+		"name":     "Card",
+		"currency": "EUR",
+		"type":     "credit",
+	})
+	assert.Equal(t, http.StatusBadRequest, rec.Code, rec.Body.String())
+
+	rec, response := doRequest(router, userId, http.MethodPost, "/wallets", map[string]any{
+		"name":         "Card",
+		"currency":     "EUR",
+		"type":         "credit_card",
+		"creditLimit":  "1500",
+		"statementDay": 20,
+	})
+	assert.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	assert.Equal(t, "1500", data(response)["creditLimit"])
+
+	rec, _ = doRequest(router, userId, http.MethodPost, "/wallets", map[string]any{"name": "Pocket", "currency": "EUR"})
+	assert.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+
+	rec, response = doRequest(router, userId, http.MethodPost, "/wallets", map[string]any{
+		"name":          "Cold storage",
+		"currency":      "ETH",
+		"type":          "crypto",
+		"initialAmount": "0.000000000000000001",
+	})
+	assert.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	assert.Equal(t, "ETH", data(response)["currency"])
+
+	rec, response = doRequest(router, userId, http.MethodGet, "/wallets?type=credit_card", nil)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	wallets := response["data"].([]any)
+	assert.Len(t, wallets, 1)
+	assert.Equal(t, "credit_card", wallets[0].(map[string]any)["type"])
+	assert.Equal(t, float64(20), wallets[0].(map[string]any)["statementDay"])
+}
+
+func TestDebitBelowBalanceFloor(t *testing.T) {
+	const userId = 10
+
+	rec, response := doRequest(router, userId, http.MethodPost, "/wallets", map[string]any{
+		"name":          "Debit card",
+		"currency":      "USD",
+		"type":          "debit_card",
+		"initialAmount": "10",
+	})
+	assert.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	walletId := uint64(data(response)["id"].(float64))
+	transactionsPath := fmt.Sprintf("/wallets/%d/transactions", walletId)
+
+	rec, response = doRequest(router, userId, http.MethodPost, transactionsPath, map[string]any{
+		"amount":    "10.01",
+		"direction": "out",
+	})
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code, rec.Body.String())
+	assert.Equal(t, "application/problem+json", rec.Header().Get("Content-Type"))
+	assert.Equal(t, "insufficient_funds", response["code"])
+	assert.Contains(t, response["detail"], "would be -0.01, below the permitted 0")
+
+	rec, _ = doRequest(router, userId, http.MethodPatch, fmt.Sprintf("/wallets/%d", walletId), map[string]any{"creditLimit": "100"})
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	rec, _ = doRequest(router, userId, http.MethodPost, transactionsPath, map[string]any{
+		"amount":    "110",
+		"direction": "out",
+	})
+	assert.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+
+	rec, response = doRequest(router, userId, http.MethodPost, transactionsPath, map[string]any{
+		"amount":    "0.01",
+		"direction": "out",
+	})
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code, rec.Body.String())
+	assert.Equal(t, "insufficient_funds", response["code"])
+	rec, response = doRequest(router, userId, http.MethodPost, "/wallets", map[string]any{
+		"name":          "Loan",
+		"currency":      "USD",
+		"type":          "loan",
+		"allowNegative": true,
+	})
+	assert.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	rec, _ = doRequest(router, userId, http.MethodPost, fmt.Sprintf("/wallets/%d/transactions", uint64(data(response)["id"].(float64))), map[string]any{
+		"amount":    "1000000",
+		"direction": "out",
+	})
+	assert.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+}
+
+func TestSharedWallet(t *testing.T) {
+	const ownerId, memberId = 11, 12
+
+	rec, response := doRequest(router, ownerId, http.MethodPost, "/wallets", map[string]any{
+		"name":          "Household",
+		"currency":      "EUR",
+		"initialAmount": "100",
+	})
+	assert.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	walletId := uint64(data(response)["id"].(float64))
+	walletPath := fmt.Sprintf("/wallets/%d", walletId)
+
+	rec, response = doRequest(router, ownerId, http.MethodPost, walletPath+"/members", map[string]any{
+		"inviteeId": memberId,
+		"role":      "viewer",
+	})
+	assert.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	memberPath := fmt.Sprintf("%s/members/%d", walletPath, uint64(data(response)["id"].(float64)))
+
+	rec, _ = doRequest(router, memberId, http.MethodGet, walletPath, nil)
+	assert.Equal(t, http.StatusForbidden, rec.Code, rec.Body.String())
+
+	rec, response = doRequest(router, memberId, http.MethodGet, "/invitations", nil)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	invitations := response["data"].([]any)
+	assert.Len(t, invitations, 1)
+	invitationId := uint64(invitations[0].(map[string]any)["id"].(float64))
+
+	rec, _ = doRequest(router, memberId, http.MethodPost, fmt.Sprintf("/invitations/%d/accept", invitationId), nil)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	rec, response = doRequest(router, memberId, http.MethodGet, "/wallets", nil)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Len(t, response["data"].([]any), 1)
+
+	rec, _ = doRequest(router, memberId, http.MethodGet, walletPath+"/transactions", nil)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	rec, _ = doRequest(router, memberId, http.MethodPatch, walletPath, map[string]any{"name": "Ours"})
+	assert.Equal(t, http.StatusForbidden, rec.Code, rec.Body.String())
+	rec, _ = doRequest(router, memberId, http.MethodPost, walletPath+"/transactions", map[string]any{
+		"amount":    "5",
+		"direction": "out",
+	})
+	assert.Equal(t, http.StatusForbidden, rec.Code, rec.Body.String())
+
+	rec, _ = doRequest(router, memberId, http.MethodDelete, memberPath, nil)
+	assert.Equal(t, http.StatusNoContent, rec.Code, rec.Body.String())
+	rec, response = doRequest(router, ownerId, http.MethodPost, walletPath+"/members", map[string]any{
+		"inviteeId": memberId,
+		"role":      "editor",
+	})
+	assert.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	rec, _ = doRequest(router, memberId, http.MethodPost, fmt.Sprintf("/invitations/%d/accept", uint64(data(response)["id"].(float64))), nil)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	rec, _ = doRequest(router, memberId, http.MethodPost, walletPath+"/transactions", map[string]any{
+		"amount":    "5",
+		"direction": "out",
+	})
+	assert.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	rec, _ = doRequest(router, memberId, http.MethodPost, walletPath+"/members", map[string]any{
+		"inviteeId": 13,
+		"role":      "viewer",
+	})
+	assert.Equal(t, http.StatusForbidden, rec.Code, rec.Body.String())
+	rec, _ = doRequest(router, memberId, http.MethodDelete, walletPath, nil)
+	assert.Equal(t, http.StatusForbidden, rec.Code, rec.Body.String())
+
+	rec, response = doRequest(router, ownerId, http.MethodGet, walletPath, nil)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, "95", data(response)["currentBalance"])
+}
+
+func TestWalletProblemResponses(t *testing.T) {
+	const userId, otherId = 17, 18
+
+	rec, response := doRequest(router, userId, http.MethodPost, "/wallets", map[string]any{"name": "Cash", "currency": "USD"})
+	assert.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	walletPath := fmt.Sprintf("/wallets/%d", uint64(data(response)["id"].(float64)))
+
+	for _, tc := range []struct {
+		name   string
+		userId uint64
+		method string
+		path   string
+		body   any
+		status int
+		code   string
+		field  string
+	}{
+		{"duplicate name", userId, http.MethodPost, "/wallets", map[string]any{"name": "Cash", "currency": "EUR"}, http.StatusConflict, "wallet_already_exists", "name"},
+		{"duplicate name in other case", userId, http.MethodPost, "/wallets", map[string]any{"name": "CASH", "currency": "EUR"}, http.StatusConflict, "wallet_already_exists", "name"},
+		{"missing wallet", userId, http.MethodGet, "/wallets/999999", nil, http.StatusNotFound, "wallet_not_found", ""},
+		{"foreign wallet", otherId, http.MethodGet, walletPath, nil, http.StatusForbidden, "wallet_forbidden", ""},
+		{"foreign wallet update", otherId, http.MethodPatch, walletPath, map[string]any{"name": "Mine"}, http.StatusForbidden, "wallet_forbidden", ""},
+		{"invalid field", userId, http.MethodPost, "/wallets", map[string]any{"name": "Pocket", "currency": "XXX"}, http.StatusBadRequest, "invalid_input", "currency"},
+		{"invalid cursor", userId, http.MethodGet, "/wallets?cursor=broken", nil, http.StatusBadRequest, "invalid_cursor", "cursor"},
+		{"missing deleted wallet", userId, http.MethodPost, "/wallets/trash/999999/restore", nil, http.StatusNotFound, "deleted_wallet_not_found", ""},
+		{"missing transaction", userId, http.MethodGet, walletPath + "/transactions/999999", nil, http.StatusNotFound, "transaction_not_found", ""},
+		{"foreign wallet transactions", otherId, http.MethodGet, walletPath + "/transactions", nil, http.StatusForbidden, "wallet_forbidden", ""},
+		{"missing invitation", userId, http.MethodPost, "/invitations/999999/accept", nil, http.StatusNotFound, "invitation_not_found", ""},
+		{"invalid preferences", userId, http.MethodPut, "/preferences", map[string]any{"monthStartDay": 31}, http.StatusBadRequest, "invalid_input", "monthStartDay"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			rec, response := doRequest(router, tc.userId, tc.method, tc.path, tc.body)
+			assert.Equal(t, tc.status, rec.Code, rec.Body.String())
+			assert.Equal(t, "application/problem+json", rec.Header().Get("Content-Type"))
+			assert.Equal(t, float64(tc.status), response["status"])
+			assert.Equal(t, http.StatusText(tc.status), response["title"])
+			assert.Equal(t, tc.code, response["code"])
+			assert.Equal(t, tc.field, stringOrEmpty(response["field"]))
+			assert.NotEmpty(t, response["detail"])
+			assert.NotEmpty(t, response["request_uuid"])
+		})
+	}
+}
+
+func TestWalletProblemResponses_InvalidEntityTag(t *testing.T) {
+	const userId = 17
+
+	rec, response := doRequestWithHeaders(router, token(userId), http.MethodPatch, fmt.Sprintf("/users/%d/wallets/1", userId),
+		map[string]any{"name": "Pocket"}, map[string]string{"If-Match": `"garbage"`})
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code, rec.Body.String())
+	assert.Equal(t, "invalid_entity_tag", response["code"])
+	assert.Equal(t, "If-Match", response["field"])
+}
+
+func TestWalletIfMatch(t *testing.T) {
+	const userId = 21
+
+	rec, response := doRequest(router, userId, http.MethodPost, "/wallets", map[string]any{"name": "Cash", "currency": "USD"})
+	if !assert.Equal(t, http.StatusCreated, rec.Code, rec.Body.String()) {
+		return
+	}
+	walletPath := fmt.Sprintf("/users/%d/wallets/%d", userId, uint64(data(response)["id"].(float64)))
+	rec, _ = doRequestWithHeaders(router, token(userId), http.MethodGet, walletPath, nil, nil)
+	etag := rec.Header().Get("ETag")
+	if !assert.NotEmpty(t, etag) {
+		return
+	}
+
+	rec, response = doRequestWithHeaders(router, token(userId), http.MethodPatch, walletPath,
+		map[string]any{"name": "Pocket"}, map[string]string{"If-Match": "W/" + etag})
+	assert.Equal(t, http.StatusPreconditionFailed, rec.Code, rec.Body.String())
+	assert.Equal(t, "wallet_version_mismatch", response["code"])
+
+	rec, response = doRequestWithHeaders(router, token(userId), http.MethodPatch, walletPath,
+		map[string]any{"name": "Pocket"}, map[string]string{"If-Match": `"99-00000000", W/` + etag})
+	assert.Equal(t, http.StatusPreconditionFailed, rec.Code, rec.Body.String())
+	assert.Equal(t, "wallet_version_mismatch", response["code"])
+
+	rec, response = doRequestWithHeaders(router, token(userId), http.MethodPatch, walletPath,
+		map[string]any{"name": "Pocket"}, map[string]string{"If-Match": `"99-00000000", ` + etag})
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, "Pocket", data(response)["name"])
+	updatedETag := rec.Header().Get("ETag")
+
+	rec, _ = doRequestWithHeaders(router, token(userId), http.MethodDelete, walletPath,
+		map[string]any{}, map[string]string{"If-Match": etag + ", " + updatedETag})
+	assert.Equal(t, http.StatusNoContent, rec.Code, rec.Body.String())
+}
+
+func TestWalletProblemResponses_UnexpectedError(t *testing.T) {
+	const userId = 17
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+
+	mockWalletRepository := mock.NewMockWalletRepository(ctl)
+	mockWalletRepository.
+		EXPECT().
+		GetWalletsByUserId(gomock.Any(), uint64(userId), gomock.Any(), gomock.Any()).
+		Return(nil, errors.New("pq: connection to 10.0.0.5 refused"))
+	services := service.NewServiceManager(&repository.Manager{Wallet: mockWalletRepository})
+	walletHandler := handler.NewWalletHandler(services, logger.Default)
+
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, fmt.Sprintf("/users/%d/wallets", userId), nil), rec)
+	c.Set(common.RequestUuidKey, "request-uuid")
+	c.Set("userId", uint64(userId))
+
+	assert.NoError(t, walletHandler.GetWallets(c))
+
+	var response map[string]any
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, http.StatusInternalServerError, rec.Code, rec.Body.String())
+	assert.Equal(t, "application/problem+json", rec.Header().Get("Content-Type"))
+	assert.Equal(t, "internal_error", response["code"])
+	assert.NotContains(t, response["detail"], "10.0.0.5")
+	assert.Equal(t, "request-uuid", response["request_uuid"])
+}
+
+func TestTransactionProblemResponses_UnexpectedError(t *testing.T) {
+	const userId = 17
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+
+	mockWalletRepository := mock.NewMockWalletRepository(ctl)
+	mockWalletRepository.
+		EXPECT().
+		GetWalletById(gomock.Any(), uint64(1)).
+		Return(nil, errors.New("pq: connection to 10.0.0.5 refused"))
+	services := service.NewServiceManager(&repository.Manager{Wallet: mockWalletRepository})
+	transactionHandler := handler.NewTransactionHandler(services, logger.Default)
+
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, fmt.Sprintf("/users/%d/wallets/1/transactions", userId), nil), rec)
+	c.SetParamNames("walletId")
+	c.SetParamValues("1")
+	c.Set(common.RequestUuidKey, "request-uuid")
+	c.Set("userId", uint64(userId))
+
+	assert.NoError(t, transactionHandler.GetTransactions(c))
+
+	var response map[string]any
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, http.StatusInternalServerError, rec.Code, rec.Body.String())
+	assert.Equal(t, "application/problem+json", rec.Header().Get("Content-Type"))
+	assert.Equal(t, "internal_error", response["code"])
+	assert.NotContains(t, response["detail"], "10.0.0.5")
+}
+
+func TestValidationMessagesAreTranslated(t *testing.T) {
+	const userId = 19
+
+	rec, response := doRequest(router, userId, http.MethodPost, "/wallets", map[string]any{"name": "Cash", "currency": "USD"})
+	assert.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	walletPath := fmt.Sprintf("/users/%d/wallets/%d", userId, uint64(data(response)["id"].(float64)))
+
+	for _, tc := range []struct {
+		name           string
+		acceptLanguage string
+		method         string
+		path           string
+		body           any
+		field          string
+		message        string
+	}{
+		{"empty update", "", http.MethodPatch, walletPath, map[string]any{}, "", "at least one field must be provided"},
+		{"empty update in Russian", "ru-RU,ru;q=0.9,en;q=0.8", http.MethodPatch, walletPath, map[string]any{}, "", "необходимо указать хотя бы одно поле"},
+		{"missing name in Russian", "ru", http.MethodPost, fmt.Sprintf("/users/%d/wallets", userId), map[string]any{"currency": "USD"}, "name", "name обязательное поле"},
+		{"invalid currency in English", "de, en;q=0.5", http.MethodPost, fmt.Sprintf("/users/%d/wallets", userId), map[string]any{"name": "Pocket", "currency": "XXX"}, "currency", "currency must be a supported currency code"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			rec, response := doRequestWithHeaders(router, token(userId), tc.method, tc.path, tc.body, map[string]string{"Accept-Language": tc.acceptLanguage})
+			assert.Equal(t, http.StatusBadRequest, rec.Code, rec.Body.String())
+			assert.Equal(t, "invalid_input", response["code"])
+			assert.Equal(t, tc.field, stringOrEmpty(response["field"]))
+			errors := response["errors"].([]any)
+			assert.Len(t, errors, 1)
+			assert.Equal(t, tc.field, stringOrEmpty(errors[0].(map[string]any)["field"]))
+			assert.Equal(t, tc.message, errors[0].(map[string]any)["message"])
+		})
+	}
+}
+
+func TestWalletHistory(t *testing.T) {
+	const userId, adminId = 20, 1
+
+	rec, response := doRequest(router, userId, http.MethodPost, "/wallets", map[string]any{
+		"name":          "Cash",
+		"currency":      "USD",
+		"initialAmount": "100.10",
+	})
+	assert.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	walletId := uint64(data(response)["id"].(float64))
+	wallet := fmt.Sprintf("/users/%d/wallets/%d", userId, walletId)
+
+	rec, response = doRequestWithToken(router, tokenWithClaims(jwt.MapClaims{"sub": adminId, "role": "admin"}), http.MethodPatch, wallet, map[string]any{
+		"currency":     "EUR",
+		"type":         "credit_card",
+		"creditLimit":  "500",
+		"statementDay": 15,
+	})
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	updateRequestUuid := response["request_uuid"]
+
+	rec, _ = doRequest(router, userId, http.MethodDelete, fmt.Sprintf("/wallets/%d", walletId), nil)
+	assert.Equal(t, http.StatusNoContent, rec.Code, rec.Body.String())
+	rec, _ = doRequest(router, userId, http.MethodGet, fmt.Sprintf("/wallets/%d/history", walletId), nil)
+	assert.Equal(t, http.StatusNotFound, rec.Code, rec.Body.String())
+	rec, _ = doRequest(router, userId, http.MethodPost, fmt.Sprintf("/wallets/trash/%d/restore", walletId), nil)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	rec, response = doRequest(router, userId, http.MethodGet, fmt.Sprintf("/wallets/%d/history", walletId), nil)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	history := response["data"].([]any)
+	if !assert.Len(t, history, 4) {
+		return
+	}
+	records := make([]map[string]any, len(history))
+	for i, record := range history {
+		records[i] = record.(map[string]any)
+		assert.Equal(t, float64(walletId), records[i]["walletId"])
+		assert.NotEmpty(t, records[i]["requestUuid"])
+	}
+
+	assert.Equal(t, "create", records[0]["action"])
+	assert.Equal(t, float64(userId), records[0]["actorId"])
+	assert.Equal(t, map[string]any{"after": "100.1"}, records[0]["changes"].(map[string]any)["initialAmount"])
+
+	assert.Equal(t, "update", records[1]["action"])
+	assert.Equal(t, float64(adminId), records[1]["actorId"])
+	assert.Equal(t, updateRequestUuid, records[1]["requestUuid"])
+	assert.Equal(t, map[string]any{
+		"currency":     map[string]any{"before": "USD", "after": "EUR"},
+		"type":         map[string]any{"before": "cash", "after": "credit_card"},
+		"creditLimit":  map[string]any{"after": "500"},
+		"statementDay": map[string]any{"after": float64(15)},
+	}, records[1]["changes"])
+
+	assert.Equal(t, "delete", records[2]["action"])
+	assert.Equal(t, map[string]any{"before": "EUR"}, records[2]["changes"].(map[string]any)["currency"])
+	assert.Equal(t, "restore", records[3]["action"])
+	restoreChanges := records[3]["changes"].(map[string]any)
+	if assert.Len(t, restoreChanges, 1) && assert.Contains(t, restoreChanges, "deletedAt") {
+		deletedAt := restoreChanges["deletedAt"].(map[string]any)
+		assert.NotEmpty(t, deletedAt["before"])
+		assert.Nil(t, deletedAt["after"])
+	}
+}
@@ -0,0 +1,5 @@
+package model
+
+func ptr[T any](t T) *T {
+	return &t
+}
@@ -0,0 +1,69 @@
+package model
+
+import (
+	"github.com/khivuksergey/portmonetka.wallet/internal/model"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestValidateWalletUpdate_AtLeastOneFieldIsRequired(t *testing.T) {
+	validate := model.GetWalletValidator()
+
+	err := model.TranslateValidationError(validate.Struct(model.WalletUpdateDTO{Id: 1, UserId: 1}), "")
+
+	var invalidFieldsError *model.InvalidFieldsError
+	assert.ErrorAs(t, err, &invalidFieldsError)
+	assert.Equal(t, []model.FieldError{{Message: "at least one field must be provided"}}, invalidFieldsError.Fields)
+	assert.NoError(t, validate.Struct(model.WalletUpdateDTO{AllowNegative: ptr(false)}))
+}
+
+func TestTranslateValidationError_AcceptLanguage(t *testing.T) {
+	validate := model.GetWalletValidator()
+	walletCreateDTO := model.WalletCreateDTO{Name: "Cash", Currency: "ABC", Type: "wallet"}
+
+	for acceptLanguage, expected := range map[string][]model.FieldError{
+		"": {
+			{Field: "currency", Message: "currency must be a supported currency code"},
+			{Field: "type", Message: "type must be one of [cash debit_card credit_card savings loan investment crypto]"},
+		},
+		"ru-RU,ru;q=0.9": {
+			{Field: "currency", Message: "currency должен быть кодом поддерживаемой валюты"},
+			{Field: "type", Message: "type должен быть одним из [cash debit_card credit_card savings loan investment crypto]"},
+		},
+		"fr": {
+			{Field: "currency", Message: "currency must be a supported currency code"},
+			{Field: "type", Message: "type must be one of [cash debit_card credit_card savings loan investment crypto]"},
+		},
+	} {
+		t.Run(acceptLanguage, func(t *testing.T) {
+			err := model.TranslateValidationError(validate.Struct(walletCreateDTO), acceptLanguage)
+
+			var invalidFieldsError *model.InvalidFieldsError
+			assert.ErrorAs(t, err, &invalidFieldsError)
+			assert.Equal(t, expected, invalidFieldsError.Fields)
+		})
+	}
+}
+
+func TestValidateWalletMemberInvite_OwnerRoleIsNotAllowed(t *testing.T) {
+	validate := model.GetWalletValidator()
+
+	assert.Error(t, validate.Struct(model.WalletMemberInviteDTO{InviteeId: 2, Role: "owner"}))
+	assert.NoError(t, validate.Struct(model.WalletMemberInviteDTO{InviteeId: 2, Role: "editor"}))
+	assert.NoError(t, validate.Struct(model.WalletMemberInviteDTO{InviteeId: 2, Role: "viewer"}))
+}
+
+func TestValidateWalletUpdate_NameAndDescriptionLength(t *testing.T) {
+	validate := model.GetWalletValidator()
+
+	err := model.TranslateValidationError(validate.Struct(model.WalletUpdateDTO{Name: ptr("ab"), Description: ptr(strings.Repeat("a", 257))}), "")
+
+	var invalidFieldsError *model.InvalidFieldsError
+	if assert.ErrorAs(t, err, &invalidFieldsError) && assert.Len(t, invalidFieldsError.Fields, 2) {
+		assert.Equal(t, "name", invalidFieldsError.Fields[0].Field)
+		assert.Equal(t, "description", invalidFieldsError.Fields[1].Field)
+	}
+	// lengths are counted in characters rather than bytes
+	assert.NoError(t, validate.Struct(model.WalletUpdateDTO{Name: ptr("Кош"), Description: ptr(strings.Repeat("ё", 256))}))
+}